@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	v13 "github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/resource"
+	"github.com/fluxcd/flux/pkg/update"
+)
+
+type approveOpts struct {
+	*rootOpts
+	namespace string
+	workload  string
+	image     string
+	outputOpts
+	cause update.Cause
+}
+
+func newApprove(parent *rootOpts) *approveOpts {
+	return &approveOpts{rootOpts: parent}
+}
+
+func (opts *approveOpts) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "Release updates proposed by automation for a workload.",
+		Long: `
+Release the updates automation has proposed for a workload, as listed
+by list-pending. Updates for other workloads in the same image group
+are released along with them.
+        `,
+		Example: makeExample(
+			"fluxctl approve --workload=default:deployment/foo",
+			"fluxctl approve --workload=default:deployment/foo --image=library/hello",
+		),
+		RunE: opts.RunE,
+	}
+
+	AddOutputFlags(cmd, &opts.outputOpts)
+	AddCauseFlags(cmd, &opts.cause)
+	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Workload namespace")
+	cmd.Flags().StringVarP(&opts.workload, "workload", "w", "", "Workload to approve updates for")
+	cmd.Flags().StringVarP(&opts.image, "image", "i", "", "Approve only the update to this image, given as a name or name:tag")
+	return cmd
+}
+
+func (opts *approveOpts) RunE(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return errorWantedNoArgs
+	}
+	if opts.workload == "" {
+		return newUsageError("-w, --workload is required")
+	}
+
+	ns := getKubeConfigContextNamespaceOrDefault(opts.namespace, "default", opts.Context)
+	id, err := resource.ParseIDOptionalNamespace(ns, opts.workload)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pending, err := opts.API.ListPendingUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	approved, err := selectPendingUpdates(pending, id, opts.image)
+	if err != nil {
+		return err
+	}
+
+	specs := map[resource.ID][]update.ContainerUpdate{}
+	for _, p := range approved {
+		specs[p.WorkloadID] = append(specs[p.WorkloadID], update.ContainerUpdate{
+			Container: p.Container,
+			Current:   p.Current,
+			Target:    p.Target,
+		})
+	}
+
+	fmt.Fprintf(cmd.OutOrStderr(), "Submitting approved release ...\n")
+	jobID, err := opts.API.UpdateManifests(ctx, update.Spec{
+		Type:  update.Containers,
+		Cause: opts.cause,
+		Spec: update.ReleaseContainersSpec{
+			Kind:           update.ReleaseKindExecute,
+			ContainerSpecs: specs,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return await(ctx, cmd.OutOrStdout(), cmd.OutOrStderr(), opts.API, jobID, true, opts.verbosity, opts.Timeout)
+}
+
+// selectPendingUpdates returns the pending updates for the workload,
+// to the image if one is given (as a name, or name and tag), along
+// with the pending updates for the rest of any image group they are
+// in, since those can only be released together.
+func selectPendingUpdates(pending []v13.PendingUpdate, id resource.ID, img string) ([]v13.PendingUpdate, error) {
+	var ref image.Ref
+	if img != "" {
+		var err error
+		if ref, err = image.ParseRef(img); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := map[string]bool{}
+	selected := map[int]bool{}
+	for i, p := range pending {
+		if p.WorkloadID != id {
+			continue
+		}
+		if img != "" && (p.Target.CanonicalName() != ref.CanonicalName() || (ref.Tag != "" && p.Target.Tag != ref.Tag)) {
+			continue
+		}
+		selected[i] = true
+		if p.Group != "" {
+			groups[p.Group] = true
+		}
+	}
+	if len(selected) == 0 {
+		if img != "" {
+			return nil, fmt.Errorf("no pending update to %s for workload %s", img, id)
+		}
+		return nil, fmt.Errorf("no pending updates for workload %s", id)
+	}
+
+	var result []v13.PendingUpdate
+	for i, p := range pending {
+		if selected[i] || groups[p.Group] {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v13 "github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/resource"
+)
+
+func TestSelectPendingUpdates(t *testing.T) {
+	api := resource.MustParseID("default:deployment/api")
+	worker := resource.MustParseID("default:deployment/worker")
+	web := resource.MustParseID("default:deployment/web")
+	pending := []v13.PendingUpdate{
+		{WorkloadID: api, Container: "api", Current: mustParseRef("billing/api:1.0"), Target: mustParseRef("billing/api:1.1"), Group: "billing"},
+		{WorkloadID: api, Container: "proxy", Current: mustParseRef("envoy:1.0"), Target: mustParseRef("envoy:1.2")},
+		{WorkloadID: worker, Container: "worker", Current: mustParseRef("billing/worker:1.0"), Target: mustParseRef("billing/worker:1.1"), Group: "billing"},
+		{WorkloadID: web, Container: "web", Current: mustParseRef("web:2.0"), Target: mustParseRef("web:2.1")},
+	}
+
+	selected, err := selectPendingUpdates(pending, api, "")
+	assert.NoError(t, err)
+	assert.Equal(t, pending[:3], selected)
+
+	// only the image given, but with the rest of its image group
+	selected, err = selectPendingUpdates(pending, api, "billing/api:1.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []v13.PendingUpdate{pending[0], pending[2]}, selected)
+
+	selected, err = selectPendingUpdates(pending, api, "envoy")
+	assert.NoError(t, err)
+	assert.Equal(t, pending[1:2], selected)
+
+	_, err = selectPendingUpdates(pending, api, "envoy:1.1")
+	assert.Error(t, err)
+	_, err = selectPendingUpdates(pending, resource.MustParseID("default:deployment/other"), "")
+	assert.Error(t, err)
+}
@@ -8,8 +8,9 @@ import (
 
 type workloadAutomateOpts struct {
 	*rootOpts
-	namespace string
-	workload  string
+	namespace  string
+	workload   string
+	containers []string
 	outputOpts
 	cause update.Cause
 
@@ -27,6 +28,7 @@ func (opts *workloadAutomateOpts) Command() *cobra.Command {
 		Short: "Turn on automatic deployment for a workload.",
 		Example: makeExample(
 			"fluxctl automate --workload=default:deployment/helloworld",
+			"fluxctl automate --workload=default:deployment/helloworld --container=app",
 		),
 		RunE: opts.RunE,
 	}
@@ -34,6 +36,7 @@ func (opts *workloadAutomateOpts) Command() *cobra.Command {
 	AddCauseFlags(cmd, &opts.cause)
 	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Workload namespace")
 	cmd.Flags().StringVarP(&opts.workload, "workload", "w", "", "Workload to automate")
+	cmd.Flags().StringSliceVar(&opts.containers, "container", nil, "Automate only the container named, rather than the whole workload; may be given more than once")
 
 	// Deprecated
 	cmd.Flags().StringVarP(&opts.controller, "controller", "c", "", "Controller to automate")
@@ -56,6 +59,7 @@ func (opts *workloadAutomateOpts) RunE(cmd *cobra.Command, args []string) error
 		outputOpts: opts.outputOpts,
 		namespace:  ns,
 		workload:   opts.workload,
+		containers: opts.containers,
 		cause:      opts.cause,
 		automate:   true,
 	}
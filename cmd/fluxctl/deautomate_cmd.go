@@ -8,8 +8,9 @@ import (
 
 type workloadDeautomateOpts struct {
 	*rootOpts
-	namespace string
-	workload  string
+	namespace  string
+	workload   string
+	containers []string
 	outputOpts
 	cause update.Cause
 
@@ -27,6 +28,7 @@ func (opts *workloadDeautomateOpts) Command() *cobra.Command {
 		Short: "Turn off automatic deployment for a workload.",
 		Example: makeExample(
 			"fluxctl deautomate --workload=default:deployment/helloworld",
+			"fluxctl deautomate --workload=default:deployment/helloworld --container=app",
 		),
 		RunE: opts.RunE,
 	}
@@ -34,6 +36,7 @@ func (opts *workloadDeautomateOpts) Command() *cobra.Command {
 	AddCauseFlags(cmd, &opts.cause)
 	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Workload namespace")
 	cmd.Flags().StringVarP(&opts.workload, "workload", "w", "", "Workload to deautomate")
+	cmd.Flags().StringSliceVar(&opts.containers, "container", nil, "Leave the container named out of automation, rather than the whole workload; may be given more than once")
 
 	// Deprecated
 	cmd.Flags().StringVarP(&opts.controller, "controller", "c", "", "Controller to deautomate")
@@ -56,6 +59,7 @@ func (opts *workloadDeautomateOpts) RunE(cmd *cobra.Command, args []string) erro
 		outputOpts: opts.outputOpts,
 		namespace:  ns,
 		workload:   opts.workload,
+		containers: opts.containers,
 		cause:      opts.cause,
 		deautomate: true,
 	}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/fluxcd/flux/pkg/registry"
 
+	v13 "github.com/fluxcd/flux/pkg/api/v13"
 	v6 "github.com/fluxcd/flux/pkg/api/v6"
 
 	"github.com/spf13/cobra"
@@ -89,6 +90,7 @@ func outputImagesJson(images []v6.ImageStatus, out io.Writer, opts *imageListOpt
 // outputImagesTab sends the provided ImageStatus info to os.Stdout in tab formatting, honoring limits in opts
 func outputImagesTab(images []v6.ImageStatus, opts *imageListOpts) {
 	out := newTabwriter()
+	now := time.Now()
 
 	if !opts.noHeaders {
 		fmt.Fprintln(out, "WORKLOAD\tCONTAINER\tIMAGE\tCREATED")
@@ -132,7 +134,7 @@ func outputImagesTab(images []v6.ImageStatus, opts *imageListOpts) {
 				var printEllipsis, printLine bool
 				if opts.limit <= 0 || lineCount <= opts.limit {
 					printEllipsis, printLine = false, true
-				} else if container.Current.ID == available.ID {
+				} else if container.Current.ID.WithDigest("") == available.ID {
 					printEllipsis, printLine = lineCount > (opts.limit+1), true
 				}
 				if printEllipsis {
@@ -142,6 +144,13 @@ func outputImagesTab(images []v6.ImageStatus, opts *imageListOpts) {
 					createdAt := ""
 					if !available.CreatedAt.IsZero() {
 						createdAt = available.CreatedAt.Format(time.RFC822)
+						if available.CreatedAtSource != "" {
+							createdAt += fmt.Sprintf(" (%s)", available.CreatedAtSource)
+						}
+					}
+					// too young for automation to use yet
+					if available.Soaking(container.MinAge, now) {
+						createdAt += fmt.Sprintf("  soaking (min age %s)", container.MinAge)
 					}
 					fmt.Fprintf(out, "\t\t%s %s\t%s\n", running, tag, createdAt)
 				}
@@ -186,3 +195,25 @@ func outputWorkloadsTab(workloads []v6.ControllerStatus, opts *workloadListOpts)
 	}
 	w.Flush()
 }
+
+// outputPendingJson sends the pending updates to the io.Writer as JSON
+func outputPendingJson(pending []v13.PendingUpdate, out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	return encoder.Encode(pending)
+}
+
+// outputPendingTab sends the pending updates to STDOUT, formatted with tabs for CLI
+func outputPendingTab(pending []v13.PendingUpdate, opts *pendingListOpts) {
+	w := newTabwriter()
+	if !opts.noHeaders {
+		fmt.Fprintf(w, "WORKLOAD\tCONTAINER\tCURRENT\tPROPOSED\tSINCE\n")
+	}
+	for _, p := range pending {
+		target := p.Target.String()
+		if p.Group != "" {
+			target += fmt.Sprintf(" (image group %s)", p.Group)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.WorkloadID, p.Container, p.Current, target, p.ProposedAt.Local().Format(time.RFC822))
+	}
+	w.Flush()
+}
@@ -50,6 +50,8 @@ fluxctl install --git-url 'git@github.com:<your username>/flux-get-started' --gi
 		"cluster namespace where to install flux")
 	cmd.Flags().BoolVar(&opts.RegistryDisableScanning, "registry-disable-scanning", false,
 		"do not scan container image registries to fill in the registry cache")
+	cmd.Flags().StringVar(&opts.RegistryCache, "registry-cache", "memcached",
+		fmt.Sprintf("where fluxd caches image metadata; %q keeps it in a file on a volume, and leaves out memcached", install.RegistryCacheDisk))
 	cmd.Flags().StringVarP(&opts.outputDir, "output-dir", "o", "", "a directory in which to write individual manifests, rather than printing to stdout")
 	cmd.Flags().BoolVar(&opts.AddSecurityContext, "add-security-context", true, "Ensure security context information is added to the pod specs. Defaults to 'true'")
 
@@ -70,6 +72,11 @@ func (opts *installOpts) RunE(cmd *cobra.Command, args []string) error {
 	if opts.GitEmail == "" {
 		return fmt.Errorf("please supply a valid --git-email argument")
 	}
+	switch opts.RegistryCache {
+	case "memcached", install.RegistryCacheDisk:
+	default:
+		return fmt.Errorf("--registry-cache must be one of memcached or %s", install.RegistryCacheDisk)
+	}
 	opts.TemplateParameters.Namespace = getKubeConfigContextNamespaceOrDefault(opts.Namespace, "default", "")
 	manifests, err := install.FillInTemplates(opts.TemplateParameters)
 	if err != nil {
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+type pendingListOpts struct {
+	*rootOpts
+	noHeaders    bool
+	outputFormat string
+}
+
+func newPendingList(parent *rootOpts) *pendingListOpts {
+	return &pendingListOpts{rootOpts: parent}
+}
+
+func (opts *pendingListOpts) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list-pending",
+		Short:   "Show updates proposed by automation, which wait to be approved.",
+		Example: makeExample("fluxctl list-pending"),
+		RunE:    opts.RunE,
+	}
+	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Don't print headers (default print headers)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output-format", "o", "tab", "Output format (tab or json)")
+	return cmd
+}
+
+func (opts *pendingListOpts) RunE(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return errorWantedNoArgs
+	}
+	if !outputFormatIsValid(opts.outputFormat) {
+		return errorInvalidOutputFormat
+	}
+
+	ctx := context.Background()
+	pending, err := opts.API.ListPendingUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].WorkloadID != pending[j].WorkloadID {
+			return pending[i].WorkloadID.String() < pending[j].WorkloadID.String()
+		}
+		return pending[i].Container < pending[j].Container
+	})
+
+	switch opts.outputFormat {
+	case outputFormatJson:
+		return outputPendingJson(pending, os.Stdout)
+	default:
+		outputPendingTab(pending, opts)
+	}
+	return nil
+}
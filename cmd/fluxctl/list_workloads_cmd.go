@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -97,10 +99,16 @@ func (s workloadStatusByName) Swap(a, b int) {
 func policies(s v6.ControllerStatus) string {
 	var ps []string
 	if s.Automated {
-		ps = append(ps, string(policy.Automated))
+		ps = append(ps, automatedPolicy(s))
+	}
+	if policy.AnyProposed(policySet(s)) {
+		ps = append(ps, containersPolicy(s, policy.AutomatedPropose, policy.GetProposed))
+	}
+	if policy.AnyNotify(policySet(s)) {
+		ps = append(ps, containersPolicy(s, policy.AutomatedNotify, policy.GetNotify))
 	}
 	if s.Locked {
-		ps = append(ps, string(policy.Locked))
+		ps = append(ps, lockedPolicy(s, time.Now()))
 	}
 	if s.Ignore {
 		ps = append(ps, string(policy.Ignore))
@@ -109,6 +117,54 @@ func policies(s v6.ControllerStatus) string {
 	return strings.Join(ps, ",")
 }
 
+// automatedPolicy says which containers of an automated workload are
+// automated, if not all of them; e.g., `automated(app,web)`.
+func automatedPolicy(s v6.ControllerStatus) string {
+	// Older daemons don't report policies, but then all containers
+	// are automated
+	if s.Policies == nil {
+		return string(policy.Automated)
+	}
+	return containersPolicy(s, string(policy.Automated), policy.GetAutomated)
+}
+
+// containersPolicy gives the name, followed by the containers the
+// policy applies to if not all of them; e.g., `propose(web)`.
+func containersPolicy(s v6.ControllerStatus, name string, applies func(policy.Set, string) bool) string {
+	policies := policySet(s)
+	var containers []string
+	for _, c := range s.Containers {
+		if applies(policies, c.Name) {
+			containers = append(containers, c.Name)
+		}
+	}
+	if len(containers) == len(s.Containers) {
+		return name
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(containers, ","))
+}
+
+// lockedPolicy gives how much longer a lock has to run, if it is for
+// a limited time; e.g., `locked(3h12m)`.
+func lockedPolicy(s v6.ControllerStatus, now time.Time) string {
+	if s.LockedUntil.IsZero() {
+		return string(policy.Locked)
+	}
+	remaining := s.LockedUntil.Sub(now).Round(time.Minute)
+	if remaining <= 0 {
+		return fmt.Sprintf("%s(expired)", policy.Locked)
+	}
+	return fmt.Sprintf("%s(%s)", policy.Locked, strings.TrimSuffix(remaining.String(), "0s"))
+}
+
+func policySet(s v6.ControllerStatus) policy.Set {
+	policies := policy.Set{}
+	for p, v := range s.Policies {
+		policies[policy.Policy(p)] = v
+	}
+	return policies
+}
+
 // Extract workloads having its container name equal to containerName
 func filterByContainerName(workloads []v6.ControllerStatus, containerName string) (filteredWorkloads []v6.ControllerStatus) {
 	for _, workload := range workloads {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -54,3 +55,71 @@ func testWorkloads(workloadCount int) []v6.ControllerStatus {
 	}
 	return workloads
 }
+
+func Test_policies(t *testing.T) {
+	containers := []v6.Container{{Name: "app"}, {Name: "sidecar"}}
+	for _, tt := range []struct {
+		name     string
+		status   v6.ControllerStatus
+		expected string
+	}{
+		{
+			name:     "workload automated",
+			status:   v6.ControllerStatus{Containers: containers, Automated: true, Policies: map[string]string{"automated": "true"}},
+			expected: "automated",
+		},
+		{
+			name:     "no policies reported",
+			status:   v6.ControllerStatus{Containers: containers, Automated: true, Locked: true},
+			expected: "automated,locked",
+		},
+		{
+			name:     "container excluded",
+			status:   v6.ControllerStatus{Containers: containers, Automated: true, Policies: map[string]string{"automated": "true", "automated.sidecar": "false"}},
+			expected: "automated(app)",
+		},
+		{
+			name:     "containers automated",
+			status:   v6.ControllerStatus{Containers: containers, Automated: true, Policies: map[string]string{"automated.app": "true", "automated.sidecar": "true"}},
+			expected: "automated",
+		},
+		{
+			name:     "workload proposed",
+			status:   v6.ControllerStatus{Containers: containers, Policies: map[string]string{"automated": "propose"}},
+			expected: "propose",
+		},
+		{
+			name:     "container proposed",
+			status:   v6.ControllerStatus{Containers: containers, Automated: true, Policies: map[string]string{"automated": "true", "automated.sidecar": "propose"}},
+			expected: "automated(app),propose(sidecar)",
+		},
+		{
+			name:     "container notify",
+			status:   v6.ControllerStatus{Containers: containers, Policies: map[string]string{"automated": "propose", "automated.sidecar": "notify"}},
+			expected: "notify(sidecar),propose(app)",
+		},
+		{
+			name:     "not automated",
+			status:   v6.ControllerStatus{Containers: containers, Policies: map[string]string{}},
+			expected: "",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, policies(tt.status))
+		})
+	}
+}
+
+func Test_lockedPolicy(t *testing.T) {
+	now := time.Date(2020, 10, 11, 9, 41, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		until    time.Time
+		expected string
+	}{
+		{until: time.Time{}, expected: "locked"},
+		{until: now.Add(3*time.Hour + 12*time.Minute + 20*time.Second), expected: "locked(3h12m)"},
+		{until: now.Add(-time.Minute), expected: "locked(expired)"},
+	} {
+		require.Equal(t, tt.expected, lockedPolicy(v6.ControllerStatus{Locked: true, LockedUntil: tt.until}, now))
+	}
+}
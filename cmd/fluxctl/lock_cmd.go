@@ -1,8 +1,11 @@
 package main
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/update"
 )
 
@@ -10,6 +13,8 @@ type workloadLockOpts struct {
 	*rootOpts
 	namespace string
 	workload  string
+	lockFor   time.Duration
+	lockUntil string
 	outputOpts
 	cause update.Cause
 
@@ -27,6 +32,8 @@ func (opts *workloadLockOpts) Command() *cobra.Command {
 		Short: "Lock a workload, so it cannot be deployed.",
 		Example: makeExample(
 			"fluxctl lock --workload=default:deployment/helloworld",
+			"fluxctl lock --workload=default:deployment/helloworld --for=4h",
+			"fluxctl lock --workload=default:deployment/helloworld --until=2020-10-11T18:00:00Z",
 		),
 		RunE: opts.RunE,
 	}
@@ -34,6 +41,8 @@ func (opts *workloadLockOpts) Command() *cobra.Command {
 	AddCauseFlags(cmd, &opts.cause)
 	cmd.Flags().StringVarP(&opts.namespace, "namespace", "n", "", "Controller namespace")
 	cmd.Flags().StringVarP(&opts.workload, "workload", "w", "", "Workload to lock")
+	cmd.Flags().DurationVar(&opts.lockFor, "for", 0, "Unlock the workload automatically after this long, e.g., 4h")
+	cmd.Flags().StringVar(&opts.lockUntil, "until", "", "Unlock the workload automatically at this time, in RFC3339 format")
 
 	// Deprecated
 	cmd.Flags().StringVarP(&opts.workload, "controller", "c", "", "Controller to lock")
@@ -50,14 +59,31 @@ func (opts *workloadLockOpts) RunE(cmd *cobra.Command, args []string) error {
 	case opts.controller != "":
 		opts.workload = opts.controller
 	}
+
+	var lockedUntil time.Time
+	switch {
+	case opts.lockFor != 0 && opts.lockUntil != "":
+		return newUsageError("can't specify both --for and --until")
+	case opts.lockFor < 0:
+		return newUsageError("--for must be a positive duration")
+	case opts.lockFor != 0:
+		lockedUntil = time.Now().Add(opts.lockFor)
+	case opts.lockUntil != "":
+		var err error
+		if lockedUntil, err = policy.ParseLockedUntil(opts.lockUntil); err != nil {
+			return newUsageError("--until must be a time in RFC3339 format, e.g., 2020-10-11T18:00:00Z")
+		}
+	}
+
 	ns := getKubeConfigContextNamespaceOrDefault(opts.namespace, "default", opts.Context)
 	policyOpts := &workloadPolicyOpts{
-		rootOpts:   opts.rootOpts,
-		outputOpts: opts.outputOpts,
-		namespace:  ns,
-		workload:   opts.workload,
-		cause:      opts.cause,
-		lock:       true,
+		rootOpts:    opts.rootOpts,
+		outputOpts:  opts.outputOpts,
+		namespace:   ns,
+		workload:    opts.workload,
+		cause:       opts.cause,
+		lock:        true,
+		lockedUntil: lockedUntil,
 	}
 	return policyOpts.RunE(cmd, args)
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+type pinOpts struct {
+	*rootOpts
+	revision string
+}
+
+func newPin(parent *rootOpts) *pinOpts {
+	return &pinOpts{rootOpts: parent}
+}
+
+func (opts *pinOpts) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin",
+		Short: "Hold the cluster at a revision of the git repository, rather than syncing the branch",
+		Example: makeExample(
+			"fluxctl pin --revision 5b1d9a3",
+		),
+		RunE: opts.RunE,
+	}
+	cmd.Flags().StringVar(&opts.revision, "revision", "", "revision (commit SHA) to hold the cluster at")
+	return cmd
+}
+
+func (opts *pinOpts) RunE(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errorWantedNoArgs
+	}
+	if opts.revision == "" {
+		return newUsageError("please supply a revision with --revision")
+	}
+
+	ctx := context.Background()
+	if err := opts.API.PinRevision(ctx, opts.revision); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStderr(), "Pinned the cluster to revision %s; it will no longer be synced with the branch, until unpinned\n", opts.revision)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -22,7 +23,10 @@ type workloadPolicyOpts struct {
 	tags      []string
 
 	automate, deautomate bool
+	containers           []string // to (de)automate, rather than the workload
 	lock, unlock         bool
+	lockedUntil          time.Time // when the lock runs out, if it does
+	pinDigest, unpin     bool
 
 	cause update.Cause
 
@@ -51,6 +55,7 @@ containers which aren't explicitly named.
 		Example: makeExample(
 			"fluxctl policy --workload=default:deployment/foo --automate",
 			"fluxctl policy --workload=default:deployment/foo --lock",
+			"fluxctl policy --workload=default:deployment/foo --pin-digest",
 			"fluxctl policy --workload=default:deployment/foo --tag='bar=1.*' --tag='baz=2.*'",
 			"fluxctl policy --workload=default:deployment/foo --tag-all='master-*' --tag='bar=1.*'",
 		),
@@ -68,6 +73,8 @@ containers which aren't explicitly named.
 	flags.BoolVar(&opts.deautomate, "deautomate", false, "Deautomate workload")
 	flags.BoolVar(&opts.lock, "lock", false, "Lock workload")
 	flags.BoolVar(&opts.unlock, "unlock", false, "Unlock workload")
+	flags.BoolVar(&opts.pinDigest, "pin-digest", false, "Release images to the workload as tag and digest")
+	flags.BoolVar(&opts.unpin, "unpin-digest", false, "Release images to the workload as tag only")
 
 	// Deprecated
 	flags.StringVarP(&opts.controller, "controller", "c", "", "Controller to modify")
@@ -97,6 +104,9 @@ func (opts *workloadPolicyOpts) RunE(cmd *cobra.Command, args []string) error {
 	if opts.lock && opts.unlock {
 		return newUsageError("lock and unlock both specified")
 	}
+	if opts.pinDigest && opts.unpin {
+		return newUsageError("pin-digest and unpin-digest both specified")
+	}
 
 	ns := getKubeConfigContextNamespaceOrDefault(opts.namespace, "default", opts.Context)
 	resourceID, err := resource.ParseIDOptionalNamespace(ns, opts.workload)
@@ -127,8 +137,14 @@ func (opts *workloadPolicyOpts) RunE(cmd *cobra.Command, args []string) error {
 func calculatePolicyChanges(opts *workloadPolicyOpts) (resource.PolicyUpdate, error) {
 	add := policy.Set{}
 	if opts.automate {
-		add = add.Add(policy.Automated)
+		if len(opts.containers) == 0 {
+			add = add.Add(policy.Automated)
+		}
+		for _, container := range opts.containers {
+			add = add.Set(policy.AutomatedPrefix(container), "true")
+		}
 	}
+	remove := policy.Set{}
 	if opts.lock {
 		add = add.Add(policy.Locked)
 		if opts.cause.User != "" {
@@ -136,24 +152,41 @@ func calculatePolicyChanges(opts *workloadPolicyOpts) (resource.PolicyUpdate, er
 				Set(policy.LockedUser, opts.cause.User).
 				Set(policy.LockedMsg, opts.cause.Message)
 		}
+		if !opts.lockedUntil.IsZero() {
+			add = add.Set(policy.LockedUntil, opts.lockedUntil.UTC().Format(time.RFC3339))
+		} else {
+			remove = remove.Add(policy.LockedUntil)
+		}
 	}
 
-	remove := policy.Set{}
 	if opts.deautomate {
-		remove = remove.Add(policy.Automated)
+		if len(opts.containers) == 0 {
+			remove = remove.Add(policy.Automated)
+		}
+		for _, container := range opts.containers {
+			add = add.Set(policy.AutomatedPrefix(container), "false")
+		}
 	}
 	if opts.unlock {
 		remove = remove.
 			Add(policy.Locked).
 			Add(policy.LockedMsg).
-			Add(policy.LockedUser)
+			Add(policy.LockedUser).
+			Add(policy.LockedUntil)
+	}
+	if opts.pinDigest {
+		add = add.Add(policy.PinDigest)
+	}
+	if opts.unpin {
+		// Images given with a digest would otherwise stay pinned
+		add = add.Set(policy.PinDigest, "false")
 	}
 	if opts.tagAll != "" {
 		add = add.Set(policy.TagAll, policy.NewPattern(opts.tagAll).String())
 	}
 
 	for _, tagPair := range opts.tags {
-		parts := strings.Split(tagPair, "=")
+		parts := strings.SplitN(tagPair, "=", 2)
 		if len(parts) != 2 {
 			return resource.PolicyUpdate{}, fmt.Errorf("invalid container/tag pair: %q. Expected format is 'container=filter'", tagPair)
 		}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,6 +14,7 @@ import (
 	v6 "github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/cluster"
 	"github.com/fluxcd/flux/pkg/job"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/resource"
 	"github.com/fluxcd/flux/pkg/update"
 )
@@ -154,6 +157,17 @@ func (opts *workloadReleaseOpts) RunE(cmd *cobra.Command, args []string) error {
 		Excludes:     excludes,
 		Force:        opts.force,
 	}
+	if kind == update.ReleaseKindExecute && opts.selectsImageGroup(ctx, spec) {
+		// Plan the release first, so that any warning comes before
+		// the release is made rather than after.
+		planSpec := spec
+		planSpec.Kind = update.ReleaseKindPlan
+		plan, err := opts.releaseResult(ctx, planSpec)
+		if err != nil {
+			return err
+		}
+		opts.warnSplitImageGroups(ctx, cmd.OutOrStderr(), plan)
+	}
 	jobID, err := opts.API.UpdateManifests(ctx, update.Spec{
 		Type:  update.Images,
 		Cause: opts.cause,
@@ -167,6 +181,9 @@ func (opts *workloadReleaseOpts) RunE(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if opts.dryRun && !opts.interactive {
+		opts.warnSplitImageGroups(ctx, cmd.OutOrStderr(), result.Result)
+	}
 	if opts.interactive {
 		spec, err := promptSpec(cmd.OutOrStdout(), result, opts.verbosity)
 		spec.Force = opts.force
@@ -175,6 +192,7 @@ func (opts *workloadReleaseOpts) RunE(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		opts.warnSplitImageGroups(ctx, cmd.OutOrStderr(), selectedResult(spec))
 		fmt.Fprintf(cmd.OutOrStderr(), "Submitting selected release ...\n")
 		jobID, err = opts.API.UpdateManifests(ctx, update.Spec{
 			Type:  update.Containers,
@@ -227,6 +245,139 @@ func (opts *workloadReleaseOpts) RunE(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// releaseResult submits the release given and waits for its result.
+func (opts *workloadReleaseOpts) releaseResult(ctx context.Context, spec update.ReleaseImageSpec) (update.Result, error) {
+	jobID, err := opts.API.UpdateManifests(ctx, update.Spec{
+		Type:  update.Images,
+		Cause: opts.cause,
+		Spec:  spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := awaitJob(ctx, opts.API, jobID, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// selectsImageGroup says whether any workload the release selects is
+// in an image group, in which case it's worth planning the release to
+// check for split image groups. If the workloads can't be listed, it
+// says so, and leaves the plan to report the problem.
+func (opts *workloadReleaseOpts) selectsImageGroup(ctx context.Context, spec update.ReleaseImageSpec) bool {
+	workloads, err := opts.API.ListServicesWithOptions(ctx, v11.ListServicesOptions{})
+	if err != nil {
+		return true
+	}
+	return selectsImageGroup(spec, workloads)
+}
+
+func selectsImageGroup(spec update.ReleaseImageSpec, workloads []v6.ControllerStatus) bool {
+	excluded := map[resource.ID]bool{}
+	for _, id := range spec.Excludes {
+		excluded[id] = true
+	}
+	selected := map[update.ResourceSpec]bool{}
+	for _, s := range spec.ServiceSpecs {
+		selected[s] = true
+	}
+	for _, workload := range workloads {
+		if excluded[workload.ID] || !(selected[update.ResourceSpecAll] || selected[update.MakeResourceSpec(workload.ID)]) {
+			continue
+		}
+		policies := policySet(workload)
+		for _, c := range workload.Containers {
+			if _, ok := policy.GetImageGroup(policies, c.Name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectedResult gives the result that releasing the containers
+// selected interactively would have.
+func selectedResult(spec update.ReleaseContainersSpec) update.Result {
+	result := update.Result{}
+	for id, updates := range spec.ContainerSpecs {
+		result[id] = update.WorkloadResult{
+			Status:       update.ReleaseStatusSuccess,
+			PerContainer: updates,
+		}
+	}
+	return result
+}
+
+// warnSplitImageGroups warns if the release would put the members of an
+// image group on different tags, which automation would otherwise
+// avoid.
+func (opts *workloadReleaseOpts) warnSplitImageGroups(ctx context.Context, out io.Writer, result update.Result) {
+	if len(result.AffectedResources()) == 0 {
+		return
+	}
+	workloads, err := opts.API.ListServicesWithOptions(ctx, v11.ListServicesOptions{})
+	if err != nil {
+		fmt.Fprintf(out, "Warning: could not check image groups: %s\n", err)
+		return
+	}
+	for _, warning := range splitImageGroups(result, workloads) {
+		fmt.Fprintf(out, "Warning: %s\n", warning)
+	}
+}
+
+// splitImageGroups returns a message for each image group that the
+// release updates and would leave with members on different tags.
+func splitImageGroups(result update.Result, workloads []v6.ControllerStatus) []string {
+	type member struct {
+		name, tag string
+	}
+	members := map[string][]member{}
+	released := map[string]bool{}
+	for _, workload := range workloads {
+		policies := policySet(workload)
+		for _, c := range workload.Containers {
+			group, ok := policy.GetImageGroup(policies, c.Name)
+			if !ok {
+				continue
+			}
+			tag := c.Current.ID.Tag
+			if r, ok := result[workload.ID]; ok && r.Status == update.ReleaseStatusSuccess {
+				for _, u := range r.PerContainer {
+					if u.Container == c.Name {
+						tag = u.Target.Tag
+						released[group] = true
+					}
+				}
+			}
+			members[group] = append(members[group], member{fmt.Sprintf("%s (%s)", workload.ID, c.Name), tag})
+		}
+	}
+
+	var warnings []string
+	for group, ms := range members {
+		if !released[group] {
+			continue
+		}
+		split := false
+		for _, m := range ms[1:] {
+			split = split || m.tag != ms[0].tag
+		}
+		if !split {
+			continue
+		}
+		var tags []string
+		for _, m := range ms {
+			tags = append(tags, fmt.Sprintf("%s at %s", m.name, m.tag))
+		}
+		sort.Strings(tags)
+		warnings = append(warnings, fmt.Sprintf("release would put image group %s on different tags: %s", group, strings.Join(tags, ", ")))
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
 func writeRolloutStatus(workload v6.ControllerStatus, verbosity int) {
 	w := newTabwriter()
 	fmt.Fprintf(w, "WORKLOAD\tCONTAINER\tIMAGE\tRELEASE\tREPLICAS\n")
@@ -5,6 +5,10 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	v6 "github.com/fluxcd/flux/pkg/api/v6"
+	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/resource"
 	"github.com/fluxcd/flux/pkg/update"
 )
@@ -84,3 +88,77 @@ func TestReleaseCommand_InputFailures(t *testing.T) {
 	}
 
 }
+
+func TestSplitImageGroups(t *testing.T) {
+	groupPolicies := map[string]string{"automated": "true", "image-group": "billing"}
+	api := resource.MustParseID("default:deployment/api")
+	workloads := []v6.ControllerStatus{
+		{
+			ID:         api,
+			Containers: []v6.Container{{Name: "api", Current: image.Info{ID: mustParseRef("billing/api:1.0")}}},
+			Policies:   groupPolicies,
+		},
+		{
+			ID:         resource.MustParseID("default:deployment/worker"),
+			Containers: []v6.Container{{Name: "worker", Current: image.Info{ID: mustParseRef("billing/worker:1.0")}}},
+			Policies:   groupPolicies,
+		},
+	}
+	release := func(tag string) update.Result {
+		return update.Result{
+			api: update.WorkloadResult{
+				Status: update.ReleaseStatusSuccess,
+				PerContainer: []update.ContainerUpdate{
+					{Container: "api", Current: mustParseRef("billing/api:1.0"), Target: mustParseRef("billing/api:" + tag)},
+				},
+			},
+		}
+	}
+
+	assert.Equal(t, []string{
+		"release would put image group billing on different tags: default:deployment/api (api) at 1.1, default:deployment/worker (worker) at 1.0",
+	}, splitImageGroups(release("1.1"), workloads))
+	assert.Empty(t, splitImageGroups(update.Result{}, workloads))
+
+	// containers selected interactively are checked the same way
+	selected := update.ReleaseContainersSpec{ContainerSpecs: map[resource.ID][]update.ContainerUpdate{
+		api: release("1.1")[api].PerContainer,
+	}}
+	assert.Equal(t, splitImageGroups(release("1.1"), workloads), splitImageGroups(selectedResult(selected), workloads))
+
+	// a member already on the tag released is fine
+	workloads[1].Containers[0].Current.ID = mustParseRef("billing/worker:1.1")
+	assert.Empty(t, splitImageGroups(release("1.1"), workloads))
+}
+
+func TestSelectsImageGroup(t *testing.T) {
+	api := resource.MustParseID("default:deployment/api")
+	web := resource.MustParseID("default:deployment/web")
+	workloads := []v6.ControllerStatus{
+		{
+			ID:         api,
+			Containers: []v6.Container{{Name: "api"}},
+			Policies:   map[string]string{"automated": "true", "image-group": "billing"},
+		},
+		{
+			ID:         web,
+			Containers: []v6.Container{{Name: "web"}},
+		},
+	}
+	release := func(excludes []resource.ID, specs ...update.ResourceSpec) update.ReleaseImageSpec {
+		return update.ReleaseImageSpec{ServiceSpecs: specs, Excludes: excludes}
+	}
+
+	assert.True(t, selectsImageGroup(release(nil, update.MakeResourceSpec(api)), workloads))
+	assert.True(t, selectsImageGroup(release(nil, update.ResourceSpecAll), workloads))
+	assert.False(t, selectsImageGroup(release(nil, update.MakeResourceSpec(web)), workloads))
+	assert.False(t, selectsImageGroup(release([]resource.ID{api}, update.ResourceSpecAll), workloads))
+}
+
+func mustParseRef(s string) image.Ref {
+	ref, err := image.ParseRef(s)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
@@ -88,6 +88,8 @@ func (opts *rootOpts) Command() *cobra.Command {
 		newImageList(opts).Command(),
 		newWorkloadList(opts).Command(),
 		newWorkloadRelease(opts).Command(),
+		newPendingList(opts).Command(),
+		newApprove(opts).Command(),
 		newWorkloadAutomate(opts).Command(),
 		newWorkloadDeautomate(opts).Command(),
 		newWorkloadLock(opts).Command(),
@@ -96,6 +98,8 @@ func (opts *rootOpts) Command() *cobra.Command {
 		newSave(opts).Command(),
 		newIdentity(opts).Command(),
 		newSync(opts).Command(),
+		newPin(opts).Command(),
+		newUnpin(opts).Command(),
 		newInstall().Command(),
 		newCompletionCommand(),
 	)
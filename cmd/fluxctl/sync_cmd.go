@@ -55,6 +55,9 @@ func (opts *syncOpts) RunE(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Fprintf(cmd.OutOrStderr(), "Synchronizing with %s\n", gitConfig.Remote.URL)
+	if gitConfig.PinnedRevision != "" {
+		fmt.Fprintf(cmd.OutOrStderr(), "The cluster is pinned to revision %.7s; run `fluxctl unpin` to sync %s again\n", gitConfig.PinnedRevision, gitConfig.Remote.Branch)
+	}
 
 	updateSpec := update.Spec{
 		Type: update.Sync,
@@ -73,7 +76,13 @@ func (opts *syncOpts) RunE(cmd *cobra.Command, args []string) error {
 	}
 
 	rev := result.Revision[:7]
-	fmt.Fprintf(cmd.OutOrStderr(), "Revision of %s to apply is %s\n", gitConfig.Remote.Branch, rev)
+	if gitConfig.PinnedRevision != "" {
+		fmt.Fprintf(cmd.OutOrStderr(), "Pinned revision to apply is %s\n", rev)
+	} else if result.Tag != "" {
+		fmt.Fprintf(cmd.OutOrStderr(), "Revision of release tag %s to apply is %s\n", result.Tag, rev)
+	} else {
+		fmt.Fprintf(cmd.OutOrStderr(), "Revision of %s to apply is %s\n", gitConfig.Remote.Branch, rev)
+	}
 	fmt.Fprintf(cmd.OutOrStderr(), "Waiting for %s to be applied ...\n", rev)
 	err = awaitSync(ctx, opts.API, rev, opts.Timeout)
 	if err != nil {
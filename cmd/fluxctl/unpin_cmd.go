@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+type unpinOpts struct {
+	*rootOpts
+}
+
+func newUnpin(parent *rootOpts) *unpinOpts {
+	return &unpinOpts{rootOpts: parent}
+}
+
+func (opts *unpinOpts) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unpin",
+		Short: "Go back to syncing the cluster with the branch, after pinning it to a revision",
+		RunE:  opts.RunE,
+	}
+	return cmd
+}
+
+func (opts *unpinOpts) RunE(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return errorWantedNoArgs
+	}
+
+	ctx := context.Background()
+	if err := opts.API.PinRevision(ctx, ""); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStderr(), "Unpinned the cluster; it will be synced with the branch again")
+	return nil
+}
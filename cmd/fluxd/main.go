@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -41,11 +42,14 @@ import (
 	transport "github.com/fluxcd/flux/pkg/http"
 	"github.com/fluxcd/flux/pkg/http/client"
 	daemonhttp "github.com/fluxcd/flux/pkg/http/daemon"
+	"github.com/fluxcd/flux/pkg/http/webhook"
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/job"
 	"github.com/fluxcd/flux/pkg/manifests"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry"
 	"github.com/fluxcd/flux/pkg/registry/cache"
+	registryDisk "github.com/fluxcd/flux/pkg/registry/cache/disk"
 	registryMemcache "github.com/fluxcd/flux/pkg/registry/cache/memcached"
 	registryMiddleware "github.com/fluxcd/flux/pkg/registry/middleware"
 	"github.com/fluxcd/flux/pkg/remote"
@@ -72,11 +76,16 @@ const (
 
 	RequireECR = "ecr"
 
+	registryCacheMemcached = "memcached"
+	registryCacheDisk      = "disk"
+
 	k8sInClusterSecretsBaseDir = "/var/run/secrets/kubernetes.io"
 )
 
 var (
 	RequireValues = []string{RequireECR}
+
+	gitCommitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
 )
 
 func optionalVar(fs *pflag.FlagSet, value ssh.OptionalValue, name, usage string) ssh.OptionalValue {
@@ -131,11 +140,17 @@ func main() {
 
 		gitPollInterval = fs.Duration("git-poll-interval", 5*time.Minute, "period at which to poll git repo for new commits")
 		gitTimeout      = fs.Duration("git-timeout", 20*time.Second, "duration after which git operations time out")
+		gitWebhooks     = fs.StringSlice("git-webhook", nil, "accept push notifications at /hooks/git/<source>, given as <source>:<verification>:<path to file with secret>, where source is one of {github,gitlab,bitbucket,gitea} and verification is one of {token,hmac}")
+		gitSubmodules   = fs.Bool("git-submodules", false, "if set, git submodules will be initialised and updated (recursively) in every git checkout, using the same credentials as the main repo")
+		gitRefPolicy    = fs.String("git-ref-policy", "", "if set, sync the newest tag matching this semver pattern (e.g., semver:^2.x) instead of the HEAD of --git-branch")
 
 		// GPG commit signing
 		gitImportGPG               = fs.StringSlice("git-gpg-key-import", []string{}, "keys at the paths given will be imported for use of signing and verifying commits")
 		gitSigningKey              = fs.String("git-signing-key", "", "if set, commits Flux makes will be signed with this GPG key")
 		gitVerifySignatures        = fs.Bool("git-verify-signatures", false, "(deprecated) sets --git-verify-signatures-mode=all when set")
+		gitAllowedSigners          = fs.String("git-ssh-allowed-signers", "", "path to a file listing the SSH keys trusted to sign commits and tags, in the format of ssh-keygen's ALLOWED SIGNERS; needed to verify SSH signatures with --git-verify-signatures-mode")
+		gitSigningPolicy           = fs.String("git-signing-policy", "", "path to a file mapping path globs to the keys allowed to sign changes to them (like CODEOWNERS); an absolute path is read from the filesystem, a relative path from the git repo as of the last synced revision. Used with --git-verify-signatures-mode")
+		gitSigningPolicyBootstrap  = fs.String("git-signing-policy-bootstrap-revision", "", "full SHA of a trusted commit to read a relative --git-signing-policy from, and to verify commits from, before there is a sync tag")
 		gitVerifySignaturesModeStr = fs.String("git-verify-signatures-mode", fluxsync.VerifySignaturesModeDefault, fmt.Sprintf("if git-verify-signatures is set, which strategy to use for signature verification (one of %s)", strings.Join([]string{fluxsync.VerifySignaturesModeNone, fluxsync.VerifySignaturesModeAll, fluxsync.VerifySignaturesModeFirstParent}, ",")))
 
 		// syncing
@@ -151,8 +166,16 @@ func main() {
 		memcachedTimeout  = fs.Duration("memcached-timeout", time.Second, "maximum time to wait before giving up on memcached requests.")
 		memcachedService  = fs.String("memcached-service", "memcached", "SRV service used to discover memcache servers.")
 
+		registryCache      = fs.String("registry-cache", registryCacheMemcached, fmt.Sprintf("where to cache image metadata (one of {%s}); %q keeps it in a file, given by --registry-cache-path, rather than needing memcached", strings.Join([]string{registryCacheMemcached, registryCacheDisk}, ","), registryCacheDisk))
+		registryCachePath  = fs.String("registry-cache-path", "/var/fluxd/cache/registry.db", "file in which to keep image metadata, with --registry-cache=disk; put this on a volume to keep it when fluxd restarts")
+		registryCacheMaxMB = fs.Int("registry-cache-max-mb", 512, "maximum size in MiB of image metadata kept, with --registry-cache=disk; the entries closest to expiring are removed to stay under this")
+
 		registryDisableScanning = fs.Bool("registry-disable-scanning", false, "do not scan container image registries to fill in the registry cache")
 		automationInterval      = fs.Duration("automation-interval", 5*time.Minute, "period at which to check for image updates for automated workloads")
+		automationPlatforms     = fs.StringSlice("automation-require-platform", nil, "automated workloads are only updated to multi-platform images available for all of these platforms, given as <os>/<architecture>[/<variant>]")
+		automationMaxPerRun     = fs.Int("automation-max-workloads", 0, "maximum number of workloads automation updates in a single run; updates to others are deferred to later runs. Zero means no limit")
+		automationMaxPerNS      = fs.Int("automation-max-workloads-per-namespace", 0, "maximum number of workloads in any one namespace automation updates in a single run. Zero means no limit")
+		automationMaxPerHour    = fs.Int("automation-max-workloads-per-hour", 0, "maximum number of workloads automation updates in any hour. Zero means no limit")
 		registryPollInterval    = fs.Duration("registry-poll-interval", 5*time.Minute, "period at which to check for updated images")
 		registryRPS             = fs.Float64("registry-rps", 50, "maximum registry requests per second per host")
 		registryBurst           = fs.Int("registry-burst", defaultRemoteConnections, "maximum number of warmer connections to remote and memcache")
@@ -161,6 +184,13 @@ func main() {
 		registryExcludeImage    = fs.StringSlice("registry-exclude-image", []string{"k8s.gcr.io/*"}, "do not scan images that match these glob expressions; the default is to exclude the 'k8s.gcr.io/*' images")
 		registryIncludeImage    = fs.StringSlice("registry-include-image", nil, "if a value or values is given, scan _only_ images matching the glob pattern(s) (less any explicitly excluded)")
 		registryUseLabels       = fs.StringSlice("registry-use-labels", []string{"index.docker.io/weaveworks/*", "index.docker.io/fluxcd/*"}, "use the timestamp (RFC3339) from labels for (canonical) image refs that match these glob expression")
+		registryTimestamps      = fs.StringArray("registry-timestamp-sources", nil, "take the creation timestamp for (canonical) image refs matching a glob expression from the first of the sources given that has one, as <glob>=<source>,...; sources are {annotation,label,config,first-seen}. Takes precedence over --registry-use-labels; repeat for more patterns, the first matching being used")
+		registryMirrors         = fs.StringSlice("registry-mirror", nil, "scan images from a registry via a mirror (e.g., a pull-through cache), given as <registry host>=<mirror host>[/<path prefix>]; mirrors for the same registry are tried in the order given")
+		registryMirrorFallback  = fs.Bool("registry-mirror-fallback", true, "scan images from the registry itself if its mirrors fail")
+		registryCertsDir        = fs.String("registry-certs-dir", "", "directory with a subdirectory per registry host (e.g., my.registry:5000) containing CA certificates (*.crt) to trust, and client certificates (*.cert) and keys (*.key) to present, as for docker's /etc/docker/certs.d")
+		registryCredPlugins     = fs.StringSlice("registry-credential-plugin", nil, "run this program to get credentials for registry hosts matching a glob pattern, given as <host pattern>=<path to program>; the program is run like a docker credential helper")
+		registryPlatform        = fs.String("registry-platform", image.DefaultPlatform.String(), "for images that are multi-platform (i.e., the tag is for an image index or manifest list), use the metadata of the image for this platform, given as <os>/<architecture>[/<variant>]")
+		registryWebhooks        = fs.StringSlice("registry-webhook", nil, "accept image push notifications at /hooks/registry/<source>, given as <source>:<verification>:<path to file with secret>, where source is one of {dockerhub,quay,harbor,gitlab,distribution} and verification is one of {token,hmac}")
 
 		// AWS authentication
 		registryAWSRegions         = fs.StringSlice("registry-ecr-region", nil, "include just these AWS regions when scanning images in ECR; when not supplied, the cluster's region will included if it can be detected through the AWS API")
@@ -370,6 +400,46 @@ func main() {
 		}
 	}
 
+	if *gitAllowedSigners != "" {
+		if _, err := os.Stat(*gitAllowedSigners); err != nil {
+			logger.Log("error", "cannot read SSH allowed signers file", "err", err.Error())
+			os.Exit(1)
+		}
+		if gitVerifySignaturesMode == fluxsync.VerifySignaturesModeNone {
+			logger.Log("warning", "--git-ssh-allowed-signers has no effect unless --git-verify-signatures-mode is set")
+		}
+	}
+
+	if *gitSigningPolicy != "" && gitVerifySignaturesMode == fluxsync.VerifySignaturesModeNone {
+		logger.Log("warning", "--git-signing-policy has no effect unless --git-verify-signatures-mode is set")
+	}
+	if *gitSigningPolicyBootstrap != "" {
+		if !gitCommitSHA.MatchString(*gitSigningPolicyBootstrap) {
+			logger.Log("err", fmt.Sprintf("--git-signing-policy-bootstrap-revision value %q is not a full commit SHA", *gitSigningPolicyBootstrap))
+			os.Exit(1)
+		}
+		if *gitSigningPolicy == "" {
+			logger.Log("warning", "--git-signing-policy-bootstrap-revision has no effect unless --git-signing-policy is set")
+		}
+	}
+
+	var refPolicy policy.Pattern
+	if *gitRefPolicy != "" {
+		pattern, ok := policy.NewPattern(*gitRefPolicy).(policy.SemverPattern)
+		if !ok || !pattern.Valid() {
+			logger.Log("err", fmt.Sprintf("--git-ref-policy value %q is not a valid semver pattern, e.g., semver:^2.x", *gitRefPolicy))
+			os.Exit(1)
+		}
+		refPolicy = pattern
+	}
+
+	switch *registryCache {
+	case registryCacheMemcached, registryCacheDisk:
+	default:
+		logger.Log("err", fmt.Sprintf("--registry-cache value %q is not one of {%s,%s}", *registryCache, registryCacheMemcached, registryCacheDisk))
+		os.Exit(1)
+	}
+
 	possiblyRequired := stringset(RequireValues)
 	for _, r := range *registryRequire {
 		if !possiblyRequired.has(r) {
@@ -379,6 +449,88 @@ func main() {
 	}
 	mandatoryRegistry := stringset(*registryRequire)
 
+	var gitEndpoints []webhook.Endpoint
+	for _, w := range *gitWebhooks {
+		endpoint, err := webhook.ParseEndpoint(w)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--git-webhook: %s", err))
+			os.Exit(1)
+		}
+		gitEndpoints = append(gitEndpoints, endpoint)
+	}
+
+	registryMirrorMap := registry.Mirrors{}
+	for _, m := range *registryMirrors {
+		host, mirror, err := registry.ParseMirror(m)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--registry-mirror: %s", err))
+			os.Exit(1)
+		}
+		registryMirrorMap[host] = append(registryMirrorMap[host], mirror)
+	}
+
+	platform, err := image.ParsePlatform(*registryPlatform)
+	if err != nil {
+		logger.Log("err", fmt.Sprintf("--registry-platform: %s", err))
+		os.Exit(1)
+	}
+	var requiredPlatforms []image.Platform
+	for _, p := range *automationPlatforms {
+		required, err := image.ParsePlatform(p)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--automation-require-platform: %s", err))
+			os.Exit(1)
+		}
+		requiredPlatforms = append(requiredPlatforms, required)
+	}
+
+	// Rules for image timestamps: those given explicitly; then the
+	// labels-first rule for --registry-use-labels; then the image
+	// config for everything else.
+	var timestampSources cache.TimestampSources
+	for _, t := range *registryTimestamps {
+		rule, err := cache.ParseTimestampSourceRule(t)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--registry-timestamp-sources: %s", err))
+			os.Exit(1)
+		}
+		timestampSources = append(timestampSources, rule)
+	}
+	for _, pattern := range *registryUseLabels {
+		timestampSources = append(timestampSources, cache.TimestampSourceRule{
+			Pattern: pattern,
+			Sources: []image.TimestampSource{image.TimestampLabel, image.TimestampConfig},
+		})
+	}
+	timestampSources = append(timestampSources, cache.TimestampSourceRule{
+		Pattern: "*",
+		Sources: []image.TimestampSource{image.TimestampConfig},
+	})
+
+	var registryCredentialPlugins []registry.CredentialPlugin
+	for _, p := range *registryCredPlugins {
+		plugin, err := registry.ParseCredentialPlugin(p)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--registry-credential-plugin: %s", err))
+			os.Exit(1)
+		}
+		registryCredentialPlugins = append(registryCredentialPlugins, plugin)
+	}
+
+	var registryEndpoints []webhook.Endpoint
+	for _, w := range *registryWebhooks {
+		endpoint, err := webhook.ParseEndpoint(w)
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--registry-webhook: %s", err))
+			os.Exit(1)
+		}
+		registryEndpoints = append(registryEndpoints, endpoint)
+	}
+	if len(registryEndpoints) > 0 && *registryDisableScanning {
+		logger.Log("warning", "--registry-webhook has no effect when --registry-disable-scanning is set, since there is no image scanning to prioritise")
+		registryEndpoints = nil
+	}
+
 	if *gitSecret && len(*gitImportGPG) == 0 {
 		logger.Log("warning", fmt.Sprintf("--git-secret is enabled but there is no GPG key(s) provided using --git-gpg-key-import, we assume you mounted the keyring directly and continue"))
 	}
@@ -430,6 +582,7 @@ func main() {
 	var k8s cluster.Cluster
 	var k8sManifests manifests.Manifests
 	var imageCreds func() registry.ImageCreds
+	var imageTagsInUse func() registry.ImageTags
 	{
 		clientset, err := k8sclient.NewForConfig(restClientConfig)
 		if err != nil {
@@ -529,6 +682,7 @@ func main() {
 
 		k8s = k8sInst
 		imageCreds = k8sInst.ImagesToFetch
+		imageTagsInUse = k8sInst.ImageTagsInUse
 		// There is only one way we currently interpret a repo of
 		// files as manifests, and that's as Kubernetes yamels.
 		namespacer, err := kubernetes.NewNamespacer(discoClientset, *k8sDefaultNamespace)
@@ -576,31 +730,47 @@ func main() {
 	if !*registryDisableScanning {
 		// Cache client, for use by registry and cache warmer
 		var cacheClient cache.Client
-		var memcacheClient *registryMemcache.MemcacheClient
-		memcacheConfig := registryMemcache.MemcacheConfig{
-			Host:           *memcachedHostname,
-			Service:        *memcachedService,
-			Timeout:        *memcachedTimeout,
-			UpdateInterval: 1 * time.Minute,
-			Logger:         log.With(logger, "component", "memcached"),
-			MaxIdleConns:   *registryBurst,
-		}
-
-		// if no memcached service is specified use the ClusterIP name instead of SRV records
-		if *memcachedService == "" {
-			memcacheClient = registryMemcache.NewFixedServerMemcacheClient(memcacheConfig,
-				fmt.Sprintf("%s:%d", *memcachedHostname, *memcachedPort))
-		} else {
-			memcacheClient = registryMemcache.NewMemcacheClient(memcacheConfig)
-		}
+		switch *registryCache {
+		case registryCacheDisk:
+			diskClient, err := registryDisk.NewDiskClient(registryDisk.DiskConfig{
+				Path:       *registryCachePath,
+				MaxSize:    int64(*registryCacheMaxMB) << 20,
+				GCInterval: 10 * time.Minute,
+				Logger:     log.With(logger, "component", "registry-cache"),
+			})
+			if err != nil {
+				logger.Log("err", err)
+				os.Exit(1)
+			}
+			defer diskClient.Stop()
+			cacheClient = cache.InstrumentClient(diskClient)
+		default:
+			var memcacheClient *registryMemcache.MemcacheClient
+			memcacheConfig := registryMemcache.MemcacheConfig{
+				Host:           *memcachedHostname,
+				Service:        *memcachedService,
+				Timeout:        *memcachedTimeout,
+				UpdateInterval: 1 * time.Minute,
+				Logger:         log.With(logger, "component", "memcached"),
+				MaxIdleConns:   *registryBurst,
+			}
+
+			// if no memcached service is specified use the ClusterIP name instead of SRV records
+			if *memcachedService == "" {
+				memcacheClient = registryMemcache.NewFixedServerMemcacheClient(memcacheConfig,
+					fmt.Sprintf("%s:%d", *memcachedHostname, *memcachedPort))
+			} else {
+				memcacheClient = registryMemcache.NewMemcacheClient(memcacheConfig)
+			}
 
-		defer memcacheClient.Stop()
-		cacheClient = cache.InstrumentClient(memcacheClient)
+			defer memcacheClient.Stop()
+			cacheClient = cache.InstrumentClient(memcacheClient)
+		}
 
 		imageRegistry = &cache.Cache{
 			Reader: cacheClient,
 			Decorators: []cache.Decorator{
-				cache.TimestampLabelWhitelist(*registryUseLabels),
+				timestampSources,
 			},
 		}
 		imageRegistry = registry.NewInstrumentedRegistry(imageRegistry)
@@ -612,11 +782,23 @@ func main() {
 			Burst:  *registryBurst,
 			Logger: log.With(logger, "component", "ratelimiter"),
 		}
+		var registryTLS *registry.HostTLS
+		if *registryCertsDir != "" {
+			registryTLS = &registry.HostTLS{
+				Dir:    *registryCertsDir,
+				Logger: registryLogger,
+			}
+		}
 		remoteFactory := &registry.RemoteClientFactory{
-			Logger:        registryLogger,
-			Limiters:      registryLimits,
-			Trace:         *registryTrace,
-			InsecureHosts: *registryInsecure,
+			Logger:            registryLogger,
+			Limiters:          registryLimits,
+			Trace:             *registryTrace,
+			InsecureHosts:     *registryInsecure,
+			CredentialPlugins: registryCredentialPlugins,
+			TLS:               registryTLS,
+			Mirrors:           registryMirrorMap,
+			MirrorFallback:    *registryMirrorFallback,
+			Platform:          platform,
 		}
 
 		// Warmer
@@ -652,7 +834,7 @@ func main() {
 		SkipMessage: *gitSkipMessage,
 	}
 
-	repo := git.NewRepo(gitRemote, git.PollInterval(*gitPollInterval), git.Timeout(*gitTimeout), git.Branch(*gitBranch), git.IsReadOnly(*gitReadonly))
+	repo := git.NewRepo(gitRemote, git.PollInterval(*gitPollInterval), git.Timeout(*gitTimeout), git.Branch(*gitBranch), git.IsReadOnly(*gitReadonly), git.RecurseSubmodules(*gitSubmodules), git.AllowedSignersFile(*gitAllowedSigners))
 	{
 		shutdownWg.Add(1)
 		go func() {
@@ -669,6 +851,9 @@ func main() {
 		"email", *gitEmail,
 		"signing-key", *gitSigningKey,
 		"verify-signatures-mode", gitVerifySignaturesMode,
+		"signing-policy", *gitSigningPolicy,
+		"signing-policy-bootstrap-revision", *gitSigningPolicyBootstrap,
+		"ref-policy", *gitRefPolicy,
 		"sync-tag", *gitSyncTag,
 		"state", *syncState,
 		"readonly", *gitReadonly,
@@ -676,6 +861,7 @@ func main() {
 		"notes-ref", *gitNotesRef,
 		"set-author", *gitSetAuthor,
 		"git-secret", *gitSecret,
+		"submodules", *gitSubmodules,
 		"sops", *sopsEnabled,
 	)
 
@@ -734,13 +920,22 @@ func main() {
 		ManifestGenerationEnabled: *manifestGeneration,
 		GitSecretEnabled:          *gitSecret,
 		LoopVars: &daemon.LoopVars{
-			SyncInterval:            *syncInterval,
-			SyncTimeout:             *syncTimeout,
-			SyncState:               syncProvider,
-			AutomationInterval:      *automationInterval,
-			GitTimeout:              *gitTimeout,
-			GitVerifySignaturesMode: gitVerifySignaturesMode,
-			ImageScanDisabled:       *registryDisableScanning,
+			SyncInterval:              *syncInterval,
+			SyncTimeout:               *syncTimeout,
+			SyncState:                 syncProvider,
+			AutomationInterval:        *automationInterval,
+			GitTimeout:                *gitTimeout,
+			GitVerifySignaturesMode:   gitVerifySignaturesMode,
+			GitSigningPolicy:          *gitSigningPolicy,
+			GitSigningPolicyBootstrap: *gitSigningPolicyBootstrap,
+			GitRefPolicy:              refPolicy,
+			ImageScanDisabled:         *registryDisableScanning,
+			RequiredPlatforms:         requiredPlatforms,
+			AutomationLimits: daemon.AutomationLimits{
+				PerRun:       *automationMaxPerRun,
+				PerNamespace: *automationMaxPerNS,
+				PerHour:      *automationMaxPerHour,
+			},
 		},
 	}
 
@@ -779,11 +974,30 @@ func main() {
 	if !*registryDisableScanning {
 		cacheWarmer.Notify = daemon.AskForAutomatedWorkloadImageUpdates
 		cacheWarmer.Priority = daemon.ImageRefresh
+		cacheWarmer.TagsInUse = imageTagsInUse
 		cacheWarmer.Trace = *registryTrace
 		shutdownWg.Add(1)
 		go cacheWarmer.Loop(log.With(logger, "component", "warmer"), shutdown, shutdownWg, imageCreds)
 	}
 
+	var gitHooks, registryHooks http.Handler
+	if len(gitEndpoints) > 0 {
+		hooks, err := webhook.NewGitHandler(gitEndpoints, gitRemote, *gitBranch, refPolicy, daemon, log.With(logger, "component", "git-webhook"))
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--git-webhook: %s", err))
+			os.Exit(1)
+		}
+		gitHooks = hooks
+	}
+	if len(registryEndpoints) > 0 {
+		hooks, err := webhook.NewRegistryHandler(registryEndpoints, daemon, log.With(logger, "component", "registry-webhook"))
+		if err != nil {
+			logger.Log("err", fmt.Sprintf("--registry-webhook: %s", err))
+			os.Exit(1)
+		}
+		registryHooks = hooks
+	}
+
 	go func() {
 		mux := http.DefaultServeMux
 		// Serve /metrics alongside API
@@ -792,6 +1006,12 @@ func main() {
 		}
 		handler := daemonhttp.NewHandler(daemon, daemonhttp.NewRouter())
 		mux.Handle("/api/flux/", http.StripPrefix("/api/flux", handler))
+		if gitHooks != nil {
+			mux.Handle("/hooks/git/", http.StripPrefix("/hooks/git", gitHooks))
+		}
+		if registryHooks != nil {
+			mux.Handle("/hooks/registry/", http.StripPrefix("/hooks/registry", registryHooks))
+		}
 		logger.Log("addr", *listenAddr)
 		errc <- http.ListenAndServe(*listenAddr, mux)
 	}()
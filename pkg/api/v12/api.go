@@ -0,0 +1,16 @@
+// This package defines the types for Flux API version 12.
+package v12
+
+import (
+	"context"
+
+	"github.com/fluxcd/flux/pkg/api/v11"
+)
+
+type Server interface {
+	v11.Server
+
+	// PinRevision holds the cluster at the given revision, rather than
+	// syncing it with the branch; an empty revision removes the pin.
+	PinRevision(ctx context.Context, revision string) error
+}
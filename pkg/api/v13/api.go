@@ -0,0 +1,33 @@
+// This package defines the types for Flux API version 13.
+package v13
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/api/v12"
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/resource"
+)
+
+// PendingUpdate is an update that automation has proposed for a
+// container, and which waits to be approved before it is released.
+type PendingUpdate struct {
+	WorkloadID resource.ID
+	Container  string
+	Current    image.Ref
+	Target     image.Ref
+	// The image group the container is in, if any; the updates for a
+	// group are approved together
+	Group string `json:",omitempty"`
+	// When the update was first proposed
+	ProposedAt time.Time
+}
+
+type Server interface {
+	v12.Server
+
+	// ListPendingUpdates returns the updates automation has proposed,
+	// and which wait to be approved.
+	ListPendingUpdates(ctx context.Context) ([]PendingUpdate, error)
+}
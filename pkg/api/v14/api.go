@@ -0,0 +1,36 @@
+// This package defines the types for Flux API version 14.
+package v14
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/resource"
+)
+
+// DeferredUpdate is an update that automation would have made, but
+// put off to a later run, because making it would have gone over the
+// limits on how many workloads automation updates.
+type DeferredUpdate struct {
+	WorkloadID resource.ID
+	Container  string
+	Current    image.Ref
+	Target     image.Ref
+	// The image group the container is in, if any; the updates for a
+	// group are deferred together
+	Group string `json:",omitempty"`
+	// Which limit the update would have gone over
+	Reason string
+	// When the update was first deferred
+	DeferredAt time.Time
+}
+
+type Server interface {
+	v13.Server
+
+	// ListDeferredUpdates returns the updates automation has put off
+	// to a later run, because of its limits.
+	ListDeferredUpdates(ctx context.Context) ([]DeferredUpdate, error)
+}
@@ -2,6 +2,7 @@ package v6
 
 import (
 	"context"
+	"time"
 
 	"github.com/fluxcd/flux/pkg/cluster"
 	"github.com/fluxcd/flux/pkg/git"
@@ -42,8 +43,10 @@ type ControllerStatus struct {
 	Labels     map[string]string
 	Automated  bool
 	Locked     bool
-	Ignore     bool
-	Policies   map[string]string
+	// When the lock runs out, if it's for a limited time (from v13)
+	LockedUntil time.Time
+	Ignore      bool
+	Policies    map[string]string
 }
 
 // --- config types
@@ -59,6 +62,11 @@ type GitConfig struct {
 	PublicSSHKey ssh.PublicKey     `json:"publicSSHKey"`
 	Status       git.GitRepoStatus `json:"status"`
 	Error        string            `json:"errors"`
+	// The revision the cluster is pinned to, if any (from v12)
+	PinnedRevision string `json:"pinnedRevision,omitempty"`
+	// The release tag synced, or to be synced, when syncing release
+	// tags; this is what HEAD stands for in SyncStatus (from v12)
+	ReleaseTag string `json:"releaseTag,omitempty"`
 }
 
 type Deprecated interface {
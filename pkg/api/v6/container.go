@@ -1,6 +1,8 @@
 package v6
 
 import (
+	"time"
+
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry"
@@ -24,6 +26,10 @@ type Container struct {
 	// Filtered available images (matching tag filters)
 	FilteredImagesCount    int `json:",omitempty"`
 	NewFilteredImagesCount int `json:",omitempty"`
+
+	// How old an image must be before automation will update to it;
+	// younger images are still "soaking"
+	MinAge time.Duration `json:",omitempty"`
 }
 
 type imageSorter interface {
@@ -35,7 +41,7 @@ type imageSorter interface {
 }
 
 // NewContainer creates a Container given a list of images and the current image
-func NewContainer(name string, images imageSorter, currentImage image.Info, tagPattern policy.Pattern, fields []string) (Container, error) {
+func NewContainer(name string, images imageSorter, currentImage image.Info, tagPattern policy.Pattern, minAge time.Duration, fields []string) (Container, error) {
 	// Default fields
 	if len(fields) == 0 {
 		fields = []string{
@@ -48,6 +54,7 @@ func NewContainer(name string, images imageSorter, currentImage image.Info, tagP
 			"NewAvailableImagesCount",
 			"FilteredImagesCount",
 			"NewFilteredImagesCount",
+			"MinAge",
 		}
 	}
 
@@ -113,6 +120,8 @@ func NewContainer(name string, images imageSorter, currentImage image.Info, tagP
 			}
 		case "AvailableImagesCount":
 			c.AvailableImagesCount = len(images.Images())
+		case "MinAge":
+			c.MinAge = minAge
 
 		// these required the sorted images, which we can get
 		// straight away
@@ -2,6 +2,7 @@ package v6
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -33,6 +34,7 @@ func TestNewContainer(t *testing.T) {
 		images       []image.Info
 		currentImage image.Info
 		tagPattern   policy.Pattern
+		minAge       time.Duration
 		fields       []string
 	}
 	tests := []struct {
@@ -81,6 +83,21 @@ func TestNewContainer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Minimum age",
+			args: args{
+				name:         "container-min-age",
+				images:       []image.Info{currentSemver, newSemver},
+				currentImage: currentSemver,
+				tagPattern:   policy.NewPattern("semver:*"),
+				minAge:       2 * time.Hour,
+				fields:       []string{"Name", "MinAge"},
+			},
+			want: Container{
+				Name:   "container-min-age",
+				MinAge: 2 * time.Hour,
+			},
+		},
 		{
 			name: "Require only some calculations",
 			args: args{
@@ -138,7 +155,7 @@ func TestNewContainer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewContainer(tt.args.name, justSlice(tt.args.images), tt.args.currentImage, tt.args.tagPattern, tt.args.fields)
+			got, err := NewContainer(tt.args.name, justSlice(tt.args.images), tt.args.currentImage, tt.args.tagPattern, tt.args.minAge, tt.args.fields)
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.want, got)
 		})
@@ -58,4 +58,6 @@ type ImageUpdate struct {
 
 type GitUpdate struct {
 	URL, Branch string
+	// Tag is given, in place of Branch, for a push of a tag
+	Tag string `json:",omitempty"`
 }
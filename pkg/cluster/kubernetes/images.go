@@ -10,7 +10,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	kresource "github.com/fluxcd/flux/pkg/cluster/kubernetes/resource"
 	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry"
 	"github.com/fluxcd/flux/pkg/resource"
 )
@@ -108,6 +110,9 @@ func mergeCredentials(log func(...interface{}) error,
 			imagePullSecretCache[namespacedSecretName] = registry.NoCredentials()
 			continue
 		}
+		// Credential helpers are only used when given in fluxd's own
+		// docker config; never run programs named in a secret.
+		crd = crd.WithoutHelpers()
 		imagePullSecretCache[namespacedSecretName] = crd
 
 		// Merge into the credentials for this PodSpec
@@ -166,3 +171,60 @@ func (c *Cluster) ImagesToFetch() registry.ImageCreds {
 
 	return allImageCreds
 }
+
+// mergeTagsInUse records, for each image used in the pod template,
+// the tag deployed and the tag pattern given for the container in the
+// policy annotations.
+func mergeTagsInUse(log func(...interface{}) error,
+	includeImage func(imageName string) bool,
+	annotations map[string]string, podTemplate apiv1.PodTemplateSpec,
+	imageTags registry.ImageTags) {
+	policies := kresource.PoliciesFromAnnotations(annotations)
+	var containers []apiv1.Container
+	containers = append(containers, podTemplate.Spec.InitContainers...)
+	containers = append(containers, podTemplate.Spec.Containers...)
+	for _, container := range containers {
+		r, err := image.ParseRef(container.Image)
+		if err != nil {
+			log("err", err.Error())
+			continue
+		}
+		if !includeImage(r.CanonicalName().Name.String()) {
+			continue
+		}
+		selection := imageTags[r.CanonicalName()]
+		selection.Add(policy.GetTagPattern(policies, container.Name), r.Tag)
+		imageTags[r.CanonicalName()] = selection
+	}
+}
+
+// ImageTagsInUse is a k8s specific method to get the tags in use for
+// each image to update, according to the workloads' policies
+func (c *Cluster) ImageTagsInUse() registry.ImageTags {
+	imageTags := make(registry.ImageTags)
+	ctx := context.Background()
+
+	namespaces, err := c.getAllowedAndExistingNamespaces(ctx)
+	if err != nil {
+		c.logger.Log("err", errors.Wrap(err, "getting namespaces"))
+		return imageTags
+	}
+
+	for _, ns := range namespaces {
+		for kind, resourceKind := range resourceKinds {
+			workloads, err := resourceKind.getWorkloads(ctx, c, ns)
+			if err != nil {
+				if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+					continue
+				}
+				c.logger.Log("err", errors.Wrapf(err, "getting kind %s for namespace %s", kind, ns))
+			}
+			for _, workload := range workloads {
+				logger := log.With(c.logger, "resource", resource.MakeID(workload.GetNamespace(), kind, workload.GetName()))
+				mergeTagsInUse(logger.Log, c.imageIncluder.IsIncluded, workload.GetAnnotations(), workload.podTemplate, imageTags)
+			}
+		}
+	}
+
+	return imageTags
+}
@@ -160,3 +160,38 @@ func TestMergeCredentials_ImageExclusion(t *testing.T) {
 	// check gcr.io image exists
 	assert.Contains(t, creds, gcrImage.Name)
 }
+
+func TestMergeTagsInUse(t *testing.T) {
+	spec := func(containers ...apiv1.Container) apiv1.PodTemplateSpec {
+		return apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Containers: containers}}
+	}
+	includeAll := func(imageName string) bool { return true }
+	imageTags := registry.ImageTags{}
+
+	mergeTagsInUse(noopLog, includeAll,
+		map[string]string{"fluxcd.io/tag.app": "semver:~1.0"},
+		spec(apiv1.Container{Name: "app", Image: "foo/bar:1.0.1"}, apiv1.Container{Name: "sidecar", Image: "foo/sidecar:v2"}),
+		imageTags)
+	mergeTagsInUse(noopLog, includeAll,
+		map[string]string{"filter.fluxcd.io/main": "glob:release-*"},
+		spec(apiv1.Container{Name: "main", Image: "foo/bar:release-3"}),
+		imageTags)
+
+	bar, _ := image.ParseRef("foo/bar")
+	selection := imageTags[bar.CanonicalName()]
+	assert.False(t, selection.All())
+	assert.ElementsMatch(t, []string{"1.0.1", "release-3"}, selection.Deployed)
+	for tag, matches := range map[string]bool{
+		"1.0.1":     true,
+		"1.0.9":     true,
+		"1.1.0":     false,
+		"release-4": true,
+		"ci-123":    false,
+	} {
+		assert.Equal(t, matches, selection.Matches(tag), tag)
+	}
+
+	// A container without a tag filter may use any tag
+	sidecar, _ := image.ParseRef("foo/sidecar")
+	assert.True(t, imageTags[sidecar.CanonicalName()].All())
+}
@@ -168,6 +168,23 @@ func TestUpdatePolicies(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "add composite tag policy",
+			in:   nil,
+			out:  []string{"fluxcd.io/tag.nginx", "expr:semver:^1.4 && !regexp:-rc"},
+			update: resource.PolicyUpdate{
+				Add: policy.Set{policy.TagPrefix("nginx"): "expr:semver:^1.4 && !regexp:-rc"},
+			},
+		},
+		{
+			name: "add invalid composite tag policy",
+			in:   nil,
+			out:  []string{"fluxcd.io/tag.nginx", "expr:semver:^1.4 && !regexp:-rc"},
+			update: resource.PolicyUpdate{
+				Add: policy.Set{policy.TagPrefix("nginx"): "expr:semver:^1.4 && (regexp:-rc"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "add tag policy with alternative prefix does not change existing prefix",
 			in:   []string{"filter.fluxcd.io/nginx", "glob:*"},
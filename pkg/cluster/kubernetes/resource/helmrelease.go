@@ -103,13 +103,13 @@ func (c ContainerImageMap) MapImageRef(image image.Ref) (map[string]string, bool
 	case c.AllDefined():
 		m[c.GetRegistry()] = image.Domain
 		m[c.GetRepository()] = image.Image
-		m[c.GetTag()] = image.Tag
+		m[c.GetTag()] = image.TagAndDigest()
 	case c.RegistryRepository():
 		m[c.GetRegistry()] = image.Domain
-		m[c.GetRepository()] = image.Image + ":" + image.Tag
+		m[c.GetRepository()] = image.Image + ":" + image.TagAndDigest()
 	case c.RepositoryTag():
 		m[c.GetRepository()] = image.Name.String()
-		m[c.GetTag()] = image.Tag
+		m[c.GetTag()] = image.TagAndDigest()
 	case c.RepositoryOnly():
 		m[c.GetRepository()] = image.String()
 	default:
@@ -274,14 +274,14 @@ func interpretAsContainer(m mapper) (image.Ref, ImageSetter, bool) {
 			case reggy && taggy:
 				m.set("registry", ref.Domain)
 				m.set("image", ref.Image)
-				m.set("tag", ref.Tag)
+				m.set("tag", ref.TagAndDigest())
 				return
 			case reggy:
 				m.set("registry", ref.Domain)
-				m.set("image", ref.Name.Image+":"+ref.Tag)
+				m.set("image", ref.Name.Image+":"+ref.TagAndDigest())
 			case taggy:
 				m.set("image", ref.Name.String())
-				m.set("tag", ref.Tag)
+				m.set("tag", ref.TagAndDigest())
 			default:
 				m.set("image", ref.String())
 			}
@@ -340,14 +340,14 @@ func interpretAsImage(m mapper) (image.Ref, ImageSetter, bool) {
 			case reggy && taggy:
 				m.set("registry", ref.Domain)
 				m.set("repository", ref.Image)
-				m.set("tag", ref.Tag)
+				m.set("tag", ref.TagAndDigest())
 				return
 			case reggy:
 				m.set("registry", ref.Domain)
-				m.set("repository", ref.Name.Image+":"+ref.Tag)
+				m.set("repository", ref.Name.Image+":"+ref.TagAndDigest())
 			case taggy:
 				m.set("repository", ref.Name.String())
-				m.set("tag", ref.Tag)
+				m.set("tag", ref.TagAndDigest())
 			default:
 				m.set("repository", ref.String())
 			}
@@ -418,7 +418,7 @@ func interpretMappedContainerImage(values map[string]interface{}, cim ContainerI
 						return imgRef, func(ref image.Ref) {
 							v.SetP(ref.Domain, cim.Registry)
 							v.SetP(ref.Image, cim.Repository)
-							v.SetP(ref.Tag, cim.Tag)
+							v.SetP(ref.TagAndDigest(), cim.Tag)
 						}, true
 					}
 				}
@@ -429,7 +429,7 @@ func interpretMappedContainerImage(values map[string]interface{}, cim ContainerI
 				if imgRef, err := image.ParseRef(reg + "/" + img); err == nil {
 					return imgRef, func(ref image.Ref) {
 						v.SetP(ref.Domain, cim.Registry)
-						v.SetP(ref.Name.Image+":"+ref.Tag, cim.Repository)
+						v.SetP(ref.Name.Image+":"+ref.TagAndDigest(), cim.Repository)
 					}, true
 				}
 			}
@@ -439,7 +439,7 @@ func interpretMappedContainerImage(values map[string]interface{}, cim ContainerI
 				if imgRef, err := image.ParseRef(img + ":" + tag); err == nil {
 					return imgRef, func(ref image.Ref) {
 						v.SetP(ref.Name.String(), cim.Repository)
-						v.SetP(ref.Tag, cim.Tag)
+						v.SetP(ref.TagAndDigest(), cim.Tag)
 					}, true
 				}
 			}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -14,6 +16,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/cluster"
@@ -149,23 +153,25 @@ func (d *Daemon) ListServicesWithOptions(ctx context.Context, opts v11.ListServi
 		case workload.IsSystem:
 			readOnly = v6.ReadOnlySystem
 		}
+		lockedUntil, _ := policy.GetLockedUntil(policies)
 		var syncError string
 		if workload.SyncError != nil {
 			syncError = workload.SyncError.Error()
 		}
 		res = append(res, v6.ControllerStatus{
-			ID:         workload.ID,
-			Containers: containers2containers(workload.ContainersOrNil()),
-			ReadOnly:   readOnly,
-			Status:     workload.Status,
-			Rollout:    workload.Rollout,
-			SyncError:  syncError,
-			Antecedent: workload.Antecedent,
-			Labels:     workload.Labels,
-			Automated:  policies.Has(policy.Automated),
-			Locked:     policies.Has(policy.Locked),
-			Ignore:     policies.Has(policy.Ignore),
-			Policies:   policies.ToStringMap(),
+			ID:          workload.ID,
+			Containers:  containers2containers(workload.ContainersOrNil()),
+			ReadOnly:    readOnly,
+			Status:      workload.Status,
+			Rollout:     workload.Rollout,
+			SyncError:   syncError,
+			Antecedent:  workload.Antecedent,
+			Labels:      workload.Labels,
+			Automated:   policy.AnyAutomated(policies),
+			Locked:      policies.Has(policy.Locked),
+			LockedUntil: lockedUntil,
+			Ignore:      policies.Has(policy.Ignore),
+			Policies:    policies.ToStringMap(),
 		})
 	}
 
@@ -249,7 +255,7 @@ func (d *Daemon) makeJobFromUpdate(update updateFunc) jobFunc {
 		var result job.Result
 		err := d.WithWorkingClone(ctx, func(working *git.Checkout) error {
 			var err error
-			if err = verifyWorkingRepo(ctx, d.Repo, working, d.SyncState, d.GitVerifySignaturesMode); d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone && err != nil {
+			if err = verifyWorkingRepo(ctx, d.Repo, working, d.SyncState, d.GitVerifySignaturesMode, d.GitSigningPolicy, d.GitSigningPolicyBootstrap); d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone && err != nil {
 				return err
 			}
 			result, err = update(ctx, jobID, working, logger)
@@ -345,12 +351,23 @@ func (d *Daemon) UpdateManifests(ctx context.Context, spec update.Spec) (job.ID,
 	}
 	switch s := spec.Spec.(type) {
 	case release.Changes:
+		if spec.Type == update.Auto {
+			if pinned, err := d.SyncState.GetPin(ctx); err != nil {
+				return id, err
+			} else if pinned != "" {
+				return id, pinnedError(pinned)
+			}
+		}
 		if s.ReleaseKind() == update.ReleaseKindPlan {
 			id := job.ID(guid.New())
 			_, err := d.executeJob(id, d.makeJobFromUpdate(d.release(spec, s)), d.Logger)
 			return id, err
 		}
-		return d.queueJob(d.makeLoggingJobFunc(d.makeJobFromUpdate(d.release(spec, s)))), nil
+		do := d.makeJobFromUpdate(d.release(spec, s))
+		if changes, ok := s.(*update.Automated); ok && spec.Type == update.Auto {
+			do = d.automatedJob(changes, do)
+		}
+		return d.queueJob(d.makeLoggingJobFunc(do)), nil
 	case resource.PolicyUpdates:
 		return d.queueJob(d.makeLoggingJobFunc(d.makeJobFromUpdate(d.updatePolicies(spec, s)))), nil
 	case update.ManualSync:
@@ -369,24 +386,40 @@ func (d *Daemon) sync() jobFunc {
 		if err != nil {
 			return result, err
 		}
-		head, err := d.Repo.BranchHead(ctx)
+		pinned, err := d.SyncState.GetPin(ctx)
 		if err != nil {
 			return result, err
 		}
-		if d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone {
+		if pinned != "" {
+			result.Revision = pinned
+			return result, nil
+		}
+		synced, err := d.SyncState.GetRevision(ctx)
+		if err != nil {
+			return result, err
+		}
+		verify := d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone
+		head, tag, err := syncTarget(ctx, d.Repo, d.GitRefPolicy, synced, verify)
+		if err != nil {
+			return result, err
+		}
+		if verify {
 			var latestValidRev string
-			if latestValidRev, _, err = latestValidRevision(ctx, d.Repo, d.SyncState, d.GitVerifySignaturesMode); err != nil {
+			var invalidCommit unverifiedCommit
+			if latestValidRev, invalidCommit, err = latestValidRevision(ctx, d.Repo, d.SyncState, d.GitVerifySignaturesMode, d.GitSigningPolicy, d.GitSigningPolicyBootstrap, d.GitRefPolicy); err != nil {
 				return result, err
 			} else if head != latestValidRev {
 				result.Revision = latestValidRev
 				return result, fmt.Errorf(
-					"The branch HEAD in the git repo is not verified, and fluxd is unable to sync to it. The last verified commit was %.8s. HEAD is %.8s.",
+					"The branch HEAD in the git repo is not verified, and fluxd is unable to sync to it. The last verified commit was %.8s. HEAD is %.8s. The first unverified %s.",
 					latestValidRev,
 					head,
+					invalidCommit,
 				)
 			}
 		}
 		result.Revision = head
+		result.Tag = tag
 		return result, err
 	}
 }
@@ -405,19 +438,42 @@ func (d *Daemon) updatePolicies(spec update.Spec, updates resource.PolicyUpdates
 		// automation run straight ASAP.
 		var anythingAutomated bool
 
+		// Deautomating a workload also means not automating any of
+		// its containers, which are found in the manifests, read
+		// just the once.
+		var resources map[string]resource.Resource
+		for _, u := range updates {
+			if !u.Remove.Has(policy.Automated) {
+				continue
+			}
+			cm, err := d.getManifestStore(working)
+			if err != nil {
+				return result, err
+			}
+			if resources, err = cm.GetAllResourcesByID(ctx); err != nil {
+				return result, err
+			}
+			break
+		}
+
 		for workloadID, u := range updates {
 			if d.Cluster.IsAllowedResource(workloadID) {
 				result.Result[workloadID] = update.WorkloadResult{
 					Status: update.ReleaseStatusSkipped,
 				}
 			}
-			if u.Add.Has(policy.Automated) {
+			if policy.AnyAutomated(u.Add) {
 				anythingAutomated = true
 			}
 			cm, err := d.getManifestStore(working)
 			if err != nil {
 				return result, err
 			}
+			if u.Remove.Has(policy.Automated) {
+				if workload, ok := resources[workloadID.String()].(resource.Workload); ok {
+					u = withoutContainerAutomation(u, workload)
+				}
+			}
 			updated, err := cm.UpdateWorkloadPolicies(ctx, workloadID, u)
 			if err != nil {
 				result.Result[workloadID] = update.WorkloadResult{
@@ -534,18 +590,28 @@ func (d *Daemon) NotifyChange(ctx context.Context, change v9.Change) error {
 	switch change.Kind {
 	case v9.GitChange:
 		gitUpdate := change.Source.(v9.GitUpdate)
-		if d.Repo.Origin().Equivalent(gitUpdate.URL) && gitUpdate.Branch != d.GitConfig.Branch {
+		unrelated := gitUpdate.Branch != d.GitConfig.Branch
+		if gitUpdate.Tag != "" {
+			// tags are only synced with a ref policy
+			unrelated = d.GitRefPolicy == nil || !d.GitRefPolicy.Matches(gitUpdate.Tag)
+		}
+		if d.Repo.Origin().Equivalent(gitUpdate.URL) && unrelated {
 			// It isn't strictly an _error_ to be notified about a repo/branch pair
 			// that isn't ours, but it's worth logging anyway for debugging.
 			d.Logger.Log("msg", "notified about unrelated change",
 				"url", gitUpdate.URL,
-				"branch", gitUpdate.Branch)
+				"branch", gitUpdate.Branch,
+				"tag", gitUpdate.Tag)
 			break
 		}
 		d.Repo.Notify()
 	case v9.ImageChange:
 		imageUpdate := change.Source.(v9.ImageUpdate)
-		d.ImageRefresh <- imageUpdate.Name
+		select {
+		case d.ImageRefresh <- imageUpdate.Name:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 	return nil
 }
@@ -596,12 +662,31 @@ func (d *Daemon) JobStatus(ctx context.Context, jobID job.ID) (job.Status, error
 // we have applied (the sync tag) and the ref given, inclusive. E.g., if you send HEAD,
 // you'll get all the commits yet to be applied. If you send a hash
 // and it's applied at or _past_ it, you'll get an empty list.
+//
+// When the cluster is pinned, or syncing from release tags, HEAD
+// stands for the pinned revision or the release tag that would be
+// synced, since commits past that are not going to be applied; the
+// name of that release tag is given by GitRepoConfig.
 func (d *Daemon) SyncStatus(ctx context.Context, commitRef string) ([]string, error) {
 	syncMarkerRevision, err := d.SyncState.GetRevision(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if commitRef == "HEAD" {
+		pinned, err := d.SyncState.GetPin(ctx)
+		switch {
+		case err != nil:
+			return nil, err
+		case pinned != "":
+			commitRef = pinned
+		case d.GitRefPolicy != nil:
+			if commitRef, _, err = syncTarget(ctx, d.Repo, d.GitRefPolicy, syncMarkerRevision, d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	commits, err := d.Repo.CommitsBetween(ctx, syncMarkerRevision, commitRef, false, d.GitConfig.Paths...)
 	if err != nil {
 		return nil, err
@@ -628,6 +713,22 @@ func (d *Daemon) GitRepoConfig(ctx context.Context, regenerate bool) (v6.GitConf
 	gitConfigError := ""
 	if err != nil {
 		gitConfigError = err.Error()
+	} else if err := d.verificationError(); err != nil {
+		gitConfigError = err.Error()
+	}
+
+	var pinned string
+	if gitConfigError == "" {
+		if pinned, err = d.SyncState.GetPin(ctx); err != nil {
+			gitConfigError = err.Error()
+		}
+	}
+
+	var releaseTag string
+	if gitConfigError == "" && pinned == "" && d.GitRefPolicy != nil {
+		if releaseTag, err = d.syncTargetTag(ctx); err != nil {
+			gitConfigError = err.Error()
+		}
 	}
 
 	path := ""
@@ -640,12 +741,72 @@ func (d *Daemon) GitRepoConfig(ctx context.Context, regenerate bool) (v6.GitConf
 			Branch: d.GitConfig.Branch,
 			Path:   path,
 		},
-		PublicSSHKey: publicSSHKey,
-		Status:       status,
-		Error:        gitConfigError,
+		PublicSSHKey:   publicSSHKey,
+		Status:         status,
+		Error:          gitConfigError,
+		PinnedRevision: pinned,
+		ReleaseTag:     releaseTag,
 	}, nil
 }
 
+// syncTargetTag returns the name of the release tag that is, or is
+// going to be, synced.
+func (d *Daemon) syncTargetTag(ctx context.Context) (string, error) {
+	synced, err := d.SyncState.GetRevision(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, tag, err := syncTarget(ctx, d.Repo, d.GitRefPolicy, synced, d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone)
+	return tag, err
+}
+
+// PinRevision holds the cluster at the revision given, rather than
+// syncing it with the branch, until it is called with an empty
+// revision. When verifying signatures, the revision must be at or
+// before the latest verified revision.
+func (d *Daemon) PinRevision(ctx context.Context, revision string) error {
+	if revision != "" {
+		rev, err := d.Repo.Revision(ctx, revision)
+		if err != nil {
+			return unknownRevisionError(revision, err)
+		}
+		if d.GitVerifySignaturesMode != sync.VerifySignaturesModeNone {
+			latestValidRev, _, err := latestValidRevision(ctx, d.Repo, d.SyncState, d.GitVerifySignaturesMode, d.GitSigningPolicy, d.GitSigningPolicyBootstrap, d.GitRefPolicy)
+			if err != nil {
+				return err
+			}
+			if ok, err := d.Repo.IsAncestor(ctx, rev, latestValidRev); err != nil {
+				return err
+			} else if !ok {
+				return unverifiedPinError(rev, latestValidRev)
+			}
+		}
+		revision = rev
+	}
+	if err := d.SyncState.UpdatePin(ctx, revision); err != nil {
+		return err
+	}
+	// Fetch the pin, if it's kept in the repo, and have the loop
+	// take it into account.
+	return d.Repo.Refresh(ctx)
+}
+
+// ListPendingUpdates returns the updates automation has proposed
+// for containers with the policy `automated: propose`.
+func (d *Daemon) ListPendingUpdates(ctx context.Context) ([]v13.PendingUpdate, error) {
+	d.pendingMu.RLock()
+	defer d.pendingMu.RUnlock()
+	return append([]v13.PendingUpdate(nil), d.pending...), nil
+}
+
+// ListDeferredUpdates returns the updates automation has put off to
+// a later run, because of its limits.
+func (d *Daemon) ListDeferredUpdates(ctx context.Context) ([]v14.DeferredUpdate, error) {
+	d.deferredMu.RLock()
+	defer d.deferredMu.RUnlock()
+	return append([]v14.DeferredUpdate(nil), d.deferred...), nil
+}
+
 // Non-api.Server methods
 
 // WithWorkingClone applies the given func to a fresh, writable clone
@@ -761,6 +922,9 @@ func getWorkloadContainers(workload cluster.Workload, imageRepos update.ImageRep
 			policies = resource.Policies()
 		}
 		tagPattern := policy.GetTagPattern(policies, c.Name)
+		// an invalid minimum age is reported by automation; here,
+		// it's taken to be no minimum
+		minAge, _ := policy.GetMinAge(policies, c.Name)
 
 		imageRepo, ok := repos[imageName]
 		if !ok {
@@ -782,7 +946,7 @@ func getWorkloadContainers(workload cluster.Workload, imageRepos update.ImageRep
 
 		currentImage := imageRepo.ImageByTag(c.Image.Tag)
 
-		container, err := v6.NewContainer(c.Name, imageRepo, currentImage, tagPattern, fields)
+		container, err := v6.NewContainer(c.Name, imageRepo, currentImage, tagPattern, minAge, fields)
 		if err != nil {
 			return res, err
 		}
@@ -792,6 +956,19 @@ func getWorkloadContainers(workload cluster.Workload, imageRepos update.ImageRep
 	return res, nil
 }
 
+// withoutContainerAutomation adds to the policy update the removal
+// of the automated.<container> policy for each container of the
+// workload, unless the update sets it.
+func withoutContainerAutomation(u resource.PolicyUpdate, workload resource.Workload) resource.PolicyUpdate {
+	for _, c := range workload.Containers() {
+		p := policy.AutomatedPrefix(c.Name)
+		if _, ok := u.Add.Get(p); !ok {
+			u.Remove = u.Remove.Add(p)
+		}
+	}
+	return u
+}
+
 func policyCommitMessage(us resource.PolicyUpdates, cause update.Cause) string {
 	// shortcut, since we want roughly the same information
 	events := policyEvents(us, time.Now())
@@ -868,40 +1045,99 @@ func policyEventTypes(u resource.PolicyUpdate) []string {
 	return result
 }
 
-// latestValidRevision returns the HEAD of the configured branch if it
-// has a valid signature, or the SHA of the latest valid commit it
-// could find plus the invalid commit thereafter.
+// unverifiedCommit is a commit that failed verification; either
+// because it does not have a valid signature, or because it changes
+// `Path`, which the signing policy does not allow its signer to
+// change.
+type unverifiedCommit struct {
+	git.Commit
+	Path string
+}
+
+func (c unverifiedCommit) String() string {
+	if c.Path != "" {
+		return fmt.Sprintf("commit %.8s changes %s, but is signed by %s, who is not allowed to change it", c.Revision, c.Path, c.Signature.Identity())
+	}
+	return fmt.Sprintf("commit %.8s does not have a valid signature (%s)", c.Revision, c.Signature.Identity())
+}
+
+// latestValidRevision returns the HEAD of the configured branch (or,
+// given a ref policy, the newest release tag with a valid signature
+// matching it) if it has a valid signature, or the SHA of the latest
+// valid commit it could find plus the invalid commit thereafter.
 //
 // Signature validation happens for commits between the revision of the
 // sync tag and the HEAD, after the signature of the sync tag itself
 // has been validated, as the branch can not be trusted when the tag
 // originates from an unknown source.
 //
+// If a signing policy is given, commits are instead validated against
+// that: a commit is valid if every protected file it changes may be
+// changed by its signer, whether or not it is signed at all. A
+// relative path to the policy is only ever read from a trusted
+// revision: that of the sync tag or, if there is no sync tag yet, the
+// bootstrap revision given, from which commits are then validated.
+// With neither, nothing is valid.
+//
 // In case the signature of the tag can not be verified, or it points
 // towards a revision we can not get a commit range for, it returns an
 // error.
-func latestValidRevision(ctx context.Context, repo *git.Repo, syncState sync.State, gitVerifySignaturesMode sync.VerifySignaturesMode) (string, git.Commit, error) {
-	var invalidCommit = git.Commit{}
-	newRevision, err := repo.BranchHead(ctx)
+func latestValidRevision(ctx context.Context, repo *git.Repo, syncState sync.State, gitVerifySignaturesMode sync.VerifySignaturesMode, signingPolicy, signingPolicyBootstrap string, refPolicy policy.Pattern) (string, unverifiedCommit, error) {
+	var invalidCommit = unverifiedCommit{}
+
+	// Validate sync state and retrieve the revision it points to
+	tagRevision, err := syncState.GetRevision(ctx)
 	if err != nil {
 		return "", invalidCommit, err
 	}
 
-	// Validate sync state and retrieve the revision it points to
-	tagRevision, err := syncState.GetRevision(ctx)
+	newRevision, _, err := syncTarget(ctx, repo, refPolicy, tagRevision, true)
 	if err != nil {
 		return "", invalidCommit, err
 	}
 
+	var signers *git.SigningPolicy
+	if signingPolicy != "" {
+		// Before the first sync, commits are validated from the
+		// bootstrap revision, which is trusted as the sync tag would be
+		if tagRevision == "" && signingPolicyBootstrap != "" {
+			if ok, err := repo.IsAncestor(ctx, signingPolicyBootstrap, newRevision); err != nil {
+				return "", invalidCommit, errors.Wrap(err, "checking signing policy bootstrap revision")
+			} else if !ok {
+				return "", invalidCommit, fmt.Errorf("signing policy bootstrap revision %s is not an ancestor of %s", signingPolicyBootstrap, newRevision)
+			}
+			tagRevision = signingPolicyBootstrap
+		}
+		if tagRevision == "" && !filepath.IsAbs(signingPolicy) {
+			return "", invalidCommit, errors.New("no trusted revision to read the signing policy from; give a bootstrap revision, or a signing policy outside the repo")
+		}
+		if signers, err = loadSigningPolicy(ctx, repo, signingPolicy, tagRevision); err != nil {
+			return "", invalidCommit, errors.Wrap(err, "loading signing policy")
+		}
+	}
+
 	var gitFirstParent = gitVerifySignaturesMode == sync.VerifySignaturesModeFirstParent
 
 	var commits []git.Commit
 	if tagRevision == "" {
 		commits, err = repo.CommitsBefore(ctx, newRevision, gitFirstParent)
 	} else {
-		// Assure the commit _at_ the high water mark is a signed and valid commit
+		// Assure the commit _at_ the high water mark is a signed and
+		// valid commit; with a signing policy, an unsigned commit is
+		// still valid if it doesn't violate the policy.
 		if err = repo.VerifyCommit(ctx, tagRevision); err != nil {
-			return "", invalidCommit, errors.Wrap(err, "failed to verify signature of last sync'ed revision")
+			if signers == nil {
+				return "", invalidCommit, errors.Wrap(err, "failed to verify signature of last sync'ed revision")
+			}
+			commit, err := repo.Commit(ctx, tagRevision)
+			if err != nil {
+				return "", invalidCommit, err
+			}
+			if violation, violated, err := checkSigningPolicy(ctx, repo, signers, commit); err != nil {
+				return "", invalidCommit, err
+			} else if violated {
+				return "", invalidCommit, fmt.Errorf("last sync'ed revision is not valid: %s", violation)
+			}
 		}
 		commits, err = repo.CommitsBetween(ctx, tagRevision, newRevision, gitFirstParent)
 	}
@@ -915,20 +1151,61 @@ func latestValidRevision(ctx context.Context, repo *git.Repo, syncState sync.Sta
 	// return the revision of the commit before that, as that one is
 	// valid.
 	for i := len(commits) - 1; i >= 0; i-- {
-		if !commits[i].Signature.Valid() {
+		invalidCommit = unverifiedCommit{Commit: commits[i]}
+		valid := commits[i].Signature.Valid()
+		if signers != nil {
+			var violated bool
+			if invalidCommit, violated, err = checkSigningPolicy(ctx, repo, signers, commits[i]); err != nil {
+				return tagRevision, unverifiedCommit{}, err
+			}
+			valid = !violated
+		}
+		if !valid {
 			if i+1 < len(commits) {
-				return commits[i+1].Revision, commits[i], nil
+				return commits[i+1].Revision, invalidCommit, nil
 			}
-			return tagRevision, commits[i], nil
+			return tagRevision, invalidCommit, nil
 		}
 	}
 
-	return newRevision, invalidCommit, nil
+	return newRevision, unverifiedCommit{}, nil
+}
+
+// checkSigningPolicy checks the files changed by the commit given
+// against the signing policy, reporting whether its signer was
+// allowed to change them all.
+func checkSigningPolicy(ctx context.Context, repo *git.Repo, signers *git.SigningPolicy, commit git.Commit) (unverifiedCommit, bool, error) {
+	files, err := repo.FilesChangedIn(ctx, commit.Revision)
+	if err != nil {
+		return unverifiedCommit{}, false, err
+	}
+	path, violated := signers.Violation(commit.Signature, files)
+	return unverifiedCommit{Commit: commit, Path: path}, violated, nil
+}
+
+// loadSigningPolicy reads the signing policy at the path given; if
+// the path is relative, it's read from the repo at the revision given,
+// otherwise from the filesystem (e.g., a mounted secret).
+func loadSigningPolicy(ctx context.Context, repo *git.Repo, path, revision string) (*git.SigningPolicy, error) {
+	var policyBytes []byte
+	var err error
+	if filepath.IsAbs(path) {
+		policyBytes, err = ioutil.ReadFile(path)
+	} else {
+		policyBytes, err = repo.ReadFile(ctx, revision, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return git.ParseSigningPolicy(bytes.NewReader(policyBytes))
 }
 
-// verifyWorkingRepo checks that a working clone is safe to be used for a write operation
-func verifyWorkingRepo(ctx context.Context, repo *git.Repo, working *git.Checkout, syncState sync.State, gitVerifySignaturesMode sync.VerifySignaturesMode) error {
-	if latestVerifiedRev, _, err := latestValidRevision(ctx, repo, syncState, gitVerifySignaturesMode); err != nil {
+// verifyWorkingRepo checks that a working clone is safe to be used
+// for a write operation. Writes go to the branch, so this is always
+// verified up to the branch HEAD, whether or not syncing is from
+// release tags.
+func verifyWorkingRepo(ctx context.Context, repo *git.Repo, working *git.Checkout, syncState sync.State, gitVerifySignaturesMode sync.VerifySignaturesMode, signingPolicy, signingPolicyBootstrap string) error {
+	if latestVerifiedRev, _, err := latestValidRevision(ctx, repo, syncState, gitVerifySignaturesMode, signingPolicy, signingPolicyBootstrap, nil); err != nil {
 		return err
 	} else if headRev, err := working.HeadRevision(ctx); err != nil {
 		return err
@@ -21,6 +21,7 @@ import (
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/cluster"
 	"github.com/fluxcd/flux/pkg/cluster/kubernetes"
+	kresource "github.com/fluxcd/flux/pkg/cluster/kubernetes/resource"
 	"github.com/fluxcd/flux/pkg/cluster/kubernetes/testfiles"
 	"github.com/fluxcd/flux/pkg/cluster/mock"
 	"github.com/fluxcd/flux/pkg/event"
@@ -527,6 +528,40 @@ func TestDaemon_PolicyUpdate(t *testing.T) {
 	}, "Waiting for new annotation")
 }
 
+func TestWithoutContainerAutomation(t *testing.T) {
+	manifests, err := kresource.ParseMultidoc([]byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: helloworld
+  namespace: default
+spec:
+  template:
+    spec:
+      containers:
+      - name: greeter
+        image: quay.io/weaveworks/helloworld:master-a000001
+      - name: sidecar
+        image: weaveworks/sidecar:master-a000002
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	workload := manifests[wl].(resource.Workload)
+
+	u := withoutContainerAutomation(resource.PolicyUpdate{
+		Remove: policy.Set{}.Add(policy.Automated),
+	}, workload)
+	assert.Equal(t, policy.Set{}.Add(policy.Automated, policy.AutomatedPrefix("greeter"), policy.AutomatedPrefix("sidecar")), u.Remove)
+
+	// a container the update automates stays automated
+	u = withoutContainerAutomation(resource.PolicyUpdate{
+		Add:    policy.Set{}.Add(policy.AutomatedPrefix("sidecar")),
+		Remove: policy.Set{}.Add(policy.Automated),
+	}, workload)
+	assert.Equal(t, policy.Set{}.Add(policy.Automated, policy.AutomatedPrefix("greeter")), u.Remove)
+}
+
 // When I call sync status, it should return a commit showing the sync
 // that is about to take place. Then it should return empty once it is
 // complete
@@ -85,3 +85,49 @@ HEAD is
 `,
 	}
 }
+
+func unknownRevisionError(revision string, err error) error {
+	return &fluxerr.Error{
+		Type: fluxerr.User,
+		Err:  fmt.Errorf("unknown revision %q: %s", revision, err),
+		Help: `Revision not found
+
+The revision given could not be found in the git repo. Check that it
+names a commit that has been pushed, and try again.
+`,
+	}
+}
+
+func unverifiedPinError(revision, latestValidRevision string) error {
+	return &fluxerr.Error{
+		Type: fluxerr.User,
+		Err:  fmt.Errorf("revision %.8s is not verified", revision),
+		Help: `Revision is not verified
+
+Signature verification is enabled, so the cluster can only be pinned
+to a commit that comes before the last verified commit, which is
+
+    ` + latestValidRevision + `
+
+Pick a revision at or before that, and try again.
+`,
+	}
+}
+
+func pinnedError(revision string) error {
+	return &fluxerr.Error{
+		Type: fluxerr.User,
+		Err:  fmt.Errorf("the cluster is pinned to revision %.8s", revision),
+		Help: `The cluster is pinned
+
+The cluster is being held at the revision
+
+    ` + revision + `
+
+and automated image updates are not made while it is pinned, since
+they would not be applied. To go back to syncing the branch, run
+
+    fluxctl unpin
+`,
+	}
+}
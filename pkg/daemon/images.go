@@ -3,11 +3,17 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
 
+	"github.com/fluxcd/flux/pkg/api/v13"
 	"github.com/fluxcd/flux/pkg/cluster"
+	"github.com/fluxcd/flux/pkg/event"
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/job"
 	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/resource"
 	"github.com/fluxcd/flux/pkg/update"
@@ -18,13 +24,24 @@ func (d *Daemon) pollForNewAutomatedWorkloadImages(logger log.Logger) {
 
 	ctx := context.Background()
 
-	candidateWorkloads, err := d.getAllowedAutomatedResources(ctx)
+	if pinned, err := d.SyncState.GetPin(ctx); err != nil {
+		logger.Log("error", errors.Wrap(err, "checking whether the cluster is pinned"))
+		return
+	} else if pinned != "" {
+		logger.Log("msg", "cluster is pinned; no automated image updates will be made", "revision", pinned)
+		return
+	}
+
+	candidateWorkloads, heldGroups, err := d.getAllowedAutomatedResources(ctx)
 	if err != nil {
 		logger.Log("error", errors.Wrap(err, "getting unlocked automated resources"))
 		return
 	}
 	if len(candidateWorkloads) == 0 {
 		logger.Log("msg", "no automated workloads")
+		d.setPendingUpdates(&update.Automated{})
+		d.notifyAvailable(&update.Automated{}, nil, nil)
+		d.setDeferredUpdates(nil, time.Now().UTC())
 		return
 	}
 	// Find images to check
@@ -40,13 +57,95 @@ func (d *Daemon) pollForNewAutomatedWorkloadImages(logger log.Logger) {
 		return
 	}
 
-	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos)
+	run := calculateChanges(logger, candidateWorkloads, workloads, imageRepos, d.RequiredPlatforms, heldGroups)
+	d.setPendingUpdates(run.proposed)
+	d.notifyAvailable(run.notify, run.newer, run.current)
 
+	changes := d.limitAutomatedChanges(logger, run.changes, time.Now().UTC())
 	if len(changes.Changes) > 0 {
-		d.UpdateManifests(ctx, update.Spec{Type: update.Auto, Spec: changes})
+		if _, err := d.UpdateManifests(ctx, update.Spec{Type: update.Auto, Spec: changes}); err != nil {
+			logger.Log("error", errors.Wrap(err, "queueing automated image updates"))
+		}
 	}
 }
 
+// automatedJob wraps the job that makes the changes found by
+// automation. The workloads it updates count towards the limit per
+// hour from when it is queued, so that later runs don't go over the
+// limit before it finishes; and the changes aren't made if the
+// cluster has been pinned in the meantime.
+func (d *Daemon) automatedJob(changes *update.Automated, do jobFunc) jobFunc {
+	queued := d.startAutomated(changes)
+	return func(ctx context.Context, id job.ID, logger log.Logger) (job.Result, error) {
+		defer d.finishAutomated(queued)
+		if pinned, err := d.SyncState.GetPin(ctx); err != nil {
+			return job.Result{}, err
+		} else if pinned != "" {
+			return job.Result{}, pinnedError(pinned)
+		}
+		result, err := do(ctx, id, logger)
+		if err != nil || result.Revision == "" {
+			return result, err
+		}
+		d.recordAutomated(result.Result, time.Now().UTC())
+		return result, nil
+	}
+}
+
+// setPendingUpdates replaces the updates that wait to be approved
+// with those proposed in the latest automation run, and logs an
+// event for those that weren't proposed before. An update keeps the
+// time it was first proposed for as long as it is pending.
+func (d *Daemon) setPendingUpdates(proposed *update.Automated) {
+	type key struct {
+		workload  resource.ID
+		container string
+	}
+	now := time.Now().UTC()
+	fresh := update.Automated{}
+
+	d.pendingMu.Lock()
+	previous := map[key]v13.PendingUpdate{}
+	for _, p := range d.pending {
+		previous[key{p.WorkloadID, p.Container}] = p
+	}
+	var pending []v13.PendingUpdate
+	for _, change := range proposed.Changes {
+		p := v13.PendingUpdate{
+			WorkloadID: change.WorkloadID,
+			Container:  change.Container.Name,
+			Current:    change.Container.Image,
+			Target:     change.ImageID,
+			Group:      change.Group,
+			ProposedAt: now,
+		}
+		if prev, ok := previous[key{p.WorkloadID, p.Container}]; ok && prev.Current == p.Current && prev.Target == p.Target {
+			p.ProposedAt = prev.ProposedAt
+		} else {
+			fresh.Changes = append(fresh.Changes, change)
+		}
+		pending = append(pending, p)
+	}
+	d.pending = pending
+	d.pendingMu.Unlock()
+
+	if len(fresh.Changes) == 0 {
+		return
+	}
+	ids := resource.IDSet{}
+	for _, change := range fresh.Changes {
+		ids.Add([]resource.ID{change.WorkloadID})
+	}
+	d.LogEvent(event.Event{
+		ServiceIDs: ids.ToSlice(),
+		Type:       event.EventProposedRelease,
+		StartedAt:  now,
+		EndedAt:    now,
+		LogLevel:   event.LogLevelInfo,
+		Metadata:   &event.ProposedReleaseEventMetadata{Spec: fresh},
+	})
+}
+
 type resources map[resource.ID]resource.Resource
 
 func (r resources) IDs() (ids []resource.ID) {
@@ -57,26 +156,74 @@ func (r resources) IDs() (ids []resource.ID) {
 }
 
 // getAllowedAutomatedResources returns all the resources that are
-// automated but do not have policies set to restrain them from
-// getting updated.
-func (d *Daemon) getAllowedAutomatedResources(ctx context.Context) (resources, error) {
+// automated, or have updates proposed or reported, but do not have policies set
+// to restrain them from getting updated; and the image groups that can't be updated,
+// because they include such a resource, with that resource.
+func (d *Daemon) getAllowedAutomatedResources(ctx context.Context) (resources, map[string]resource.ID, error) {
 	resources, _, err := d.getResources(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	result := map[resource.ID]resource.Resource{}
+	heldGroups := map[string]resource.ID{}
 	for _, resource := range resources {
 		policies := resource.Policies()
-		if policies.Has(policy.Automated) && !policies.Has(policy.Locked) && !policies.Has(policy.Ignore) {
+		if !policy.AnyAutomated(policies) && !policy.AnyProposed(policies) && !policy.AnyNotify(policies) {
+			continue
+		}
+		if !policies.Has(policy.Locked) && !policies.Has(policy.Ignore) {
 			result[resource.ResourceID()] = resource
+		} else if group, ok := policies.Get(policy.ImageGroup); ok && group != "" {
+			heldGroups[group] = resource.ResourceID()
 		}
 	}
-	return result, nil
+	return result, heldGroups, nil
 }
 
-func calculateChanges(logger log.Logger, candidateWorkloads resources, workloads []cluster.Workload, imageRepos update.ImageRepos) *update.Automated {
-	changes := &update.Automated{}
+// automationRun is what an automation run found to do.
+type automationRun struct {
+	// the updates to make
+	changes *update.Automated
+	// the updates to propose, for containers with `automated: propose`
+	proposed *update.Automated
+	// the updates to report, for containers with `automated: notify`
+	notify *update.Automated
+	// how many images are newer than the current image, for each
+	// container with `automated: notify`
+	newer map[workloadContainer]int
+	// the current image of each container with `automated: notify`
+	current map[workloadContainer]image.Ref
+}
+
+// updates returns the updates for containers with the mode given.
+func (r automationRun) updates(mode string) *update.Automated {
+	switch mode {
+	case policy.AutomatedPropose:
+		return r.proposed
+	case policy.AutomatedNotify:
+		return r.notify
+	}
+	return r.changes
+}
+
+// calculateChanges returns the updates automation should make; those
+// it should propose, for containers with the policy `automated:
+// propose`; and those it should only report, for containers with the
+// policy `automated: notify`. An image group is treated according to
+// its most restrained member: if any member only has updates
+// reported, so does the whole group, and otherwise if any member has
+// updates proposed, the whole group does.
+func calculateChanges(logger log.Logger, candidateWorkloads resources, workloads []cluster.Workload, imageRepos update.ImageRepos, requiredPlatforms []image.Platform, heldGroups map[string]resource.ID) automationRun {
+	run := automationRun{
+		changes:  &update.Automated{},
+		proposed: &update.Automated{},
+		notify:   &update.Automated{},
+		newer:    map[workloadContainer]int{},
+		current:  map[workloadContainer]image.Ref{},
+	}
+	now := time.Now()
+	groups := map[string][]automatedContainer{}
 
 	for _, workload := range workloads {
 		var p policy.Set
@@ -85,6 +232,16 @@ func calculateChanges(logger log.Logger, candidateWorkloads resources, workloads
 		}
 	containers:
 		for _, container := range workload.ContainersOrNil() {
+			var mode string
+			switch {
+			case policy.GetAutomated(p, container.Name):
+			case policy.GetProposed(p, container.Name):
+				mode = policy.AutomatedPropose
+			case policy.GetNotify(p, container.Name):
+				mode = policy.AutomatedNotify
+			default:
+				continue containers
+			}
 			currentImageID := container.Image
 			pattern := policy.GetTagPattern(p, container.Name)
 			repo := currentImageID.Name
@@ -95,23 +252,246 @@ func calculateChanges(logger log.Logger, candidateWorkloads resources, workloads
 				logger.Log("warning", fmt.Sprintf("inconsistent repository metadata: %s", err), "action", "skip container")
 				continue containers
 			}
+			minAge, err := policy.GetMinAge(p, container.Name)
+			if err != nil {
+				logger.Log("warning", fmt.Sprintf("invalid minimum age: %s", err), "action", "skip container")
+				continue containers
+			}
+			// Images already given with a digest stay pinned,
+			// unless there's a policy saying otherwise.
+			pin, ok := policy.GetPinDigest(p, container.Name)
+			if !ok {
+				pin = currentImageID.Digest != ""
+			}
+			c := automatedContainer{
+				logger:       logger,
+				workload:     workload.ID,
+				container:    container,
+				pattern:      pattern,
+				repoMetadata: repoMetadata,
+				images:       candidateImages(logger, images, currentImageID, requiredPlatforms, minAge, now),
+				pin:          pin,
+				mode:         mode,
+			}
+			if mode == policy.AutomatedNotify {
+				k := workloadContainer{workload.ID, container.Name}
+				run.newer[k] = newerImages(images, pattern, repoMetadata.FindImageWithRef(currentImageID))
+				run.current[k] = currentImageID
+			}
+			if group, ok := policy.GetImageGroup(p, container.Name); ok {
+				groups[group] = append(groups[group], c)
+				continue containers
+			}
 
-			if latest, ok := images.Latest(); ok && latest.ID != currentImageID {
-				if latest.ID.Tag == "" {
-					logger.Log("warning", "untagged image in available images", "action", "skip container")
-					continue containers
-				}
-				current := repoMetadata.FindImageWithRef(currentImageID)
-				if pattern.RequiresTimestamp() && (current.CreatedAt.IsZero() || latest.CreatedAt.IsZero()) {
-					logger.Log("warning", "image with zero created timestamp", "current", fmt.Sprintf("%s (%s)", current.ID, current.CreatedAt), "latest", fmt.Sprintf("%s (%s)", latest.ID, latest.CreatedAt), "action", "skip container")
-					continue containers
-				}
-				newImage := currentImageID.WithNewTag(latest.ID.Tag)
-				changes.Add(workload.ID, container, newImage)
-				logger.Log("info", "added update to automation run", "new", newImage, "reason", fmt.Sprintf("latest %s (%s) > current %s (%s)", latest.ID.Tag, latest.CreatedAt, currentImageID.Tag, current.CreatedAt))
+			latest, ok := c.images.Latest()
+			if !ok {
+				continue containers
+			}
+			c.update(run.updates(c.mode), "", latest)
+		}
+	}
+
+	var groupNames []string
+	for group := range groups {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+	for _, group := range groupNames {
+		members := groups[group]
+		logger := log.With(logger, "image-group", group)
+		if by, ok := heldGroups[group]; ok {
+			logger.Log("info", "image group has a member that cannot be updated", "member", by, "action", "skip group")
+			continue
+		}
+		var mode string
+		for _, m := range members {
+			if m.mode == policy.AutomatedNotify {
+				mode = m.mode
+				break
+			}
+			if m.mode == policy.AutomatedPropose {
+				mode = m.mode
 			}
 		}
+		calculateGroupChanges(logger, run.updates(mode), group, members)
 	}
 
-	return changes
+	return run
+}
+
+// automatedContainer has what's needed to decide on an automated
+// update for a container.
+type automatedContainer struct {
+	logger       log.Logger
+	workload     resource.ID
+	container    resource.Container
+	pattern      policy.Pattern
+	repoMetadata image.RepositoryMetadata
+	// the images automation may update to, newest first
+	images update.SortedImageInfos
+	pin    bool
+	// whether updates to the container are made (""), proposed
+	// (policy.AutomatedPropose) or only reported
+	// (policy.AutomatedNotify)
+	mode string
+}
+
+// newerImages returns how many of the images matching the tag
+// pattern are newer than the current image, including those that
+// automation may not update to yet (e.g., because they are too new,
+// or not for every platform required).
+func newerImages(images update.SortedImageInfos, pattern policy.Pattern, current image.Info) int {
+	var n int
+	for i := range images {
+		if images[i].ID.Tag != current.ID.Tag && pattern.Newer(&images[i], &current) {
+			n++
+		}
+	}
+	return n
+}
+
+// update adds a change to the latest image given to the changes, if
+// that is a change.
+func (c automatedContainer) update(changes *update.Automated, group string, latest image.Info) {
+	logger, currentImageID := c.logger, c.container.Image
+	add := func(newImage image.Ref) {
+		if group != "" {
+			changes.AddToGroup(group, c.workload, c.container, newImage)
+		} else {
+			changes.Add(c.workload, c.container, newImage)
+		}
+	}
+
+	if latest.ID == currentImageID.WithDigest("") {
+		// The tag is the same; but if it's pinned, the tag may
+		// have been pushed again since, and if it's no longer to be
+		// pinned, the digest is to be removed.
+		if !c.pin && currentImageID.Digest != "" {
+			newImage := currentImageID.WithDigest("")
+			add(newImage)
+			logger.Log("info", "added update to automation run", "new", newImage, "reason", fmt.Sprintf("unpinning tag %s from digest %s", latest.ID.Tag, currentImageID.Digest))
+			return
+		}
+		if !c.pin || latest.Digest == "" || latest.Digest == currentImageID.Digest {
+			return
+		}
+		newImage := currentImageID.WithDigest(latest.Digest)
+		add(newImage)
+		reason := fmt.Sprintf("tag %s moved from digest %s to %s", latest.ID.Tag, currentImageID.Digest, latest.Digest)
+		if currentImageID.Digest == "" {
+			reason = fmt.Sprintf("pinning tag %s to digest %s", latest.ID.Tag, latest.Digest)
+		}
+		logger.Log("info", "added update to automation run", "new", newImage, "reason", reason)
+		return
+	}
+
+	if latest.ID.Tag == "" {
+		logger.Log("warning", "untagged image in available images", "action", "skip container")
+		return
+	}
+	current := c.repoMetadata.FindImageWithRef(currentImageID)
+	if c.pattern.RequiresTimestamp() && (current.CreatedAt.IsZero() || latest.CreatedAt.IsZero()) {
+		logger.Log("warning", "image with zero created timestamp", "current", fmt.Sprintf("%s (%s)", current.ID, current.CreatedAt), "latest", fmt.Sprintf("%s (%s)", latest.ID, latest.CreatedAt), "action", "skip container")
+		return
+	}
+	newImage := currentImageID.WithNewTag(latest.ID.Tag)
+	if c.pin {
+		if latest.Digest == "" {
+			logger.Log("warning", "no digest known for image to pin", "latest", latest.ID, "action", "skip container")
+			return
+		}
+		newImage = newImage.WithDigest(latest.Digest)
+	}
+	add(newImage)
+	logger.Log("info", "added update to automation run", "new", newImage, "reason", fmt.Sprintf("latest %s (%s) > current %s (%s)", latest.ID.Tag, latest.CreatedAt, currentImageID.Tag, current.CreatedAt))
+}
+
+// calculateGroupChanges adds the changes that put all the members of
+// an image group on the same tag: the newest, as ordered for the
+// first member, that every member may be updated to.
+func calculateGroupChanges(logger log.Logger, changes *update.Automated, group string, members []automatedContainer) {
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].workload != members[j].workload {
+			return members[i].workload.String() < members[j].workload.String()
+		}
+		return members[i].container.Name < members[j].container.Name
+	})
+	byTag := make([]map[string]image.Info, len(members))
+	for i, m := range members {
+		byTag[i] = map[string]image.Info{}
+		for _, im := range m.images {
+			byTag[i][im.ID.Tag] = im
+		}
+	}
+
+	var tag string
+tags:
+	for _, im := range members[0].images {
+		if im.ID.Tag == "" {
+			continue
+		}
+		for i := range members[1:] {
+			if _, ok := byTag[i+1][im.ID.Tag]; !ok {
+				continue tags
+			}
+		}
+		tag = im.ID.Tag
+		break
+	}
+	if tag == "" {
+		logger.Log("warning", "no image tag may be used by all members of image group", "action", "skip group")
+		return
+	}
+	// A member may already be on a newer image, e.g., if the group
+	// was split by a release, or its newest image isn't a candidate;
+	// never move it back.
+	for i, m := range members {
+		im := byTag[i][tag]
+		current := m.repoMetadata.FindImageWithRef(m.container.Image)
+		if im.ID.Tag != current.ID.Tag && !m.pattern.Newer(&im, &current) {
+			logger.Log("warning", "image group member is on an image newer than the tag all members may use", "member", m.workload, "container", m.container.Name, "current", m.container.Image, "tag", tag, "action", "skip group")
+			return
+		}
+	}
+	for i, m := range members {
+		m.update(changes, group, byTag[i][tag])
+	}
+}
+
+// candidateImages returns the images that automation may update to:
+// those available for all the platforms given, and at least as old as
+// the minimum age. Images for which the platforms aren't known (e.g.,
+// because they were fetched by an earlier version of Flux) are kept.
+// The current image is always kept, so that automation never goes
+// back to an image older than it.
+func candidateImages(logger log.Logger, images update.SortedImageInfos, current image.Ref, platforms []image.Platform, minAge time.Duration, now time.Time) update.SortedImageInfos {
+	if len(platforms) == 0 && minAge == 0 {
+		return images
+	}
+	var result update.SortedImageInfos
+images:
+	for _, im := range images {
+		if im.ID == current.WithDigest("") {
+			result = append(result, im)
+			continue
+		}
+		// only images that would otherwise have been the latest are
+		// worth mentioning
+		for _, p := range platforms {
+			if supported, known := im.SupportsPlatform(p); known && !supported {
+				if len(result) == 0 {
+					logger.Log("info", "skipping image not available for required platform", "image", im.ID, "platform", p)
+				}
+				continue images
+			}
+		}
+		if im.Soaking(minAge, now) {
+			if len(result) == 0 {
+				logger.Log("info", "skipping image younger than minimum age", "image", im.ID, "min-age", minAge)
+			}
+			continue images
+		}
+		result = append(result, im)
+	}
+	return result
 }
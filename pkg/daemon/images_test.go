@@ -1,12 +1,16 @@
 package daemon
 
 import (
+	"context"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/go-kit/kit/log"
 
 	"github.com/fluxcd/flux/pkg/cluster"
+	"github.com/fluxcd/flux/pkg/event"
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry"
@@ -92,7 +96,7 @@ func TestCalculateChanges_Automated(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos)
+	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos, nil, nil).changes
 
 	if len := len(changes.Changes); len != 1 {
 		t.Errorf("Expected exactly 1 change, got %d changes", len)
@@ -148,7 +152,7 @@ func TestCalculateChanges_UntaggedImage(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos)
+	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos, nil, nil).changes
 
 	if len := len(changes.Changes); len != 1 {
 		t.Errorf("Expected exactly 1 change, got %d changes", len)
@@ -217,7 +221,7 @@ func TestCalculateChanges_ZeroTimestamp(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos)
+	changes := calculateChanges(logger, candidateWorkloads, workloads, imageRepos, nil, nil).changes
 
 	if len := len(changes.Changes); len != 2 {
 		t.Fatalf("Expected exactly 2 changes, got %d changes: %v", len, changes.Changes)
@@ -229,3 +233,461 @@ func TestCalculateChanges_ZeroTimestamp(t *testing.T) {
 		t.Errorf("Expected changed image to be %s, got %s", newContainer3Image, newImage)
 	}
 }
+
+// makeWorkload returns a workload running the images given, in the
+// containers named.
+func makeWorkload(id resource.ID, containers ...resource.Container) cluster.Workload {
+	return cluster.Workload{
+		ID:         id,
+		Containers: cluster.ContainersOrExcuse{Containers: containers},
+	}
+}
+
+func makeContainer(name, ref string) resource.Container {
+	return resource.Container{Name: name, Image: mustParseImageRef(ref)}
+}
+
+// makeImageRepos returns the image repos for the workloads, from a
+// registry holding the images given.
+func makeImageRepos(t *testing.T, workloads []cluster.Workload, images ...image.Info) update.ImageRepos {
+	imageRepos, err := update.FetchImageRepos(&registryMock.Registry{Images: images}, clusterContainers(workloads), log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return imageRepos
+}
+
+// calculateChangedImages returns the images automation would update
+// the workload to, if it had the policies given, in order.
+func calculateChangedImages(workloads []cluster.Workload, imageRepos update.ImageRepos, policies policy.Set, platforms []image.Platform) []string {
+	id := workloads[0].ID
+	candidateWorkloads := resources{id: candidate{resourceID: id, policies: policies}}
+	return changedImages(calculateChanges(log.NewNopLogger(), candidateWorkloads, workloads, imageRepos, platforms, nil).changes)
+}
+
+func changedImages(a *update.Automated) (result []string) {
+	for _, change := range a.Changes {
+		result = append(result, change.ImageID.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestCalculateChanges_RequiredPlatforms(t *testing.T) {
+	workloads := []cluster.Workload{
+		makeWorkload(resource.MakeID(ns, "deployment", "application"),
+			makeContainer(container1, currentContainer1Image),
+			makeContainer(container3, currentContainer3Image)),
+	}
+	now := time.Now()
+	// no arm64 image, so this should be skipped
+	new1 := makeImageInfo(newContainer1Image, now.Add(1*time.Second))
+	new1.Platforms = map[string]string{"linux/amd64": "sha256:amd64"}
+	// the platforms aren't known for the current image, and the
+	// new image has arm64 as one of them
+	new3 := makeImageInfo(newContainer3Image, now.Add(1*time.Second))
+	new3.Platforms = map[string]string{"linux/amd64": "sha256:amd64", "linux/arm64/v8": "sha256:arm64"}
+	imageRepos := makeImageRepos(t, workloads,
+		makeImageInfo(currentContainer1Image, now), new1,
+		makeImageInfo(currentContainer3Image, now), new3)
+
+	policies := policy.Set{
+		policy.Automated:             "true",
+		policy.TagPrefix(container3): "semver:^1.0",
+	}
+	changes := calculateChangedImages(workloads, imageRepos, policies, []image.Platform{{OS: "linux", Architecture: "arm64"}})
+	if expected := []string{newContainer3Image}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+}
+
+func TestCalculateChanges_PinDigest(t *testing.T) {
+	const (
+		oldDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		newDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	workloads := []cluster.Workload{
+		makeWorkload(resource.MakeID(ns, "deployment", "application"),
+			makeContainer(container1, currentContainer1Image),
+			makeContainer(container3, currentContainer3Image+"@"+oldDigest)),
+	}
+	now := time.Now()
+	new1 := makeImageInfo(newContainer1Image, now.Add(1*time.Second))
+	new1.Digest = newDigest
+	// the tag has been pushed again, so it has a new digest
+	current3 := makeImageInfo(currentContainer3Image, now)
+	current3.Digest = newDigest
+	imageRepos := makeImageRepos(t, workloads, makeImageInfo(currentContainer1Image, now), new1, current3)
+
+	policies := policy.Set{
+		policy.Automated:             "true",
+		policy.PinDigest:             "true",
+		policy.TagPrefix(container3): "glob:1.0.0",
+	}
+	changes := calculateChangedImages(workloads, imageRepos, policies, nil)
+	if expected := []string{newContainer1Image + "@" + newDigest, currentContainer3Image + "@" + newDigest}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+
+	// without a policy, an image given with a digest stays pinned
+	delete(policies, policy.PinDigest)
+	changes = calculateChangedImages(workloads, imageRepos, policies, nil)
+	if expected := []string{newContainer1Image, currentContainer3Image + "@" + newDigest}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+
+	// unless the policy says not to pin it
+	policies[policy.PinDigest] = "false"
+	changes = calculateChangedImages(workloads, imageRepos, policies, nil)
+	if expected := []string{newContainer1Image, currentContainer3Image}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+}
+
+func TestCalculateChanges_MinAge(t *testing.T) {
+	workloads := []cluster.Workload{
+		makeWorkload(resource.MakeID(ns, "deployment", "application"),
+			makeContainer(container1, currentContainer1Image),
+			makeContainer(container3, currentContainer3Image)),
+	}
+	now := time.Now()
+	// the new image was built a while ago, but only just pushed
+	new1 := makeImageInfo(newContainer1Image, now.Add(-24*time.Hour))
+	new1.FirstSeen = now.Add(-time.Minute)
+	imageRepos := makeImageRepos(t, workloads,
+		makeImageInfo(currentContainer1Image, now.Add(-48*time.Hour)), new1,
+		makeImageInfo(currentContainer3Image, now.Add(-48*time.Hour)),
+		makeImageInfo(newContainer3Image, now.Add(-3*time.Hour)))
+
+	policies := policy.Set{
+		policy.Automated:                "true",
+		policy.MinAgePrefix(container1): "2h",
+		policy.MinAgePrefix(container3): "2h",
+		policy.TagPrefix(container3):    "semver:^1.0",
+	}
+	changes := calculateChangedImages(workloads, imageRepos, policies, nil)
+	if expected := []string{newContainer3Image}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected changes %v, got %v", expected, changes)
+	}
+
+	// images too new to update to still count as newer, for
+	// containers with updates reported
+	policies[policy.Automated] = policy.AutomatedNotify
+	resourceID := workloads[0].ID
+	candidateWorkloads := resources{resourceID: candidate{resourceID: resourceID, policies: policies}}
+	run := calculateChanges(log.NewNopLogger(), candidateWorkloads, workloads, imageRepos, nil, nil)
+	if expected := []string{newContainer3Image}; !reflect.DeepEqual(changedImages(run.notify), expected) {
+		t.Errorf("Expected reported changes %v, got %v", expected, changedImages(run.notify))
+	}
+	if n := run.newer[workloadContainer{resourceID, container1}]; n != 1 {
+		t.Errorf("Expected 1 newer image, got %d", n)
+	}
+}
+
+// applicationWorkloads returns a workload with two containers, and
+// the image repos in which each has a newer image.
+func applicationWorkloads(t *testing.T) ([]cluster.Workload, update.ImageRepos) {
+	workloads := []cluster.Workload{
+		makeWorkload(resource.MakeID(ns, "deployment", "application"),
+			makeContainer(container1, currentContainer1Image),
+			makeContainer(container2, currentContainer2Image)),
+	}
+	now := time.Now()
+	imageRepos := makeImageRepos(t, workloads,
+		makeImageInfo(currentContainer1Image, now),
+		makeImageInfo(newContainer1Image, now.Add(1*time.Second)),
+		makeImageInfo(currentContainer2Image, now),
+		makeImageInfo(newContainer2Image, now.Add(1*time.Second)))
+	return workloads, imageRepos
+}
+
+func TestCalculateChanges_ContainerAutomated(t *testing.T) {
+	workloads, imageRepos := applicationWorkloads(t)
+
+	for _, tt := range []struct {
+		name     string
+		policies policy.Set
+		expected []string
+	}{
+		{
+			name: "container excluded from automated workload",
+			policies: policy.Set{
+				policy.Automated:                   "true",
+				policy.AutomatedPrefix(container2): "false",
+			},
+			expected: []string{newContainer1Image},
+		},
+		{
+			name: "container automated on its own",
+			policies: policy.Set{
+				policy.AutomatedPrefix(container2): "true",
+			},
+			expected: []string{newContainer2Image},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			newImages := calculateChangedImages(workloads, imageRepos, tt.policies, nil)
+			if !reflect.DeepEqual(newImages, tt.expected) {
+				t.Errorf("Expected changes %v, got %v", tt.expected, newImages)
+			}
+		})
+	}
+}
+
+func TestCalculateChanges_ImageGroup(t *testing.T) {
+	logger := log.NewNopLogger()
+	apiID := resource.MakeID(ns, "deployment", "api")
+	workerID := resource.MakeID(ns, "deployment", "worker")
+	makeWorkloads := func(apiImage string) []cluster.Workload {
+		return []cluster.Workload{
+			makeWorkload(apiID, makeContainer("api", apiImage)),
+			makeWorkload(workerID, makeContainer("worker", "billing/worker:1.0.0")),
+		}
+	}
+	now := time.Now()
+	imageRepos := makeImageRepos(t, makeWorkloads("billing/api:1.0.0"),
+		makeImageInfo("billing/api:1.0.0", now),
+		makeImageInfo("billing/api:1.1.0", now.Add(1*time.Second)),
+		makeImageInfo("billing/api:1.2.0", now.Add(2*time.Second)),
+		makeImageInfo("billing/worker:1.0.0", now),
+		makeImageInfo("billing/worker:1.1.0", now.Add(1*time.Second)))
+	groupPolicies := policy.Set{
+		policy.Automated:  "true",
+		policy.ImageGroup: "billing",
+	}
+
+	for _, tt := range []struct {
+		name           string
+		apiImage       string
+		workerPolicies policy.Set
+		heldGroups     map[string]resource.ID
+		expected       []string
+	}{
+		{
+			name:           "newest tag all members have",
+			workerPolicies: groupPolicies,
+			expected:       []string{"billing/api:1.1.0", "billing/worker:1.1.0"},
+		},
+		{
+			name:           "tag not admitted by a member's filter",
+			workerPolicies: groupPolicies.Set(policy.TagPrefix("worker"), "glob:1.0.*"),
+			expected:       nil,
+		},
+		{
+			name:           "group held by a locked member",
+			workerPolicies: groupPolicies,
+			heldGroups:     map[string]resource.ID{"billing": resource.MakeID(ns, "cronjob", "migrate")},
+			expected:       nil,
+		},
+		{
+			name:           "member ahead of the tag all members have",
+			apiImage:       "billing/api:1.2.0",
+			workerPolicies: groupPolicies,
+			expected:       nil,
+		},
+		{
+			name:           "not in the group",
+			workerPolicies: policy.Set{policy.Automated: "true"},
+			expected:       []string{"billing/api:1.2.0", "billing/worker:1.1.0"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			candidateWorkloads := resources{
+				apiID:    candidate{resourceID: apiID, policies: groupPolicies},
+				workerID: candidate{resourceID: workerID, policies: tt.workerPolicies},
+			}
+			apiImage := tt.apiImage
+			if apiImage == "" {
+				apiImage = "billing/api:1.0.0"
+			}
+			newImages := changedImages(calculateChanges(logger, candidateWorkloads, makeWorkloads(apiImage), imageRepos, nil, tt.heldGroups).changes)
+			if !reflect.DeepEqual(newImages, tt.expected) {
+				t.Errorf("Expected changes %v, got %v", tt.expected, newImages)
+			}
+		})
+	}
+}
+
+func TestCalculateChanges_ProposeAndNotify(t *testing.T) {
+	logger := log.NewNopLogger()
+	workloads, imageRepos := applicationWorkloads(t)
+	resourceID := workloads[0].ID
+
+	for _, tt := range []struct {
+		name                     string
+		policies                 policy.Set
+		changes, propose, notify []string
+		// newer images, by container
+		newer map[string]int
+	}{
+		{
+			name:     "workload proposed",
+			policies: policy.Set{policy.Automated: policy.AutomatedPropose},
+			propose:  []string{newContainer1Image, newContainer2Image},
+		},
+		{
+			name: "container proposed in automated workload",
+			policies: policy.Set{
+				policy.Automated:                   "true",
+				policy.AutomatedPrefix(container2): policy.AutomatedPropose,
+			},
+			changes: []string{newContainer1Image},
+			propose: []string{newContainer2Image},
+		},
+		{
+			name: "image group with a proposed member",
+			policies: policy.Set{
+				policy.Automated:                   "true",
+				policy.AutomatedPrefix(container2): policy.AutomatedPropose,
+				policy.ImageGroup:                  "app",
+			},
+			propose: []string{newContainer1Image, newContainer2Image},
+		},
+		{
+			name:     "workload notify",
+			policies: policy.Set{policy.Automated: policy.AutomatedNotify},
+			notify:   []string{newContainer1Image, newContainer2Image},
+			newer:    map[string]int{container1: 1, container2: 1},
+		},
+		{
+			name: "container notify in proposed workload",
+			policies: policy.Set{
+				policy.Automated:                   policy.AutomatedPropose,
+				policy.AutomatedPrefix(container1): policy.AutomatedNotify,
+			},
+			propose: []string{newContainer2Image},
+			notify:  []string{newContainer1Image},
+			newer:   map[string]int{container1: 1},
+		},
+		{
+			name: "image group with a notify member",
+			policies: policy.Set{
+				policy.Automated:                   policy.AutomatedPropose,
+				policy.AutomatedPrefix(container2): policy.AutomatedNotify,
+				policy.ImageGroup:                  "app",
+			},
+			notify: []string{newContainer1Image, newContainer2Image},
+			newer:  map[string]int{container2: 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			candidateWorkloads := resources{
+				resourceID: candidate{
+					resourceID: resourceID,
+					policies:   tt.policies,
+				},
+			}
+			run := calculateChanges(logger, candidateWorkloads, workloads, imageRepos, nil, nil)
+			if got := changedImages(run.changes); !reflect.DeepEqual(got, tt.changes) {
+				t.Errorf("Expected changes %v, got %v", tt.changes, got)
+			}
+			if got := changedImages(run.proposed); !reflect.DeepEqual(got, tt.propose) {
+				t.Errorf("Expected proposed changes %v, got %v", tt.propose, got)
+			}
+			if got := changedImages(run.notify); !reflect.DeepEqual(got, tt.notify) {
+				t.Errorf("Expected reported changes %v, got %v", tt.notify, got)
+			}
+			newer := map[string]int{}
+			for k, n := range run.newer {
+				newer[k.container] = n
+			}
+			if len(tt.newer) == 0 {
+				tt.newer = map[string]int{}
+			}
+			if !reflect.DeepEqual(newer, tt.newer) {
+				t.Errorf("Expected newer images %v, got %v", tt.newer, newer)
+			}
+		})
+	}
+}
+
+func TestSetPendingUpdates(t *testing.T) {
+	events := &mockEventWriter{}
+	d := &Daemon{LoopVars: &LoopVars{}, EventWriter: events, Logger: log.NewNopLogger()}
+	resourceID := resource.MakeID(ns, "deployment", "application")
+	container := resource.Container{Name: container1, Image: mustParseImageRef(currentContainer1Image)}
+	proposed := &update.Automated{}
+	proposed.Add(resourceID, container, mustParseImageRef(newContainer1Image))
+
+	d.setPendingUpdates(proposed)
+	pending, _ := d.ListPendingUpdates(context.Background())
+	if len(pending) != 1 || pending[0].Target.String() != newContainer1Image {
+		t.Fatalf("Expected an update to %s pending, got %v", newContainer1Image, pending)
+	}
+	proposedAt := pending[0].ProposedAt
+
+	// proposing the same update again neither resets when it was
+	// proposed, nor logs another event
+	d.setPendingUpdates(proposed)
+	pending, _ = d.ListPendingUpdates(context.Background())
+	if len(pending) != 1 || !pending[0].ProposedAt.Equal(proposedAt) {
+		t.Errorf("Expected the update to be pending since %s, got %v", proposedAt, pending)
+	}
+	if len(events.events) != 1 || events.events[0].Type != event.EventProposedRelease {
+		t.Errorf("Expected a single proposed release event, got %v", events.events)
+	}
+
+	d.setPendingUpdates(&update.Automated{})
+	if pending, _ = d.ListPendingUpdates(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected no pending updates, got %v", pending)
+	}
+}
+
+func TestNotifyAvailable(t *testing.T) {
+	events := &mockEventWriter{}
+	d := &Daemon{LoopVars: &LoopVars{}, EventWriter: events, Logger: log.NewNopLogger()}
+	resourceID := resource.MakeID(ns, "deployment", "application")
+	container := resource.Container{Name: container1, Image: mustParseImageRef(currentContainer1Image)}
+	notify := &update.Automated{}
+	notify.Add(resourceID, container, mustParseImageRef(newContainer1Image))
+	k := workloadContainer{resourceID, container1}
+	newer := map[workloadContainer]int{k: 1}
+	current := map[workloadContainer]image.Ref{k: container.Image}
+
+	d.notifyAvailable(notify, newer, current)
+	if len(events.events) != 1 || events.events[0].Type != event.EventUpdateAvailable {
+		t.Fatalf("Expected a single update available event, got %v", events.events)
+	}
+
+	// the same update is only reported once
+	d.notifyAvailable(notify, newer, current)
+	if len(events.events) != 1 {
+		t.Errorf("Expected the update to be reported once, got %v", events.events)
+	}
+
+	// nor again if it isn't found for a run
+	d.notifyAvailable(&update.Automated{}, nil, current)
+	d.notifyAvailable(notify, newer, current)
+	if len(events.events) != 1 {
+		t.Errorf("Expected the update to be reported once, got %v", events.events)
+	}
+
+	// a container not looked at for a run is forgotten, and its
+	// update reported again when it is
+	d.notifyAvailable(&update.Automated{}, nil, nil)
+	if len(d.notified) != 0 {
+		t.Errorf("Expected no updates to be remembered, got %v", d.notified)
+	}
+	d.notifyAvailable(notify, newer, current)
+	if len(events.events) != 2 {
+		t.Errorf("Expected the update to be reported again, got %v", events.events)
+	}
+
+	// a newer image is reported again
+	newerImage := mustParseImageRef(currentContainer1Image).WithNewTag("newer")
+	notify = &update.Automated{}
+	notify.Add(resourceID, container, newerImage)
+	d.notifyAvailable(notify, map[workloadContainer]int{k: 2}, current)
+	if len(events.events) != 3 {
+		t.Errorf("Expected the newer image to be reported, got %v", events.events)
+	}
+
+	// once the container is running the image reported, the update
+	// is forgotten, and reported again should it be found again
+	// (e.g., after a rollback)
+	d.notifyAvailable(&update.Automated{}, nil, map[workloadContainer]image.Ref{k: newerImage})
+	d.notifyAvailable(notify, map[workloadContainer]int{k: 2}, current)
+	if len(events.events) != 4 {
+		t.Errorf("Expected the image to be reported again, got %v", events.events)
+	}
+}
@@ -0,0 +1,258 @@
+package daemon
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/fluxcd/flux/pkg/api/v14"
+	"github.com/fluxcd/flux/pkg/resource"
+	"github.com/fluxcd/flux/pkg/update"
+)
+
+// AutomationLimits bounds how many workloads automation updates, so
+// that e.g., rebuilding a base image doesn't roll every workload
+// using it at once. A limit of zero means no limit.
+type AutomationLimits struct {
+	// Workloads updated in a single automation run
+	PerRun int
+	// Workloads in any one namespace updated in a single run
+	PerNamespace int
+	// Workloads updated in any hour
+	PerHour int
+}
+
+// The reasons for deferring an update, as reported in the API
+const (
+	deferredPerRun       = "per-run limit"
+	deferredPerNamespace = "per-namespace limit"
+	deferredPerHour      = "per-hour limit"
+)
+
+// deferredChange is a change put off to a later run, and why.
+type deferredChange struct {
+	update.Change
+	reason string
+}
+
+// limitChanges splits the changes into those that can be made within
+// the limits, and those that are deferred. Changes for workloads that
+// have been deferred before are taken first, those deferred longest
+// first, so that no workload is put off for ever; otherwise, changes
+// are taken in order of workload ID. The changes for an image group
+// (which may span workloads) are kept together. `recent` is the
+// number of workloads updated by automation in the last hour, and
+// `deferredSince` says when updates to workloads were first deferred.
+func (l AutomationLimits) limitChanges(changes *update.Automated, recent int, deferredSince map[resource.ID]time.Time) (*update.Automated, []deferredChange) {
+	if l.PerRun <= 0 && l.PerNamespace <= 0 && l.PerHour <= 0 {
+		return changes, nil
+	}
+
+	// The changes that must be made together, and the workloads
+	// they update
+	type unit struct {
+		workloads []resource.ID
+		// when changes to any of the workloads were first deferred
+		since  time.Time
+		reason string
+	}
+	units := map[string]*unit{}
+	unitKey := func(c update.Change) string {
+		if c.Group != "" {
+			return "group:" + c.Group
+		}
+		return "workload:" + c.WorkloadID.String()
+	}
+	for _, c := range changes.Changes {
+		key := unitKey(c)
+		u, ok := units[key]
+		if !ok {
+			u = &unit{}
+			units[key] = u
+		}
+		if !resource.IDs(u.workloads).Contains(c.WorkloadID) {
+			u.workloads = append(u.workloads, c.WorkloadID)
+		}
+		if since, ok := deferredSince[c.WorkloadID]; ok && (u.since.IsZero() || since.Before(u.since)) {
+			u.since = since
+		}
+	}
+	var keys []string
+	for key, u := range units {
+		sort.Sort(resource.IDs(u.workloads))
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if si, sj := units[keys[i]].since, units[keys[j]].since; !si.Equal(sj) {
+			switch {
+			case si.IsZero():
+				return false
+			case sj.IsZero():
+				return true
+			}
+			return si.Before(sj)
+		}
+		a, b := units[keys[i]].workloads[0].String(), units[keys[j]].workloads[0].String()
+		if a != b {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+
+	admitted := resource.IDSet{}
+	perNamespace := map[string]int{}
+	for _, key := range keys {
+		u := units[key]
+		var added []resource.ID
+		addedPerNamespace := map[string]int{}
+		for _, id := range u.workloads {
+			if !admitted.Contains(id) {
+				added = append(added, id)
+				ns, _, _ := id.Components()
+				addedPerNamespace[ns]++
+			}
+		}
+		switch {
+		case l.PerHour > 0 && recent+len(admitted)+len(added) > l.PerHour:
+			u.reason = deferredPerHour
+		case l.PerRun > 0 && len(admitted)+len(added) > l.PerRun:
+			u.reason = deferredPerRun
+		case l.PerNamespace > 0:
+			for ns, n := range addedPerNamespace {
+				if perNamespace[ns]+n > l.PerNamespace {
+					u.reason = deferredPerNamespace
+				}
+			}
+		}
+		if u.reason != "" {
+			continue
+		}
+		admitted.Add(added)
+		for ns, n := range addedPerNamespace {
+			perNamespace[ns] += n
+		}
+	}
+
+	result := &update.Automated{}
+	var deferred []deferredChange
+	for _, c := range changes.Changes {
+		if reason := units[unitKey(c)].reason; reason != "" {
+			deferred = append(deferred, deferredChange{Change: c, reason: reason})
+		} else {
+			result.Changes = append(result.Changes, c)
+		}
+	}
+	return result, deferred
+}
+
+// limitAutomatedChanges returns the changes automation can make
+// within its limits, and keeps track of those it defers.
+func (d *Daemon) limitAutomatedChanges(logger log.Logger, changes *update.Automated, now time.Time) *update.Automated {
+	admitted, deferred := d.AutomationLimits.limitChanges(changes, d.recentlyAutomated(now), d.deferredSince())
+	d.setDeferredUpdates(deferred, now)
+	if len(deferred) > 0 {
+		ids := resource.IDSet{}
+		for _, c := range deferred {
+			ids.Add([]resource.ID{c.WorkloadID})
+		}
+		logger.Log("info", "automation limits reached; deferring updates to a later run", "deferred", len(deferred), "workloads", ids)
+	}
+	return admitted
+}
+
+// recordAutomated notes the workloads updated by a committed
+// automation job, to count towards the limit per hour.
+func (d *LoopVars) recordAutomated(result update.Result, now time.Time) {
+	d.deferredMu.Lock()
+	defer d.deferredMu.Unlock()
+	for _, r := range result {
+		if r.Status == update.ReleaseStatusSuccess {
+			d.automatedAt = append(d.automatedAt, now)
+		}
+	}
+}
+
+// startAutomated counts the workloads the changes update, which are
+// queued to be made, towards the limit per hour until they are
+// finished with (by which time those committed are recorded), and
+// returns how many there are.
+func (d *LoopVars) startAutomated(changes *update.Automated) int {
+	ids := resource.IDSet{}
+	for _, c := range changes.Changes {
+		ids.Add([]resource.ID{c.WorkloadID})
+	}
+	d.deferredMu.Lock()
+	defer d.deferredMu.Unlock()
+	d.automatedQueued += len(ids)
+	return len(ids)
+}
+
+// finishAutomated stops counting the workloads of changes that were
+// queued, once the job making them has finished.
+func (d *LoopVars) finishAutomated(queued int) {
+	d.deferredMu.Lock()
+	defer d.deferredMu.Unlock()
+	d.automatedQueued -= queued
+}
+
+// recentlyAutomated returns how many workloads automation updated in
+// the hour before now, including those it is yet to update in jobs
+// queued.
+func (d *LoopVars) recentlyAutomated(now time.Time) int {
+	d.deferredMu.Lock()
+	defer d.deferredMu.Unlock()
+	i := 0
+	for i < len(d.automatedAt) && !d.automatedAt[i].After(now.Add(-time.Hour)) {
+		i++
+	}
+	d.automatedAt = d.automatedAt[i:]
+	return len(d.automatedAt) + d.automatedQueued
+}
+
+// deferredSince returns when the updates to each workload that are
+// deferred were first deferred.
+func (d *LoopVars) deferredSince() map[resource.ID]time.Time {
+	d.deferredMu.RLock()
+	defer d.deferredMu.RUnlock()
+	since := map[resource.ID]time.Time{}
+	for _, u := range d.deferred {
+		if t, ok := since[u.WorkloadID]; !ok || u.DeferredAt.Before(t) {
+			since[u.WorkloadID] = u.DeferredAt
+		}
+	}
+	return since
+}
+
+// setDeferredUpdates replaces the updates deferred by automation with
+// those deferred in the latest run. An update keeps the time it was
+// first deferred for as long as it is deferred.
+func (d *LoopVars) setDeferredUpdates(deferred []deferredChange, now time.Time) {
+	type key struct {
+		workload  resource.ID
+		container string
+	}
+	d.deferredMu.Lock()
+	defer d.deferredMu.Unlock()
+	previous := map[key]v14.DeferredUpdate{}
+	for _, u := range d.deferred {
+		previous[key{u.WorkloadID, u.Container}] = u
+	}
+	var result []v14.DeferredUpdate
+	for _, c := range deferred {
+		u := v14.DeferredUpdate{
+			WorkloadID: c.WorkloadID,
+			Container:  c.Container.Name,
+			Current:    c.Container.Image,
+			Target:     c.ImageID,
+			Group:      c.Group,
+			Reason:     c.reason,
+			DeferredAt: now,
+		}
+		if prev, ok := previous[key{u.WorkloadID, u.Container}]; ok && prev.Target == u.Target {
+			u.DeferredAt = prev.DeferredAt
+		}
+		result = append(result, u)
+	}
+	d.deferred = result
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/resource"
+	"github.com/fluxcd/flux/pkg/update"
+)
+
+func TestLimitChanges(t *testing.T) {
+	change := func(id, group string) update.Change {
+		return update.Change{
+			WorkloadID: resource.MustParseID(id),
+			Container:  resource.Container{Name: "app", Image: mustParseImageRef("app:1.0")},
+			ImageID:    mustParseImageRef("app:1.1"),
+			Group:      group,
+		}
+	}
+	// out of order, to check that changes are taken in order of workload ID
+	changes := &update.Automated{Changes: []update.Change{
+		change("b:deployment/two", ""),
+		change("a:deployment/two", ""),
+		change("a:deployment/one", ""),
+		change("a:deployment/three", "billing"),
+		change("b:deployment/one", "billing"),
+	}}
+
+	workloads := func(changes []update.Change) (ids []string) {
+		for _, c := range changes {
+			ids = append(ids, c.WorkloadID.String())
+		}
+		return ids
+	}
+
+	for _, tt := range []struct {
+		name          string
+		limits        AutomationLimits
+		recent        int
+		deferredSince map[string]time.Time
+		admitted      []string
+		deferred      map[string]string
+	}{
+		{
+			name:     "no limits",
+			admitted: []string{"b:deployment/two", "a:deployment/two", "a:deployment/one", "a:deployment/three", "b:deployment/one"},
+		},
+		{
+			name:     "per run",
+			limits:   AutomationLimits{PerRun: 2},
+			admitted: []string{"a:deployment/two", "a:deployment/one"},
+			deferred: map[string]string{
+				"b:deployment/two":   deferredPerRun,
+				"a:deployment/three": deferredPerRun,
+				"b:deployment/one":   deferredPerRun,
+			},
+		},
+		{
+			name:     "per run, with an image group that fits",
+			limits:   AutomationLimits{PerRun: 3},
+			admitted: []string{"a:deployment/one", "a:deployment/three", "b:deployment/one"},
+			deferred: map[string]string{
+				"b:deployment/two": deferredPerRun,
+				"a:deployment/two": deferredPerRun,
+			},
+		},
+		{
+			name:   "per run, taking those deferred longest first",
+			limits: AutomationLimits{PerRun: 3},
+			deferredSince: map[string]time.Time{
+				"b:deployment/two":   time.Date(2020, 10, 11, 8, 0, 0, 0, time.UTC),
+				"a:deployment/three": time.Date(2020, 10, 11, 9, 0, 0, 0, time.UTC),
+			},
+			admitted: []string{"b:deployment/two", "a:deployment/three", "b:deployment/one"},
+			deferred: map[string]string{
+				"a:deployment/two": deferredPerRun,
+				"a:deployment/one": deferredPerRun,
+			},
+		},
+		{
+			name:     "per namespace",
+			limits:   AutomationLimits{PerNamespace: 1},
+			admitted: []string{"b:deployment/two", "a:deployment/one"},
+			deferred: map[string]string{
+				"a:deployment/two":   deferredPerNamespace,
+				"a:deployment/three": deferredPerNamespace,
+				"b:deployment/one":   deferredPerNamespace,
+			},
+		},
+		{
+			name:     "per hour",
+			limits:   AutomationLimits{PerHour: 5},
+			recent:   4,
+			admitted: []string{"a:deployment/one"},
+			deferred: map[string]string{
+				"b:deployment/two":   deferredPerHour,
+				"a:deployment/two":   deferredPerHour,
+				"a:deployment/three": deferredPerHour,
+				"b:deployment/one":   deferredPerHour,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			deferredSince := map[resource.ID]time.Time{}
+			for id, since := range tt.deferredSince {
+				deferredSince[resource.MustParseID(id)] = since
+			}
+			admitted, deferred := tt.limits.limitChanges(changes, tt.recent, deferredSince)
+			if got := workloads(admitted.Changes); !reflect.DeepEqual(got, tt.admitted) {
+				t.Errorf("expected %v to be admitted, got %v", tt.admitted, got)
+			}
+			gotDeferred := map[string]string{}
+			for _, c := range deferred {
+				gotDeferred[c.WorkloadID.String()] = c.reason
+			}
+			if len(tt.deferred) == 0 && len(gotDeferred) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(gotDeferred, tt.deferred) {
+				t.Errorf("expected %v to be deferred, got %v", tt.deferred, gotDeferred)
+			}
+		})
+	}
+}
+
+func TestRecentlyAutomated(t *testing.T) {
+	now := time.Date(2020, 10, 11, 9, 41, 0, 0, time.UTC)
+	loop := &LoopVars{}
+	result := update.Result{
+		resource.MustParseID("a:deployment/one"):   update.WorkloadResult{Status: update.ReleaseStatusSuccess},
+		resource.MustParseID("a:deployment/two"):   update.WorkloadResult{Status: update.ReleaseStatusSuccess},
+		resource.MustParseID("a:deployment/three"): update.WorkloadResult{Status: update.ReleaseStatusSkipped},
+		resource.MustParseID("a:deployment/four"):  update.WorkloadResult{Status: update.ReleaseStatusFailed},
+	}
+
+	loop.recordAutomated(result, now.Add(-90*time.Minute))
+	loop.recordAutomated(result, now.Add(-30*time.Minute))
+	if n := loop.recentlyAutomated(now); n != 2 {
+		t.Errorf("expected 2 workloads updated in the last hour, got %d", n)
+	}
+
+	// workloads in jobs queued count until the jobs finish
+	queued := loop.startAutomated(&update.Automated{Changes: []update.Change{
+		{WorkloadID: resource.MustParseID("a:deployment/five"), Container: resource.Container{Name: "one"}},
+		{WorkloadID: resource.MustParseID("a:deployment/five"), Container: resource.Container{Name: "two"}},
+		{WorkloadID: resource.MustParseID("a:deployment/six"), Container: resource.Container{Name: "one"}},
+	}})
+	if n := loop.recentlyAutomated(now); n != 4 {
+		t.Errorf("expected 4 workloads updated or queued in the last hour, got %d", n)
+	}
+	loop.finishAutomated(queued)
+	if n := loop.recentlyAutomated(now); n != 2 {
+		t.Errorf("expected 2 workloads updated in the last hour, got %d", n)
+	}
+}
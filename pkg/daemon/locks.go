@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/fluxcd/flux/pkg/event"
+	"github.com/fluxcd/flux/pkg/job"
+	"github.com/fluxcd/flux/pkg/policy"
+	"github.com/fluxcd/flux/pkg/resource"
+	"github.com/fluxcd/flux/pkg/update"
+)
+
+// lockExpiredMessage is the message for the commits that unlock
+// workloads whose locks have run out. These aren't made on behalf of
+// a user, so are attributed to Flux itself.
+const lockExpiredMessage = "Lock expired"
+
+// unlockExpired queues a job to unlock the workloads, of the
+// resources given, whose locks have run out, and logs an unlock event
+// for them once that is committed. Workloads that a job queued
+// earlier has yet to unlock are left to that job.
+func (d *Daemon) unlockExpired(resources map[string]resource.Resource, logger log.Logger) {
+	updates := d.startUnlocking(expiredLocks(resources, time.Now()))
+	if len(updates) == 0 {
+		return
+	}
+
+	spec := update.Spec{
+		Type:  update.Policy,
+		Cause: update.Cause{Message: lockExpiredMessage},
+		Spec:  updates,
+	}
+	unlock := d.makeJobFromUpdate(d.updatePolicies(spec, updates))
+	id := d.queueJob(d.makeLoggingJobFunc(func(ctx context.Context, id job.ID, logger log.Logger) (job.Result, error) {
+		defer d.doneUnlocking(updates)
+		started := time.Now().UTC()
+		result, err := unlock(ctx, id, logger)
+		if err != nil || result.Revision == "" {
+			return result, err
+		}
+		var workloadIDs []resource.ID
+		for id, result := range result.Result {
+			if result.Status == update.ReleaseStatusSuccess {
+				workloadIDs = append(workloadIDs, id)
+			}
+		}
+		d.LogEvent(event.Event{
+			ServiceIDs: workloadIDs,
+			Type:       event.EventUnlock,
+			StartedAt:  started,
+			EndedAt:    time.Now().UTC(),
+			LogLevel:   event.LogLevelInfo,
+		})
+		return result, nil
+	}))
+	for workloadID := range updates {
+		logger.Log("info", "lock expired; unlocking workload", "workload", workloadID, "jobID", id)
+	}
+}
+
+// startUnlocking records that the workloads of the updates given are
+// being unlocked, and returns the updates for those that weren't
+// already.
+func (loop *LoopVars) startUnlocking(updates resource.PolicyUpdates) resource.PolicyUpdates {
+	loop.unlockingMu.Lock()
+	defer loop.unlockingMu.Unlock()
+	if loop.unlocking == nil {
+		loop.unlocking = map[resource.ID]bool{}
+	}
+	for id := range updates {
+		if loop.unlocking[id] {
+			delete(updates, id)
+			continue
+		}
+		loop.unlocking[id] = true
+	}
+	return updates
+}
+
+// doneUnlocking records that the workloads of the updates given are
+// no longer being unlocked, whether or not that succeeded.
+func (loop *LoopVars) doneUnlocking(updates resource.PolicyUpdates) {
+	loop.unlockingMu.Lock()
+	defer loop.unlockingMu.Unlock()
+	for id := range updates {
+		delete(loop.unlocking, id)
+	}
+}
+
+// expiredLocks returns the policy updates that unlock the resources
+// whose locks have run out.
+func expiredLocks(resources map[string]resource.Resource, now time.Time) resource.PolicyUpdates {
+	updates := resource.PolicyUpdates{}
+	for _, r := range resources {
+		if !policy.LockExpired(r.Policies(), now) {
+			continue
+		}
+		updates[r.ResourceID()] = resource.PolicyUpdate{
+			Remove: policy.Set{}.
+				Add(policy.Locked).
+				Add(policy.LockedUser).
+				Add(policy.LockedMsg).
+				Add(policy.LockedUntil),
+		}
+	}
+	return updates
+}
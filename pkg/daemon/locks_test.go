@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/policy"
+	"github.com/fluxcd/flux/pkg/resource"
+)
+
+func TestExpiredLocks(t *testing.T) {
+	now := time.Date(2020, 10, 11, 9, 41, 0, 0, time.UTC)
+	expired := resource.MustParseID("default:deployment/expired")
+	resources := map[string]resource.Resource{}
+	for _, c := range []candidate{
+		{resourceID: expired, policies: policy.Set{
+			policy.Locked:      "true",
+			policy.LockedUser:  "alice",
+			policy.LockedUntil: "2020-10-11T09:00:00Z",
+		}},
+		{resourceID: resource.MustParseID("default:deployment/current"), policies: policy.Set{
+			policy.Locked:      "true",
+			policy.LockedUntil: "2020-10-11T10:00:00Z",
+		}},
+		{resourceID: resource.MustParseID("default:deployment/indefinite"), policies: policy.Set{
+			policy.Locked: "true",
+		}},
+	} {
+		resources[c.resourceID.String()] = c
+	}
+
+	expected := resource.PolicyUpdates{
+		expired: resource.PolicyUpdate{
+			Remove: policy.Set{
+				policy.Locked:      "true",
+				policy.LockedUser:  "true",
+				policy.LockedMsg:   "true",
+				policy.LockedUntil: "true",
+			},
+		},
+	}
+	if updates := expiredLocks(resources, now); !reflect.DeepEqual(expected, updates) {
+		t.Errorf("expected %v, got %v", expected, updates)
+	}
+}
+
+func TestUnlockingOutstanding(t *testing.T) {
+	a := resource.MustParseID("default:deployment/a")
+	b := resource.MustParseID("default:deployment/b")
+	expired := func(ids ...resource.ID) resource.PolicyUpdates {
+		updates := resource.PolicyUpdates{}
+		for _, id := range ids {
+			updates[id] = resource.PolicyUpdate{Remove: policy.Set{}.Add(policy.Locked)}
+		}
+		return updates
+	}
+
+	loop := &LoopVars{}
+	first := loop.startUnlocking(expired(a))
+	if !reflect.DeepEqual(expired(a), first) {
+		t.Errorf("expected %v, got %v", expired(a), first)
+	}
+	// while a is being unlocked, only b is queued
+	if updates := loop.startUnlocking(expired(a, b)); !reflect.DeepEqual(expired(b), updates) {
+		t.Errorf("expected %v, got %v", expired(b), updates)
+	}
+	// once that's done, a can be unlocked again
+	loop.doneUnlocking(first)
+	if updates := loop.startUnlocking(expired(a)); !reflect.DeepEqual(expired(a), updates) {
+		t.Errorf("expected %v, got %v", expired(a), updates)
+	}
+}
@@ -8,24 +8,64 @@ import (
 
 	"github.com/go-kit/kit/log"
 
-	"github.com/fluxcd/flux/pkg/git"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
+	"github.com/fluxcd/flux/pkg/image"
 	fluxmetrics "github.com/fluxcd/flux/pkg/metrics"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/resource"
 	fluxsync "github.com/fluxcd/flux/pkg/sync"
 )
 
 type LoopVars struct {
-	SyncInterval            time.Duration
-	SyncTimeout             time.Duration
-	AutomationInterval      time.Duration
-	GitTimeout              time.Duration
-	GitVerifySignaturesMode fluxsync.VerifySignaturesMode
-	SyncState               fluxsync.State
-	ImageScanDisabled       bool
+	SyncInterval              time.Duration
+	SyncTimeout               time.Duration
+	AutomationInterval        time.Duration
+	GitTimeout                time.Duration
+	GitVerifySignaturesMode   fluxsync.VerifySignaturesMode
+	GitSigningPolicy          string
+	GitSigningPolicyBootstrap string
+	GitRefPolicy              policy.Pattern
+	SyncState                 fluxsync.State
+	ImageScanDisabled         bool
+	// images for automated updates must be available for all of
+	// these platforms
+	RequiredPlatforms []image.Platform
+	// how many workloads automation may update
+	AutomationLimits AutomationLimits
 
 	initOnce               sync.Once
 	syncSoon               chan struct{}
 	automatedWorkloadsSoon chan struct{}
+
+	verificationMu  sync.RWMutex
+	verificationErr error
+
+	// updates proposed by the last automation run, which wait to be
+	// approved
+	pendingMu sync.RWMutex
+	pending   []v13.PendingUpdate
+
+	// updates put off by automation because of its limits; when it
+	// updated workloads, oldest first; and how many workloads it is
+	// yet to update in jobs queued
+	deferredMu      sync.RWMutex
+	deferred        []v14.DeferredUpdate
+	automatedAt     []time.Time
+	automatedQueued int
+
+	// the updates reported for containers with `automated: notify`,
+	// until they are superseded or made; and how many newer images
+	// there were for each of those containers, as of the last
+	// automation run
+	notifyMu sync.Mutex
+	notified map[workloadContainer]image.Ref
+	newer    map[workloadContainer]int
+
+	// the workloads that a job queued to unlock them (once their
+	// locks ran out) has yet to
+	unlockingMu sync.Mutex
+	unlocking   map[resource.ID]bool
 }
 
 func (loop *LoopVars) ensureInit() {
@@ -100,38 +140,59 @@ func (d *Daemon) Loop(stop chan struct{}, wg *sync.WaitGroup, logger log.Logger)
 				}
 			}
 			started := time.Now().UTC()
-			err := d.Sync(context.Background(), started, syncHead, ratchet)
+			resources, err := d.syncRevision(context.Background(), started, syncHead, ratchet)
 			syncDuration.With(
 				fluxmetrics.LabelSuccess, fmt.Sprint(err == nil),
 			).Observe(time.Since(started).Seconds())
 			if err != nil {
 				logger.Log("err", err)
 			}
+			if resources != nil && !d.Repo.Readonly() {
+				d.unlockExpired(resources, logger)
+			}
 			syncTimer.Reset(d.SyncInterval)
 		case <-syncTimer.C:
 			d.AskForSync()
 		case <-d.Repo.C:
-			var newSyncHead string
-			var invalidCommit git.Commit
+			var newSyncHead, pinned string
+			var invalidCommit unverifiedCommit
 			var err error
 
 			ctx, cancel := context.WithTimeout(context.Background(), d.GitTimeout)
-			if d.GitVerifySignaturesMode != fluxsync.VerifySignaturesModeNone {
-				newSyncHead, invalidCommit, err = latestValidRevision(ctx, d.Repo, d.SyncState, d.GitVerifySignaturesMode)
-			} else {
-				newSyncHead, err = d.Repo.BranchHead(ctx)
+			if pinned, err = d.SyncState.GetPin(ctx); err == nil {
+				switch {
+				case pinned != "":
+					newSyncHead = pinned
+				case d.GitVerifySignaturesMode != fluxsync.VerifySignaturesModeNone:
+					newSyncHead, invalidCommit, err = latestValidRevision(ctx, d.Repo, d.SyncState, d.GitVerifySignaturesMode, d.GitSigningPolicy, d.GitSigningPolicyBootstrap, d.GitRefPolicy)
+				case d.GitRefPolicy != nil:
+					var synced string
+					if synced, err = d.SyncState.GetRevision(ctx); err == nil {
+						newSyncHead, _, err = syncTarget(ctx, d.Repo, d.GitRefPolicy, synced, false)
+					}
+				default:
+					newSyncHead, _, err = syncTarget(ctx, d.Repo, nil, "", false)
+				}
 			}
 			cancel()
 
 			if err != nil {
 				logger.Log("url", d.Repo.Origin().SafeURL(), "err", err)
+				if pinned == "" && d.GitVerifySignaturesMode != fluxsync.VerifySignaturesModeNone {
+					// e.g., the last synced revision is no longer
+					// verified; report why nothing is being synced
+					d.setVerificationError(err)
+				}
 				continue
 			}
 			if invalidCommit.Revision != "" {
-				logger.Log("err", "found invalid GPG signature for commit", "revision", invalidCommit.Revision, "key", invalidCommit.Signature.Key)
+				logger.Log("err", "found invalid signature for commit", "revision", invalidCommit.Revision, "key", invalidCommit.Signature.Key, "signer", invalidCommit.Signature.Identity(), "path", invalidCommit.Path)
+				d.setVerificationError(fmt.Errorf("not syncing past unverified commit: %s", invalidCommit))
+			} else {
+				d.setVerificationError(nil)
 			}
 
-			logger.Log("event", "refreshed", "url", d.Repo.Origin().SafeURL(), "branch", d.GitConfig.Branch, "HEAD", newSyncHead)
+			logger.Log("event", "refreshed", "url", d.Repo.Origin().SafeURL(), "branch", d.GitConfig.Branch, "HEAD", newSyncHead, "pinned", pinned != "")
 			if newSyncHead != syncHead {
 				syncHead = newSyncHead
 				d.AskForSync()
@@ -181,6 +242,22 @@ func (d *LoopVars) AskForAutomatedWorkloadImageUpdates() {
 	}
 }
 
+// setVerificationError records why the branch cannot be synced past
+// a commit, or clears that if given nil.
+func (d *LoopVars) setVerificationError(err error) {
+	d.verificationMu.Lock()
+	d.verificationErr = err
+	d.verificationMu.Unlock()
+}
+
+// verificationError returns the reason, if any, that the branch
+// cannot be synced past a commit.
+func (d *LoopVars) verificationError() error {
+	d.verificationMu.RLock()
+	defer d.verificationMu.RUnlock()
+	return d.verificationErr
+}
+
 // -- internals to keep track of sync tag and resources state
 type lastKnownSyncState struct {
 	logger log.Logger
@@ -51,4 +51,17 @@ var (
 		Name:      "sync_manifests",
 		Help:      "Number of synchronized manifests",
 	}, []string{fluxmetrics.LabelSuccess})
+
+	// This is used directly, rather than through go-kit, so that
+	// the series for containers no longer looked at can be deleted.
+	newerImagesMetric = stdprometheus.NewGaugeVec(stdprometheus.GaugeOpts{
+		Namespace: "flux",
+		Subsystem: "daemon",
+		Name:      "automation_newer_images",
+		Help:      "Number of images newer than the current image, for containers with automated: notify.",
+	}, []string{fluxmetrics.LabelWorkload, fluxmetrics.LabelContainer})
 )
+
+func init() {
+	stdprometheus.MustRegister(newerImagesMetric)
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/fluxcd/flux/pkg/event"
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/resource"
+	"github.com/fluxcd/flux/pkg/update"
+)
+
+// workloadContainer identifies a container in a workload.
+type workloadContainer struct {
+	workload  resource.ID
+	container string
+}
+
+// notifyAvailable reports the updates found for containers with the
+// policy `automated: notify`, without making them: it logs an event
+// for each update that wasn't reported by an earlier run, and
+// exports how many newer images there are for each container. An
+// update stays reported until the container is running the image it
+// was for, or a different update is found for the container; so an
+// update that isn't found for a run or two isn't reported again.
+// Containers not looked at in this run (e.g., because they are no
+// longer automated, or were deleted) are forgotten.
+func (d *Daemon) notifyAvailable(notify *update.Automated, newer map[workloadContainer]int, current map[workloadContainer]image.Ref) {
+	now := time.Now().UTC()
+	fresh := update.Automated{}
+
+	d.notifyMu.Lock()
+	notified := map[workloadContainer]image.Ref{}
+	for k, target := range d.notified {
+		if ref, ok := current[k]; !ok || ref.CanonicalRef() == target.CanonicalRef() {
+			continue
+		}
+		notified[k] = target
+	}
+	for _, change := range notify.Changes {
+		k := workloadContainer{change.WorkloadID, change.Container.Name}
+		if prev, ok := notified[k]; !ok || prev != change.ImageID {
+			fresh.Changes = append(fresh.Changes, change)
+		}
+		notified[k] = change.ImageID
+	}
+	// Containers no longer looked at would otherwise keep the count
+	// from when they last were.
+	for k := range d.newer {
+		if _, ok := newer[k]; !ok {
+			newerImagesMetric.DeleteLabelValues(k.workload.String(), k.container)
+		}
+	}
+	for k, n := range newer {
+		newerImagesMetric.WithLabelValues(k.workload.String(), k.container).Set(float64(n))
+	}
+	d.notified, d.newer = notified, newer
+	d.notifyMu.Unlock()
+
+	if len(fresh.Changes) == 0 {
+		return
+	}
+	ids := resource.IDSet{}
+	for _, change := range fresh.Changes {
+		ids.Add([]resource.ID{change.WorkloadID})
+	}
+	d.LogEvent(event.Event{
+		ServiceIDs: ids.ToSlice(),
+		Type:       event.EventUpdateAvailable,
+		StartedAt:  now,
+		EndedAt:    now,
+		LogLevel:   event.LogLevelInfo,
+		Metadata:   &event.UpdateAvailableEventMetadata{Spec: fresh},
+	})
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/fluxcd/flux/pkg/git"
+	"github.com/fluxcd/flux/pkg/policy"
+)
+
+// releaseTags returns the tags in the repo that match the ref policy
+// given, newest (by semantic version) first.
+func releaseTags(ctx context.Context, repo *git.Repo, refPolicy policy.Pattern) ([]git.Tag, error) {
+	tags, err := repo.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matching []git.Tag
+	versions := map[string]*semver.Version{}
+	for _, tag := range tags {
+		if !refPolicy.Matches(tag.Name) {
+			continue
+		}
+		v, err := semver.NewVersion(tag.Name)
+		if err != nil {
+			continue
+		}
+		versions[tag.Name] = v
+		matching = append(matching, tag)
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return versions[matching[i].Name].GreaterThan(versions[matching[j].Name])
+	})
+	return matching, nil
+}
+
+// latestReleaseTag returns the newest tag matching the ref policy. If
+// a revision has been synced already, only tags that descend from it
+// are considered, so the sync never goes backwards; if there is no
+// newer tag, the revision synced is returned (with the name of the tag
+// at it, if any). If verify is true, tags without a valid signature
+// are passed over; only tags newer than the revision synced need be
+// verified, since that was verified before it was synced.
+func latestReleaseTag(ctx context.Context, repo *git.Repo, refPolicy policy.Pattern, synced string, verify bool) (git.Tag, error) {
+	tags, err := releaseTags(ctx, repo, refPolicy)
+	if err != nil {
+		return git.Tag{}, err
+	}
+	for _, tag := range tags {
+		if synced != "" {
+			if tag.Revision == synced {
+				return tag, nil
+			}
+			if ok, err := repo.IsAncestor(ctx, synced, tag.Revision); err != nil {
+				return git.Tag{}, err
+			} else if !ok {
+				continue
+			}
+		}
+		if verify {
+			if _, err := repo.VerifyTag(ctx, tag.Name); err != nil {
+				continue
+			}
+		}
+		return tag, nil
+	}
+	if synced != "" {
+		return git.Tag{Revision: synced}, nil
+	}
+	if verify {
+		return git.Tag{}, fmt.Errorf("no tag with a valid signature matches the ref policy %s", refPolicy)
+	}
+	return git.Tag{}, fmt.Errorf("no tag matches the ref policy %s", refPolicy)
+}
+
+// releaseTagAt returns the newest tag matching the ref policy that
+// points at the revision given, or an empty string if there is none.
+func releaseTagAt(ctx context.Context, repo *git.Repo, refPolicy policy.Pattern, revision string) (string, error) {
+	tags, err := releaseTags(ctx, repo, refPolicy)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag.Revision == revision {
+			return tag.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// syncTarget returns the revision that should be synced to the cluster,
+// if there are no unverified commits in the way: with a ref policy,
+// the newest release tag matching it (and the name of that tag) that
+// descends from the revision synced, otherwise the HEAD of the
+// configured branch.
+func syncTarget(ctx context.Context, repo *git.Repo, refPolicy policy.Pattern, synced string, verify bool) (string, string, error) {
+	if refPolicy == nil {
+		head, err := repo.BranchHead(ctx)
+		return head, "", err
+	}
+	tag, err := latestReleaseTag(ctx, repo, refPolicy, synced, verify)
+	if err != nil {
+		return "", "", err
+	}
+	return tag.Revision, tag.Name, nil
+}
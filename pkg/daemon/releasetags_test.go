@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/cluster/kubernetes/testfiles"
+	"github.com/fluxcd/flux/pkg/git/gittest"
+	"github.com/fluxcd/flux/pkg/policy"
+)
+
+func TestSyncTarget_RefPolicy(t *testing.T) {
+	repo, cleanup := gittest.Repo(t, testfiles.Files)
+	defer cleanup()
+	gitDir := strings.TrimPrefix(repo.Origin().URL, "file://")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	gitOutput := func(args ...string) string {
+		out, err := exec.Command("git", append([]string{"-C", gitDir, "-c", "user.name=example", "-c", "user.email=example@example.com"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("git %v: %s", args, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	// Add a commit on top of the branch, without needing a working
+	// tree, and tag it as given.
+	commitAndTag := func(tags ...string) string {
+		rev := gitOutput("commit-tree", "master^{tree}", "-p", "master", "-m", "release")
+		gitOutput("update-ref", "refs/heads/master", rev)
+		for _, tag := range tags {
+			gitOutput("tag", "-a", "-m", tag, tag, rev)
+		}
+		return rev
+	}
+
+	v1 := commitAndTag("v1.0.0")
+	v21 := commitAndTag("v2.1.0", "not-a-version")
+	v20 := commitAndTag("v2.0.0")
+	commitAndTag("v3.0.0-rc.1")
+	head := commitAndTag()
+
+	if err := repo.Ready(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		pattern  string
+		revision string
+		tag      string
+	}{
+		{"semver:^1.x", v1, "v1.0.0"},
+		{"semver:^2.x", v21, "v2.1.0"},
+		{"semver:~2.0", v20, "v2.0.0"},
+		{"semver:>=1.0.0", v21, "v2.1.0"},
+	} {
+		rev, tag, err := syncTarget(ctx, repo, policy.NewPattern(c.pattern), "", false)
+		if err != nil {
+			t.Errorf("%s: %s", c.pattern, err)
+			continue
+		}
+		if rev != c.revision || tag != c.tag {
+			t.Errorf("%s: expected %s (%s), got %s (%s)", c.pattern, c.tag, c.revision, tag, rev)
+		}
+	}
+
+	if _, _, err := syncTarget(ctx, repo, policy.NewPattern("semver:^4.x"), "", false); err == nil {
+		t.Error("expected an error when no tag matches the ref policy")
+	}
+	// None of the tags are signed
+	if _, _, err := syncTarget(ctx, repo, policy.NewPattern("semver:^2.x"), "", true); err == nil {
+		t.Error("expected an error when no matching tag has a valid signature")
+	}
+
+	rev, tag, err := syncTarget(ctx, repo, nil, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev != head || tag != "" {
+		t.Errorf("expected branch HEAD %s without a tag, got %s (%q)", head, rev, tag)
+	}
+
+	// Once v2.0.0 is synced, v2.1.0 (on an earlier commit) would take
+	// the sync backwards, so the sync stays where it is
+	if rev, tag, err := syncTarget(ctx, repo, policy.NewPattern("semver:>=1.0.0"), v20, false); err != nil || rev != v20 || tag != "v2.0.0" {
+		t.Errorf("expected to stay at v2.0.0 (%s), got %s (%s, err: %v)", v20, tag, rev, err)
+	}
+	// A synced tag was verified before it was synced, and isn't
+	// verified again
+	if rev, tag, err := syncTarget(ctx, repo, policy.NewPattern("semver:^2.x"), v21, true); err != nil || rev != v21 || tag != "v2.1.0" {
+		t.Errorf("expected the synced tag v2.1.0 (%s), got %s (%s, err: %v)", v21, tag, rev, err)
+	}
+	// With no tag newer than the revision synced, that is the target
+	if rev, tag, err := syncTarget(ctx, repo, policy.NewPattern("semver:^1.x"), v20, false); err != nil || rev != v20 || tag != "" {
+		t.Errorf("expected to stay at %s, got %s (%q, err: %v)", v20, rev, tag, err)
+	}
+
+	if tag, err := releaseTagAt(ctx, repo, policy.NewPattern("semver:^2.x"), v21); err != nil || tag != "v2.1.0" {
+		t.Errorf("expected tag v2.1.0 at %s, got %q (err: %v)", v21, tag, err)
+	}
+}
@@ -40,29 +40,38 @@ type changeSet struct {
 	commits     []git.Commit
 	oldTagRev   string
 	newTagRev   string
+	releaseTag  string
 	initialSync bool
 }
 
 // Sync starts the synchronization of the cluster with git.
 func (d *Daemon) Sync(ctx context.Context, started time.Time, newRevision string, rat ratchet) error {
+	_, err := d.syncRevision(ctx, started, newRevision, rat)
+	return err
+}
+
+// syncRevision synchronizes the cluster with the revision given, and
+// returns the resources applied, if it got that far, so they can be
+// looked at without loading them again.
+func (d *Daemon) syncRevision(ctx context.Context, started time.Time, newRevision string, rat ratchet) (map[string]resource.Resource, error) {
 	// Load last-synced resources for comparison
 	lastResources, err := d.getLastResources(ctx, rat)
 	if err != nil {
-		return errors.Wrap(err, "loading last-synced resources")
+		return nil, errors.Wrap(err, "loading last-synced resources")
 	}
 
 	// Retrieve change set of commits we need to sync
 	changeSet, err := d.getChangeSet(ctx, rat, newRevision)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	d.Logger.Log("info", "trying to sync git changes to the cluster", "old", changeSet.oldTagRev, "new", changeSet.newTagRev)
+	d.Logger.Log("info", "trying to sync git changes to the cluster", "old", changeSet.oldTagRev, "new", changeSet.newTagRev, "tag", changeSet.releaseTag)
 
 	// Load resources from the new revision
 	resourceStore, cleanup, err := d.getManifestStoreByRevision(ctx, newRevision)
 	if err != nil {
-		return errors.Wrap(err, "loading new resources")
+		return nil, errors.Wrap(err, "loading new resources")
 	}
 	defer cleanup()
 
@@ -70,7 +79,7 @@ func (d *Daemon) Sync(ctx context.Context, started time.Time, newRevision string
 	syncSetName := makeGitConfigHash(d.Repo.Origin(), d.GitConfig)
 	resources, resourceErrors, err := doSync(ctx, resourceStore, d.Cluster, syncSetName, d.Logger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Determine what resources changed and deleted during the sync
@@ -81,16 +90,16 @@ func (d *Daemon) Sync(ctx context.Context, started time.Time, newRevision string
 	// Retrieve git notes and collect events from them
 	notes, err := d.getNotes(ctx, d.GitTimeout)
 	if err != nil {
-		return err
+		return resources, err
 	}
 	noteEvents, includesEvents, err := d.collectNoteEvents(ctx, changeSet, notes, d.GitTimeout, started, d.Logger)
 	if err != nil {
-		return err
+		return resources, err
 	}
 
 	// Report all synced commits
 	if err := logCommitEvent(d, changeSet, updatedIDs, started, includesEvents, resourceErrors, d.Logger); err != nil {
-		return err
+		return resources, err
 	}
 
 	// Report all collected events
@@ -98,19 +107,18 @@ func (d *Daemon) Sync(ctx context.Context, started time.Time, newRevision string
 		if err = d.LogEvent(event); err != nil {
 			d.Logger.Log("err", err)
 			// Abort early to ensure at least once delivery of events
-			return err
+			return resources, err
 		}
 	}
 
 	// Move the revision the sync state points to
 	if ok, err := rat.Update(ctx, changeSet.oldTagRev, changeSet.newTagRev, resources); err != nil {
-		return err
+		return resources, err
 	} else if !ok {
-		return nil
+		return resources, nil
 	}
 
-	err = refresh(ctx, d.GitTimeout, d.Repo)
-	return err
+	return resources, refresh(ctx, d.GitTimeout, d.Repo)
 }
 
 // getLastResources loads last-synced resources
@@ -197,6 +205,12 @@ func (d *Daemon) getChangeSet(ctxGitOp context.Context, state ratchet, headRev s
 	c.oldTagRev = currentRev
 	c.newTagRev = headRev
 
+	if d.GitRefPolicy != nil {
+		if c.releaseTag, err = releaseTagAt(ctxGitOp, d.Repo, d.GitRefPolicy, headRev); err != nil {
+			return c, err
+		}
+	}
+
 	paths := d.GitConfig.Paths
 	if d.ManifestGenerationEnabled {
 		paths = []string{}
@@ -428,6 +442,7 @@ func logCommitEvent(el eventLogger, c changeSet, serviceIDs resource.IDSet, star
 			InitialSync: c.initialSync,
 			Includes:    includesEvents,
 			Errors:      resourceErrors,
+			Tag:         c.releaseTag,
 		},
 	}); err != nil {
 		logger.Log("err", err)
@@ -14,15 +14,17 @@ import (
 
 // These are all the types of events.
 const (
-	EventCommit       = "commit"
-	EventSync         = "sync"
-	EventRelease      = "release"
-	EventAutoRelease  = "autorelease"
-	EventAutomate     = "automate"
-	EventDeautomate   = "deautomate"
-	EventLock         = "lock"
-	EventUnlock       = "unlock"
-	EventUpdatePolicy = "update_policy"
+	EventCommit          = "commit"
+	EventSync            = "sync"
+	EventRelease         = "release"
+	EventAutoRelease     = "autorelease"
+	EventProposedRelease = "proposed_release"
+	EventUpdateAvailable = "update_available"
+	EventAutomate        = "automate"
+	EventDeautomate      = "deautomate"
+	EventLock            = "lock"
+	EventUnlock          = "unlock"
+	EventUpdatePolicy    = "update_policy"
 
 	// This is used to label e.g., commits that we _don't_ consider an event in themselves.
 	NoneOfTheAbove = "other"
@@ -131,6 +133,28 @@ func (e Event) String() string {
 			"Automated release of %s",
 			strings.Join(strImageIDs, ", "),
 		)
+	case EventProposedRelease:
+		metadata := e.Metadata.(*ProposedReleaseEventMetadata)
+		var strImageIDs []string
+		for _, change := range metadata.Spec.Changes {
+			strImageIDs = append(strImageIDs, change.ImageID.String())
+		}
+		sort.Strings(strImageIDs)
+		return fmt.Sprintf(
+			"Proposed release of %s, awaiting approval",
+			strings.Join(strImageIDs, ", "),
+		)
+	case EventUpdateAvailable:
+		metadata := e.Metadata.(*UpdateAvailableEventMetadata)
+		var strImageIDs []string
+		for _, change := range metadata.Spec.Changes {
+			strImageIDs = append(strImageIDs, change.ImageID.String())
+		}
+		sort.Strings(strImageIDs)
+		return fmt.Sprintf(
+			"Update available to %s",
+			strings.Join(strImageIDs, ", "),
+		)
 	case EventCommit:
 		metadata := e.Metadata.(*CommitEventMetadata)
 		svcStr := "<no changes>"
@@ -150,6 +174,9 @@ func (e Event) String() string {
 				shortRevision(metadata.Commits[0].Revision),
 			)
 		}
+		if metadata.Tag != "" {
+			revStr = fmt.Sprintf("%s (%s)", metadata.Tag, revStr)
+		}
 		svcStr := "no workloads changed"
 		if len(strWorkloadIDs) > 0 {
 			svcStr = strings.Join(strWorkloadIDs, ", ")
@@ -216,6 +243,9 @@ type SyncEventMetadata struct {
 	Errors []ResourceError `json:"errors,omitempty"`
 	// `true` if we have no record of having synced before
 	InitialSync bool `json:"initialSync,omitempty"`
+	// The release tag synced, when syncing from tags rather than a
+	// branch
+	Tag string `json:"tag,omitempty"`
 }
 
 // Account for old events, which used the revisions field rather than commits
@@ -315,6 +345,18 @@ type AutoReleaseEventMetadata struct {
 	Spec update.Automated `json:"spec"`
 }
 
+// ProposedReleaseEventMetadata is for when automation has found new
+// images for workloads(s), but the updates wait to be approved
+type ProposedReleaseEventMetadata struct {
+	Spec update.Automated `json:"spec"`
+}
+
+// UpdateAvailableEventMetadata is for when automation has found new
+// images for workload(s) that it only reports, without updating them
+type UpdateAvailableEventMetadata struct {
+	Spec update.Automated `json:"spec"`
+}
+
 type UnknownEventMetadata map[string]interface{}
 
 func (e *Event) UnmarshalJSON(in []byte) error {
@@ -350,6 +392,20 @@ func (e *Event) UnmarshalJSON(in []byte) error {
 		}
 		e.Metadata = &metadata
 		break
+	case EventProposedRelease:
+		var metadata ProposedReleaseEventMetadata
+		if err := json.Unmarshal(wireEvent.MetadataBytes, &metadata); err != nil {
+			return err
+		}
+		e.Metadata = &metadata
+		break
+	case EventUpdateAvailable:
+		var metadata UpdateAvailableEventMetadata
+		if err := json.Unmarshal(wireEvent.MetadataBytes, &metadata); err != nil {
+			return err
+		}
+		e.Metadata = &metadata
+		break
 	case EventCommit:
 		var metadata CommitEventMetadata
 		if err := json.Unmarshal(wireEvent.MetadataBytes, &metadata); err != nil {
@@ -403,6 +459,14 @@ func (rem *AutoReleaseEventMetadata) Type() string {
 	return EventAutoRelease
 }
 
+func (pem *ProposedReleaseEventMetadata) Type() string {
+	return EventProposedRelease
+}
+
+func (uem *UpdateAvailableEventMetadata) Type() string {
+	return EventUpdateAvailable
+}
+
 // Special exception from pointer receiver rule, as UnknownEventMetadata is a
 // type alias for a map
 func (uem UnknownEventMetadata) Type() string {
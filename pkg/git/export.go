@@ -4,10 +4,16 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// emptyTree is the well-known ID of git's empty tree, for diffing
+// against when there is no earlier revision.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbc4904c"
+
 type Export struct {
-	dir string
+	dir        string
+	submodules bool
 }
 
 func (e *Export) Dir() string {
@@ -30,7 +36,12 @@ func (r *Repo) Export(ctx context.Context, ref string) (*Export, error) {
 	if err = checkout(ctx, dir, ref); err != nil {
 		return nil, err
 	}
-	return &Export{dir}, nil
+	if r.submodules {
+		if err = updateSubmodules(ctx, dir, r.Origin().URL); err != nil {
+			return nil, err
+		}
+	}
+	return &Export{dir: dir, submodules: r.submodules}, nil
 }
 
 // SecretUnseal unseals git secrets in the clone.
@@ -38,9 +49,14 @@ func (e *Export) SecretUnseal(ctx context.Context) error {
 	return secretUnseal(ctx, e.Dir())
 }
 
-// ChangedFiles does a git diff listing changed files
+// ChangedFiles does a git diff listing changed files. If submodules
+// are enabled, files changed within a submodule (since the revision it
+// pointed to at `sinceRef`) are listed in place of the submodule itself.
 func (e *Export) ChangedFiles(ctx context.Context, sinceRef string, paths []string) ([]string, error) {
 	list, err := changed(ctx, e.Dir(), sinceRef, paths)
+	if err == nil && e.submodules {
+		list, err = e.withSubmoduleChanges(ctx, sinceRef, paths, list)
+	}
 	if err == nil {
 		for i, file := range list {
 			list[i] = filepath.Join(e.Dir(), file)
@@ -48,3 +64,70 @@ func (e *Export) ChangedFiles(ctx context.Context, sinceRef string, paths []stri
 	}
 	return list, err
 }
+
+// withSubmoduleChanges replaces any submodule in the list of changed
+// files with the files that changed within it, restricted to the paths
+// given.
+func (e *Export) withSubmoduleChanges(ctx context.Context, sinceRef string, paths []string, list []string) ([]string, error) {
+	submodules, err := submodulePaths(ctx, e.Dir(), "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	isSubmodule := map[string]bool{}
+	for _, sub := range submodules {
+		isSubmodule[sub] = true
+	}
+	for _, file := range list {
+		if !isSubmodule[file] {
+			result = append(result, file)
+		}
+	}
+
+	for _, sub := range submodules {
+		subPaths, ok := pathsWithin(sub, paths)
+		if !ok {
+			continue
+		}
+		subDir := filepath.Join(e.Dir(), sub)
+		since, err := submoduleRevision(ctx, e.Dir(), sinceRef, sub)
+		if err != nil {
+			return nil, err
+		}
+		if since == "" {
+			// The submodule was added since `sinceRef`, so
+			// everything in it is new.
+			since = emptyTree
+		}
+		subChanged, err := changed(ctx, subDir, since, subPaths)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range subChanged {
+			result = append(result, filepath.Join(sub, file))
+		}
+	}
+	return result, nil
+}
+
+// pathsWithin translates the paths given into paths relative to the
+// submodule at `sub`, and reports whether any part of the submodule
+// is covered by them at all.
+func pathsWithin(sub string, paths []string) ([]string, bool) {
+	if len(paths) == 0 {
+		return nil, true
+	}
+	var subPaths []string
+	for _, p := range paths {
+		p = strings.TrimSuffix(p, "/")
+		switch {
+		case p == sub || strings.HasPrefix(sub, p+"/"):
+			// the whole submodule is included
+			return nil, true
+		case strings.HasPrefix(p, sub+"/"):
+			subPaths = append(subPaths, strings.TrimPrefix(p, sub+"/"))
+		}
+	}
+	return subPaths, len(subPaths) > 0
+}
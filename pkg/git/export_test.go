@@ -2,6 +2,9 @@ package git
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -42,3 +45,110 @@ func TestExportAtRevision(t *testing.T) {
 		t.Errorf("exported %s, but head in export dir %s is %s", headMinusOne, export.dir, exportHead)
 	}
 }
+
+func TestExportWithSubmodules(t *testing.T) {
+	// Recent versions of git refuse to clone submodules from local
+	// paths unless told otherwise; since the git commands only
+	// inherit some environment entries, do that via $HOME.
+	home, homeCleanup := testfiles.TempDir(t)
+	defer homeCleanup()
+	if err := ioutil.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[protocol \"file\"]\n\tallow = always\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", oldHome)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	subDir, subCleanup := testfiles.TempDir(t)
+	defer subCleanup()
+	if err := createRepo(subDir, []string{"base"}); err != nil {
+		t.Fatal(err)
+	}
+
+	superDir, superCleanup := testfiles.TempDir(t)
+	defer superCleanup()
+	if err := createRepo(superDir, []string{"config"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCommand("git", "-C", superDir, "submodule", "add", subDir, "shared"); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCommand("git", "-C", superDir, "commit", "-m", "Add submodule"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewRepo(Remote{URL: superDir}, ReadOnly, RecurseSubmodules(true))
+	if err := repo.Ready(ctx); err != nil {
+		t.Fatal(err)
+	}
+	oldHead, err := repo.Revision(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export, err := repo.Export(ctx, oldHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer export.Clean()
+	if _, err := os.Stat(filepath.Join(export.Dir(), "shared", "base", "helloworld-deploy.yaml")); err != nil {
+		t.Errorf("expected submodule to be checked out in export: %s", err)
+	}
+
+	// Bump the submodule, and check that it is seen both in the
+	// commits and in the changed files, for a path within the
+	// submodule.
+	if err := updateDirAndCommit(subDir, "base", testfiles.FilesUpdated); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCommand("git", "-C", superDir, "submodule", "update", "--remote", "shared"); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCommand("git", "-C", superDir, "commit", "-a", "-m", "Bump submodule"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	newHead, err := repo.Revision(ctx, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := repo.CommitsBetween(ctx, oldHead, newHead, false, "shared/base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Revision != newHead {
+		t.Errorf("expected submodule bump %s in commits, got %v", newHead, commits)
+	}
+
+	newExport, err := repo.Export(ctx, newHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer newExport.Clean()
+	files, err := newExport.ChangedFiles(ctx, oldHead, []string{"shared/base"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(newExport.Dir(), "shared", "base", "helloworld-deploy.yaml")}
+	if len(files) != len(expected) || files[0] != expected[0] {
+		t.Errorf("expected changed files %v, got %v", expected, files)
+	}
+
+	// Failing to read the submodules is an error, rather than
+	// there being no changes.
+	if _, err := submodulePaths(ctx, newExport.Dir(), "no-such-ref"); err == nil {
+		t.Error("expected an error reading submodules at a missing ref")
+	}
+	if _, err := submoduleRevision(ctx, newExport.Dir(), "no-such-ref", "shared"); err == nil {
+		t.Error("expected an error reading a submodule at a missing ref")
+	}
+	if rev, err := submoduleRevision(ctx, newExport.Dir(), oldHead, "config"); err != nil || rev != "" {
+		t.Errorf("expected no submodule at a plain directory, got %q, %v", rev, err)
+	}
+}
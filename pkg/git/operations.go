@@ -95,6 +95,80 @@ func checkout(ctx context.Context, workingDir, ref string) error {
 	return nil
 }
 
+// updateSubmodules initialises and updates any submodules registered
+// in the working clone, recursively. Submodule URLs given relative to
+// the superproject are resolved against `upstream` rather than
+// whichever local repo the working clone was made from.
+func updateSubmodules(ctx context.Context, workingDir, upstream string) error {
+	args := []string{"-c", "remote.origin.url=" + upstream, "submodule", "init"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir}); err != nil {
+		return errors.Wrap(err, "git submodule init")
+	}
+	args = []string{"submodule", "update", "--init", "--recursive"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir}); err != nil {
+		return errors.Wrap(err, "git submodule update --init --recursive")
+	}
+	return nil
+}
+
+// submodulePaths returns the paths of the submodules registered in
+// `.gitmodules` as of the ref given. It works in bare repos as well as
+// in working clones, and returns an empty list if there is no
+// `.gitmodules` file at that ref.
+func submodulePaths(ctx context.Context, workingDir, ref string) ([]string, error) {
+	if entry, err := treeEntry(ctx, workingDir, ref, ".gitmodules"); err != nil {
+		return nil, err
+	} else if entry == "" {
+		return []string{}, nil
+	}
+	out := &bytes.Buffer{}
+	args := []string{"config", "--blob", ref + ":.gitmodules", "--get-regexp", `^submodule\..*\.path$`}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		// `git config` exits with status 1, without output, when
+		// there are no matching keys
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, line := range splitList(out.String()) {
+		if fields := strings.SplitN(line, " ", 2); len(fields) == 2 {
+			paths = append(paths, fields[1])
+		}
+	}
+	return paths, nil
+}
+
+// submoduleRevision returns the commit the submodule at `path` points
+// to, as of the ref given, or an empty string if there was no
+// submodule at `path` then.
+func submoduleRevision(ctx context.Context, workingDir, ref, path string) (string, error) {
+	entry, err := treeEntry(ctx, workingDir, ref, path)
+	if err != nil {
+		return "", err
+	}
+	// <mode> SP <type> SP <object> TAB <path>
+	fields := strings.Fields(entry)
+	if len(fields) < 3 || fields[1] != "commit" {
+		return "", nil
+	}
+	return fields[2], nil
+}
+
+// treeEntry returns the `git ls-tree` entry for the path given, as of
+// the ref given, or an empty string if there is no such path. Unlike
+// e.g., `git cat-file -e`, this tells a missing path apart from a
+// ref that can't be read.
+func treeEntry(ctx context.Context, workingDir, ref, path string) (string, error) {
+	out := &bytes.Buffer{}
+	args := []string{"ls-tree", ref, "--", path}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
 func add(ctx context.Context, workingDir, path string) error {
 	args := []string{"add", "--", path}
 	return execGitCmd(ctx, args, gitCmdConfig{dir: workingDir})
@@ -133,6 +207,13 @@ func deleteTag(ctx context.Context, workingDir, tag, upstream string) error {
 	return execGitCmd(ctx, args, gitCmdConfig{dir: workingDir})
 }
 
+// deleteLocalTag deletes the given git tag from the repo in
+// workingDir, without touching any upstream.
+func deleteLocalTag(ctx context.Context, workingDir, tag string) error {
+	args := []string{"tag", "--delete", tag}
+	return execGitCmd(ctx, args, gitCmdConfig{dir: workingDir})
+}
+
 func secretUnseal(ctx context.Context, workingDir string) error {
 	args := []string{"secret", "reveal", "-f"}
 	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir}); err != nil {
@@ -274,10 +355,20 @@ func refRevision(ctx context.Context, workingDir, ref string) (string, error) {
 	return strings.TrimSpace(out.String()), nil
 }
 
+// Get the best common ancestor of two refs
+func mergeBase(ctx context.Context, workingDir, ref1, ref2 string) (string, error) {
+	out := &bytes.Buffer{}
+	args := []string{"merge-base", ref1, ref2}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
 // Return the revisions and one-line log commit messages
 func onelinelog(ctx context.Context, workingDir, refspec string, subdirs []string, firstParent bool) ([]Commit, error) {
 	out := &bytes.Buffer{}
-	args := []string{"log", "--pretty=format:%GK|%G?|%H|%s"}
+	args := []string{"log", "--pretty=format:%GK|%G?|%GS|%H|%s"}
 
 	if firstParent {
 		args = append(args, "--first-parent")
@@ -300,13 +391,14 @@ func splitLog(s string) ([]Commit, error) {
 	lines := splitList(s)
 	commits := make([]Commit, len(lines))
 	for i, m := range lines {
-		parts := strings.SplitN(m, "|", 4)
+		parts := strings.SplitN(m, "|", 5)
 		commits[i].Signature = Signature{
 			Key:    parts[0],
 			Status: parts[1],
+			Signer: parts[2],
 		}
-		commits[i].Revision = parts[2]
-		commits[i].Message = parts[3]
+		commits[i].Revision = parts[3]
+		commits[i].Message = parts[4]
 	}
 	return commits, nil
 }
@@ -337,6 +429,30 @@ func moveTagAndPush(ctx context.Context, workingDir, upstream string, action Tag
 	return nil
 }
 
+// List all tags, with the commits they point at; for annotated tags,
+// that is the object the tag object itself points at.
+func listTags(ctx context.Context, workingDir string) ([]Tag, error) {
+	out := &bytes.Buffer{}
+	args := []string{"for-each-ref", "--format", "%(refname:strip=2) %(objectname) %(*objectname)", "refs/tags"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return nil, errors.Wrap(err, "listing tags")
+	}
+	lines := splitList(out.String())
+	tags := make([]Tag, 0, len(lines))
+	for _, l := range lines {
+		fields := strings.Fields(l)
+		if len(fields) < 2 {
+			continue
+		}
+		tag := Tag{Name: fields[0], Revision: fields[1]}
+		if len(fields) > 2 {
+			tag.Revision = fields[2]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
 // Verify tag signature and return the revision it points to
 func verifyTag(ctx context.Context, workingDir, tag string) (string, error) {
 	out := &bytes.Buffer{}
@@ -351,11 +467,45 @@ func verifyTag(ctx context.Context, workingDir, tag string) (string, error) {
 func verifyCommit(ctx context.Context, workingDir, commit string) error {
 	args := []string{"verify-commit", commit}
 	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir}); err != nil {
+		// Say who signed it, if anyone, so it's clear which key
+		// needs to be trusted (or which commit needs re-signing).
+		if c, err := commitInfo(ctx, workingDir, commit); err == nil {
+			return fmt.Errorf("failed to verify commit %s (%s)", commit, c.Signature.Identity())
+		}
 		return fmt.Errorf("failed to verify commit %s", commit)
 	}
 	return nil
 }
 
+// commitInfo returns the signature and one-line message of the commit
+// given.
+func commitInfo(ctx context.Context, workingDir, rev string) (Commit, error) {
+	out := &bytes.Buffer{}
+	args := []string{"log", "-1", "--pretty=format:%GK|%G?|%GS|%H|%s", rev, "--"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return Commit{}, err
+	}
+	commits, err := splitLog(out.String())
+	if err != nil {
+		return Commit{}, err
+	}
+	if len(commits) != 1 {
+		return Commit{}, fmt.Errorf("no commit %s", rev)
+	}
+	return commits[0], nil
+}
+
+// configAllowedSigners points git at the file listing the SSH keys
+// trusted to sign commits and tags, so that SSH signatures can be
+// verified.
+func configAllowedSigners(ctx context.Context, workingDir, path string) error {
+	args := []string{"config", "gpg.ssh.allowedSignersFile", path}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir}); err != nil {
+		return errors.Wrap(err, "setting git config")
+	}
+	return nil
+}
+
 func changed(ctx context.Context, workingDir, ref string, subPaths []string) ([]string, error) {
 	out := &bytes.Buffer{}
 	// This uses --diff-filter to only look at changes for file _in
@@ -373,6 +523,34 @@ func changed(ctx context.Context, workingDir, ref string, subPaths []string) ([]
 	return splitList(out.String()), nil
 }
 
+// changedIn lists the files changed (including those deleted) by the
+// commit given, compared to its first parent.
+func changedIn(ctx context.Context, workingDir, rev string) ([]string, error) {
+	parent := emptyTree
+	out := &bytes.Buffer{}
+	args := []string{"rev-parse", "--verify", "--quiet", rev + "^"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err == nil {
+		parent = strings.TrimSpace(out.String())
+	}
+
+	out = &bytes.Buffer{}
+	args = []string{"diff", "--name-only", parent, rev, "--"}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return nil, err
+	}
+	return splitList(out.String()), nil
+}
+
+// show returns the contents of the file at `path` as of the ref given.
+func show(ctx context.Context, workingDir, ref, path string) ([]byte, error) {
+	out := &bytes.Buffer{}
+	args := []string{"show", ref + ":" + path}
+	if err := execGitCmd(ctx, args, gitCmdConfig{dir: workingDir, out: out}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // traceGitCommand returns a log line that can be useful when debugging and developing git activity
 func traceGitCommand(args []string, config gitCmdConfig, stdOutAndStdErr string) string {
 	for _, exemptedCommand := range exemptedTraceCommands {
@@ -57,6 +57,9 @@ type Repo struct {
 	timeout  time.Duration
 	readonly bool
 
+	submodules     bool
+	allowedSigners string
+
 	// State
 	mu     sync.RWMutex
 	status GitRepoStatus
@@ -103,6 +106,25 @@ func (ro IsReadOnly) apply(r *Repo) {
 
 var ReadOnly IsReadOnly = true
 
+// AllowedSignersFile is the path to a file, in the format of
+// ssh-keygen(1)'s ALLOWED SIGNERS, listing the SSH keys trusted to
+// sign commits and tags. Without it, SSH signatures cannot be
+// verified.
+type AllowedSignersFile string
+
+func (f AllowedSignersFile) apply(r *Repo) {
+	r.allowedSigners = string(f)
+}
+
+// RecurseSubmodules makes working clones and exports of the repo
+// initialise and update any git submodules, and makes the commit and
+// change listings take submodule pointer changes into account.
+type RecurseSubmodules bool
+
+func (s RecurseSubmodules) apply(r *Repo) {
+	r.submodules = bool(s)
+}
+
 // NewRepo constructs a repo mirror which will sync itself.
 func NewRepo(origin Remote, opts ...Option) *Repo {
 	status := RepoNew
@@ -238,6 +260,10 @@ func (r *Repo) CommitsBefore(ctx context.Context, ref string, firstParent bool,
 	if err := r.errorIfNotReady(); err != nil {
 		return nil, err
 	}
+	paths, err := r.withSubmodulePaths(ctx, ref, paths)
+	if err != nil {
+		return nil, err
+	}
 	return onelinelog(ctx, r.dir, ref, paths, firstParent)
 }
 
@@ -247,9 +273,89 @@ func (r *Repo) CommitsBetween(ctx context.Context, ref1, ref2 string, firstParen
 	if err := r.errorIfNotReady(); err != nil {
 		return nil, err
 	}
+	paths, err := r.withSubmodulePaths(ctx, ref2, paths)
+	if err != nil {
+		return nil, err
+	}
 	return onelinelog(ctx, r.dir, ref1+".."+ref2, paths, firstParent)
 }
 
+// withSubmodulePaths adds to the paths given those of any submodules
+// (as of `ref`) that contain one of them. A commit that only bumps a
+// submodule changes nothing but the submodule's own path, so without
+// this, paths pointing into a submodule would never see it.
+func (r *Repo) withSubmodulePaths(ctx context.Context, ref string, paths []string) ([]string, error) {
+	if !r.submodules || len(paths) == 0 {
+		return paths, nil
+	}
+	submodules, err := submodulePaths(ctx, r.dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	result := append([]string{}, paths...)
+	for _, sub := range submodules {
+		for _, p := range paths {
+			if strings.HasPrefix(p, sub+"/") {
+				result = append(result, sub)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// IsAncestor reports whether the commit `ancestor` is reachable from
+// (or the same as) the commit `rev`.
+func (r *Repo) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := r.errorIfNotReady(); err != nil {
+		return false, err
+	}
+	ancestorRev, err := refRevision(ctx, r.dir, ancestor)
+	if err != nil {
+		return false, err
+	}
+	base, err := mergeBase(ctx, r.dir, ancestor, rev)
+	if err != nil {
+		return false, err
+	}
+	return base == ancestorRev, nil
+}
+
+// FilesChangedIn returns the files changed by the commit given,
+// compared to its first parent.
+func (r *Repo) FilesChangedIn(ctx context.Context, rev string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := r.errorIfNotReady(); err != nil {
+		return nil, err
+	}
+	return changedIn(ctx, r.dir, rev)
+}
+
+// ReadFile returns the contents of the file at `path` (relative to
+// the root of the repo), as of the revision given.
+func (r *Repo) ReadFile(ctx context.Context, rev, path string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := r.errorIfNotReady(); err != nil {
+		return nil, err
+	}
+	return show(ctx, r.dir, rev, path)
+}
+
+// Tags returns all the tags in the repo, and the commits they point
+// at.
+func (r *Repo) Tags(ctx context.Context) ([]Tag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := r.errorIfNotReady(); err != nil {
+		return nil, err
+	}
+	return listTags(ctx, r.dir)
+}
+
 func (r *Repo) VerifyTag(ctx context.Context, tag string) (string, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -259,6 +365,16 @@ func (r *Repo) VerifyTag(ctx context.Context, tag string) (string, error) {
 	return verifyTag(ctx, r.dir, tag)
 }
 
+// Commit returns the commit given, with its signature.
+func (r *Repo) Commit(ctx context.Context, rev string) (Commit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if err := r.errorIfNotReady(); err != nil {
+		return Commit{}, err
+	}
+	return commitInfo(ctx, r.dir, rev)
+}
+
 func (r *Repo) VerifyCommit(ctx context.Context, commit string) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -274,7 +390,12 @@ func (r *Repo) DeleteTag(ctx context.Context, tag string) error {
 	if err := r.errorIfNotReady(); err != nil {
 		return err
 	}
-	return deleteTag(ctx, r.dir, tag, r.origin.URL)
+	if err := deleteTag(ctx, r.dir, tag, r.origin.URL); err != nil {
+		return err
+	}
+	// Fetching will not prune the tag from the mirror, so remove it
+	// here as well.
+	return deleteLocalTag(ctx, r.dir, tag)
 }
 
 func (r *Repo) NoteRevList(ctx context.Context, notesRef string) (map[string]struct{}, error) {
@@ -310,6 +431,9 @@ func (r *Repo) step(bg context.Context) bool {
 
 		ctx, cancel := context.WithTimeout(bg, r.timeout)
 		dir, err = mirror(ctx, rootdir, url)
+		if err == nil && r.allowedSigners != "" {
+			err = configAllowedSigners(ctx, dir, r.allowedSigners)
+		}
 		cancel()
 		if err == nil {
 			r.mu.Lock()
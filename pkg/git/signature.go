@@ -1,9 +1,13 @@
 package git
 
-// Signature holds information about a GPG signature.
+// Signature holds information about a GPG or SSH signature.
 type Signature struct {
 	Key    string
 	Status string
+	// Signer is the identity of the signer as reported by git; for
+	// SSH signatures, this is the principal given in the allowed
+	// signers file, and is empty if the key is not in there.
+	Signer string
 }
 
 // Valid returns true if the signature is _G_ood (valid).
@@ -11,3 +15,17 @@ type Signature struct {
 func (s *Signature) Valid() bool {
 	return s.Status == "G"
 }
+
+// Identity returns the best description available of who signed the
+// commit: the signer if known, otherwise the key, and failing that,
+// that it was not signed at all.
+func (s *Signature) Identity() string {
+	switch {
+	case s.Signer != "":
+		return s.Signer
+	case s.Key != "":
+		return "unknown signer with key " + s.Key
+	default:
+		return "unsigned"
+	}
+}
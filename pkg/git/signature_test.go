@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/cluster/kubernetes/testfiles"
+)
+
+func sshKey(t *testing.T, dir, name string) string {
+	keyPath := filepath.Join(dir, name)
+	if err := execCommand("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", name, "-f", keyPath); err != nil {
+		t.Fatal(err)
+	}
+	return keyPath
+}
+
+func sshSignedCommit(dir, keyPath, message string) error {
+	return execCommand("git", "-C", dir, "-c", "gpg.format=ssh", "-c", "user.signingkey="+keyPath,
+		"commit", "--allow-empty", "-S", "-m", message)
+}
+
+func TestSSHSignatureVerification(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	keysDir, keysCleanup := testfiles.TempDir(t)
+	defer keysCleanup()
+	trusted := sshKey(t, keysDir, "trusted")
+	untrusted := sshKey(t, keysDir, "untrusted")
+
+	pub, err := ioutil.ReadFile(trusted + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowedSigners := filepath.Join(keysDir, "allowed_signers")
+	if err := ioutil.WriteFile(allowedSigners, []byte(fmt.Sprintf("alice@example.com %s", pub)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	upstreamDir, upstreamCleanup := testfiles.TempDir(t)
+	defer upstreamCleanup()
+	if err := createRepo(upstreamDir, []string{"config"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sshSignedCommit(upstreamDir, trusted, "Trusted"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sshSignedCommit(upstreamDir, untrusted, "Untrusted"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewRepo(Remote{URL: upstreamDir}, ReadOnly, AllowedSignersFile(allowedSigners))
+	if err := repo.Ready(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := repo.CommitsBefore(ctx, "HEAD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) < 2 {
+		t.Fatalf("expected at least two commits, got %v", commits)
+	}
+
+	untrustedCommit, trustedCommit := commits[0], commits[1]
+	if untrustedCommit.Signature.Valid() {
+		t.Errorf("expected signature by key not in allowed signers to be invalid, got %+v", untrustedCommit.Signature)
+	}
+	if !strings.HasPrefix(untrustedCommit.Signature.Identity(), "unknown signer with key SHA256:") {
+		t.Errorf("expected untrusted signer to be identified by key, got %q", untrustedCommit.Signature.Identity())
+	}
+	if !trustedCommit.Signature.Valid() {
+		t.Errorf("expected signature by allowed signer to be valid, got %+v", trustedCommit.Signature)
+	}
+	if trustedCommit.Signature.Identity() != "alice@example.com" {
+		t.Errorf("expected signer alice@example.com, got %q", trustedCommit.Signature.Identity())
+	}
+
+	if err := repo.VerifyCommit(ctx, trustedCommit.Revision); err != nil {
+		t.Error(err)
+	}
+	if err := repo.VerifyCommit(ctx, untrustedCommit.Revision); err == nil {
+		t.Error("expected verification of commit signed with untrusted key to fail")
+	} else if !strings.Contains(err.Error(), "unknown signer with key SHA256:") {
+		t.Errorf("expected verification error to name the key, got %q", err)
+	}
+}
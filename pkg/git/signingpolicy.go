@@ -0,0 +1,115 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// SigningPolicy says which keys may sign changes to which files, in
+// the manner of a CODEOWNERS file. Each line has a path glob followed
+// by the fingerprints of the keys allowed to sign changes to matching
+// files, e.g.,
+//
+//	# production needs sign-off from the ops team
+//	clusters/prod/*  SHA256:0yQ7...  700D397C988079BFF0DDAFED6A7436E8790F8689
+//	# ... except for the dashboards
+//	clusters/prod/dashboards
+//
+// A glob matches a file if it matches (as with `path.Match`) the
+// whole path of the file, or any of its leading directories. As with
+// CODEOWNERS, the last matching line wins; a line with no keys leaves
+// matching files unprotected. Blank lines and lines starting with `#`
+// are ignored.
+type SigningPolicy struct {
+	rules []signingRule
+}
+
+type signingRule struct {
+	pattern string
+	keys    []string
+}
+
+// ParseSigningPolicy reads a signing policy.
+func ParseSigningPolicy(r io.Reader) (*SigningPolicy, error) {
+	var policy SigningPolicy
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		pattern := strings.Trim(fields[0], "/")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("signing policy line %d: invalid pattern %q: %s", n, fields[0], err)
+		}
+		policy.rules = append(policy.rules, signingRule{pattern: pattern, keys: fields[1:]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// AllowedKeys returns the keys allowed to sign changes to the file
+// given, and whether the file is protected at all.
+func (p *SigningPolicy) AllowedKeys(file string) ([]string, bool) {
+	for i := len(p.rules) - 1; i >= 0; i-- {
+		if p.rules[i].matches(file) {
+			return p.rules[i].keys, len(p.rules[i].keys) > 0
+		}
+	}
+	return nil, false
+}
+
+// Violation returns the first of the files given that a commit with
+// the signature given is not allowed to change, if there is one.
+func (p *SigningPolicy) Violation(sig Signature, files []string) (string, bool) {
+	for _, file := range files {
+		keys, protected := p.AllowedKeys(file)
+		if !protected {
+			continue
+		}
+		if !sig.Valid() {
+			return file, true
+		}
+		allowed := false
+		for _, key := range keys {
+			if keyMatches(key, sig.Key) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+func (r signingRule) matches(file string) bool {
+	for p := strings.Trim(file, "/"); p != "." && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(r.pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// keyMatches says whether the key git reports as having made a
+// signature is the key given in a policy. SSH key fingerprints must
+// match exactly; GPG keys may be given as a full fingerprint, while
+// git reports the (long) key ID, which is its tail.
+func keyMatches(allowed, key string) bool {
+	if key == "" {
+		return false
+	}
+	if strings.HasPrefix(allowed, "SHA256:") {
+		return allowed == key
+	}
+	allowed, key = strings.ToUpper(allowed), strings.ToUpper(key)
+	return strings.HasSuffix(allowed, key)
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSigningPolicy = `
+# production needs sign-off
+clusters/prod/*   SHA256:prodkey  700D397C988079BFF0DDAFED6A7436E8790F8689
+# ... except for the dashboards
+clusters/prod/dashboards
+.policy           SHA256:securitykey
+`
+
+func TestSigningPolicy_Violation(t *testing.T) {
+	policy, err := ParseSigningPolicy(strings.NewReader(testSigningPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prodSSH := Signature{Key: "SHA256:prodkey", Status: "G"}
+	prodGPG := Signature{Key: "6A7436E8790F8689", Status: "G"}
+	other := Signature{Key: "SHA256:otherkey", Status: "G"}
+	badProd := Signature{Key: "SHA256:prodkey", Status: "B"}
+	unsigned := Signature{Status: "N"}
+
+	for _, c := range []struct {
+		name      string
+		sig       Signature
+		files     []string
+		violation string
+	}{
+		{"unprotected files need no signature", unsigned, []string{"clusters/dev/app.yaml", "README.md"}, ""},
+		{"protected file signed by allowed SSH key", prodSSH, []string{"clusters/prod/app.yaml"}, ""},
+		{"protected file signed by allowed GPG key ID", prodGPG, []string{"clusters/prod/app/deploy.yaml"}, ""},
+		{"protected file signed by other key", other, []string{"clusters/dev/app.yaml", "clusters/prod/app.yaml"}, "clusters/prod/app.yaml"},
+		{"protected file with bad signature", badProd, []string{"clusters/prod/app.yaml"}, "clusters/prod/app.yaml"},
+		{"protected file unsigned", unsigned, []string{"clusters/prod/app.yaml"}, "clusters/prod/app.yaml"},
+		{"later line unprotects", unsigned, []string{"clusters/prod/dashboards/grafana.yaml"}, ""},
+		{"policy protects itself", prodSSH, []string{".policy"}, ".policy"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			path, violated := policy.Violation(c.sig, c.files)
+			if violated != (c.violation != "") || path != c.violation {
+				t.Errorf("expected violation %q, got %q (%v)", c.violation, path, violated)
+			}
+		})
+	}
+}
+
+func TestSigningPolicy_InvalidPattern(t *testing.T) {
+	if _, err := ParseSigningPolicy(strings.NewReader("clusters/[prod SHA256:key\n")); err == nil {
+		t.Error("expected error for malformed glob")
+	}
+}
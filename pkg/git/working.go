@@ -40,6 +40,13 @@ type Commit struct {
 	Message   string
 }
 
+// Tag is a tag in the repo, and the revision of the commit it
+// points at.
+type Tag struct {
+	Name     string
+	Revision string
+}
+
 // CommitAction is a struct holding commit information
 type CommitAction struct {
 	Author     string
@@ -72,6 +79,13 @@ func (r *Repo) Clone(ctx context.Context, conf Config) (*Checkout, error) {
 		return nil, err
 	}
 
+	if r.submodules {
+		if err := updateSubmodules(ctx, repoDir, upstream.URL); err != nil {
+			os.RemoveAll(repoDir)
+			return nil, err
+		}
+	}
+
 	// We'll need the notes ref for pushing it, so make sure we have
 	// it. This assumes we're syncing it (otherwise we'll likely get conflicts)
 	realNotesRef, err := getNotesRef(ctx, repoDir, conf.NotesRef)
@@ -101,7 +115,7 @@ func (r *Repo) Clone(ctx context.Context, conf Config) (*Checkout, error) {
 	r.mu.RUnlock()
 
 	return &Checkout{
-		Export:       &Export{dir: repoDir},
+		Export:       &Export{dir: repoDir, submodules: r.submodules},
 		upstream:     upstream,
 		realNotesRef: realNotesRef,
 		config:       conf,
@@ -188,7 +202,13 @@ func (c *Checkout) MoveTagAndPush(ctx context.Context, tagAction TagAction) erro
 }
 
 func (c *Checkout) Checkout(ctx context.Context, rev string) error {
-	return checkout(ctx, c.Dir(), rev)
+	if err := checkout(ctx, c.Dir(), rev); err != nil {
+		return err
+	}
+	if c.submodules {
+		return updateSubmodules(ctx, c.Dir(), c.upstream.URL)
+	}
+	return nil
 }
 
 func (c *Checkout) Add(ctx context.Context, path string) error {
@@ -15,6 +15,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	fluxerr "github.com/fluxcd/flux/pkg/errors"
@@ -130,6 +132,22 @@ func (c *Client) GitRepoConfig(ctx context.Context, regenerate bool) (v6.GitConf
 	return res, err
 }
 
+func (c *Client) PinRevision(ctx context.Context, revision string) error {
+	return c.PostWithBody(ctx, transport.PinRevision, revision)
+}
+
+func (c *Client) ListPendingUpdates(ctx context.Context) ([]v13.PendingUpdate, error) {
+	var res []v13.PendingUpdate
+	err := c.Get(ctx, &res, transport.ListPendingUpdates)
+	return res, err
+}
+
+func (c *Client) ListDeferredUpdates(ctx context.Context) ([]v14.DeferredUpdate, error) {
+	var res []v14.DeferredUpdate
+	err := c.Get(ctx, &res, transport.ListDeferredUpdates)
+	return res, err
+}
+
 // --- Request helpers
 
 // Post is a simple query-param only post request
@@ -54,7 +54,7 @@ func NewHandler(s api.Server, r *mux.Router) http.Handler {
 	r.Get(transport.Version).HandlerFunc(handle.Version)
 	r.Get(transport.Notify).HandlerFunc(handle.Notify)
 
-	// v6-v11 handlers
+	// v6-v14 handlers
 	r.Get(transport.ListServices).HandlerFunc(handle.ListServicesWithOptions)
 	r.Get(transport.ListServicesWithOptions).HandlerFunc(handle.ListServicesWithOptions)
 	r.Get(transport.ListImages).HandlerFunc(handle.ListImagesWithOptions)
@@ -64,6 +64,9 @@ func NewHandler(s api.Server, r *mux.Router) http.Handler {
 	r.Get(transport.SyncStatus).HandlerFunc(handle.SyncStatus)
 	r.Get(transport.Export).HandlerFunc(handle.Export)
 	r.Get(transport.GitRepoConfig).HandlerFunc(handle.GitRepoConfig)
+	r.Get(transport.PinRevision).HandlerFunc(handle.PinRevision)
+	r.Get(transport.ListPendingUpdates).HandlerFunc(handle.ListPendingUpdates)
+	r.Get(transport.ListDeferredUpdates).HandlerFunc(handle.ListDeferredUpdates)
 
 	// These handlers persist to support requests from older fluxctls. In general we
 	// should avoid adding references to them so that they can eventually be removed.
@@ -231,6 +234,39 @@ func (s HTTPServer) GitRepoConfig(w http.ResponseWriter, r *http.Request) {
 	transport.JSONResponse(w, r, res)
 }
 
+func (s HTTPServer) PinRevision(w http.ResponseWriter, r *http.Request) {
+	var revision string
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(&revision); err != nil {
+		transport.WriteError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.server.PinRevision(r.Context(), revision); err != nil {
+		transport.ErrorResponse(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s HTTPServer) ListPendingUpdates(w http.ResponseWriter, r *http.Request) {
+	res, err := s.server.ListPendingUpdates(r.Context())
+	if err != nil {
+		transport.ErrorResponse(w, r, err)
+		return
+	}
+	transport.JSONResponse(w, r, res)
+}
+
+func (s HTTPServer) ListDeferredUpdates(w http.ResponseWriter, r *http.Request) {
+	res, err := s.server.ListDeferredUpdates(r.Context())
+	if err != nil {
+		transport.ErrorResponse(w, r, err)
+		return
+	}
+	transport.JSONResponse(w, r, res)
+}
+
 // --- handlers supporting deprecated requests
 
 func (s HTTPServer) UpdateImages(w http.ResponseWriter, r *http.Request) {
@@ -15,6 +15,9 @@ const (
 	SyncStatus              = "SyncStatus"
 	Export                  = "Export"
 	GitRepoConfig           = "GitRepoConfig"
+	PinRevision             = "PinRevision"
+	ListPendingUpdates      = "ListPendingUpdates"
+	ListDeferredUpdates     = "ListDeferredUpdates"
 
 	UpdateImages           = "UpdateImages"
 	UpdatePolicies         = "UpdatePolicies"
@@ -43,6 +43,9 @@ func NewAPIRouter() *mux.Router {
 	r.NewRoute().Name(SyncStatus).Methods("GET").Path("/v6/sync").Queries("ref", "{ref}")
 	r.NewRoute().Name(Export).Methods("HEAD", "GET").Path("/v6/export")
 	r.NewRoute().Name(GitRepoConfig).Methods("POST").Path("/v9/git-repo-config")
+	r.NewRoute().Name(PinRevision).Methods("POST").Path("/v12/pin")
+	r.NewRoute().Name(ListPendingUpdates).Methods("GET").Path("/v13/pending")
+	r.NewRoute().Name(ListDeferredUpdates).Methods("GET").Path("/v14/deferred")
 
 	// These routes persist to support requests from older fluxctls. In general we
 	// should avoid adding references to them so that they can eventually be removed.
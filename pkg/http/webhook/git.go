@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/fluxcd/flux/pkg/api/v9"
+	"github.com/fluxcd/flux/pkg/git"
+	"github.com/fluxcd/flux/pkg/policy"
+)
+
+// Git hosting webhook sources (and GitLab, which is both a registry
+// and a git host)
+const (
+	GitHub    = "github"
+	Bitbucket = "bitbucket"
+	Gitea     = "gitea"
+)
+
+var gitSources = map[string]source{
+	GitHub:    {parse: parseGitHub},
+	GitLab:    {parse: parseGitLabPush, tokenHeader: "X-Gitlab-Token"},
+	Bitbucket: {parse: parseBitbucket, signatureHeader: "X-Hub-Signature", signaturePrefix: "sha256="},
+	Gitea:     {parse: parseGitea, signatureHeader: "X-Gitea-Signature"},
+}
+
+// NewGitHandler returns a handler for the git hosting webhook
+// endpoints given. Pushes to the branch of the remote given, and, if
+// there is a ref policy, pushes of tags it matches, are passed on to
+// the notifier; any other push is rejected. The notifier is expected
+// to sync once it has fetched the push, rather than being asked to
+// sync straight away, which would sync whatever it had fetched before.
+func NewGitHandler(endpoints []Endpoint, remote git.Remote, branch string, refPolicy policy.Pattern, notifier Notifier, logger log.Logger) (*Handler, error) {
+	h, err := newHandler("git", gitSources, endpoints, logger)
+	if err != nil {
+		return nil, err
+	}
+	h.match = func(change v9.Change) error {
+		update := change.Source.(v9.GitUpdate)
+		if !remote.Equivalent(update.URL) {
+			return fmt.Errorf("push to %s is not to the configured git repo", update.URL)
+		}
+		if update.Tag != "" {
+			if refPolicy == nil || !refPolicy.Matches(update.Tag) {
+				return fmt.Errorf("push of tag %q is not matched by the ref policy", update.Tag)
+			}
+			return nil
+		}
+		if update.Branch != branch {
+			return fmt.Errorf("push to branch %q is not to the configured branch %q", update.Branch, branch)
+		}
+		return nil
+	}
+	h.notify = notifier.NotifyChange
+	return h, nil
+}
+
+// gitChanges returns a change for each of the refs given that is a
+// branch or a tag; other refs are ignored.
+func gitChanges(url string, refs ...string) []v9.Change {
+	var changes []v9.Change
+	for _, ref := range refs {
+		update := v9.GitUpdate{URL: url}
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			update.Branch = strings.TrimPrefix(ref, "refs/heads/")
+		case strings.HasPrefix(ref, "refs/tags/"):
+			update.Tag = strings.TrimPrefix(ref, "refs/tags/")
+		default:
+			continue
+		}
+		changes = append(changes, v9.Change{
+			Kind:   v9.GitChange,
+			Source: update,
+		})
+	}
+	return changes
+}
+
+// GitHub and Gitea send much the same payload for a push.
+type pushPayload struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+	} `json:"repository"`
+}
+
+func parsePush(body []byte) ([]v9.Change, error) {
+	var payload pushPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return gitChanges(payload.Repository.CloneURL, payload.Ref), nil
+}
+
+// See https://docs.github.com/en/webhooks/webhook-events-and-payloads#push
+func parseGitHub(header http.Header, body []byte) ([]v9.Change, error) {
+	// Other events, e.g., the `ping` sent when the webhook is
+	// created, have nothing to sync.
+	if header.Get("X-GitHub-Event") != "push" {
+		return nil, nil
+	}
+	return parsePush(body)
+}
+
+// See https://docs.gitea.com/usage/webhooks
+func parseGitea(header http.Header, body []byte) ([]v9.Change, error) {
+	if header.Get("X-Gitea-Event") != "push" {
+		return nil, nil
+	}
+	return parsePush(body)
+}
+
+// See https://docs.gitlab.com/ee/user/project/integrations/webhook_events.html#push-events
+func parseGitLabPush(header http.Header, body []byte) ([]v9.Change, error) {
+	switch header.Get("X-Gitlab-Event") {
+	case "Push Hook", "Tag Push Hook":
+	default:
+		return nil, nil
+	}
+	var payload struct {
+		Ref     string `json:"ref"`
+		Project struct {
+			GitHTTPURL string `json:"git_http_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	return gitChanges(payload.Project.GitHTTPURL, payload.Ref), nil
+}
+
+// Bitbucket Cloud and Bitbucket Server send different payloads, and
+// different event keys.
+//
+// See https://support.atlassian.com/bitbucket-cloud/docs/event-payloads/#Push
+// and https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html#Eventpayload-Push
+func parseBitbucket(header http.Header, body []byte) ([]v9.Change, error) {
+	switch header.Get("X-Event-Key") {
+	case "repo:push":
+		var payload struct {
+			Push struct {
+				Changes []struct {
+					New *struct {
+						Type string `json:"type"`
+						Name string `json:"name"`
+					} `json:"new"`
+				} `json:"changes"`
+			} `json:"push"`
+			Repository struct {
+				Links struct {
+					HTML struct {
+						Href string `json:"href"`
+					} `json:"html"`
+				} `json:"links"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		var refs []string
+		for _, c := range payload.Push.Changes {
+			// A deleted branch or tag has no `new`
+			switch {
+			case c.New == nil:
+			case c.New.Type == "branch":
+				refs = append(refs, "refs/heads/"+c.New.Name)
+			case c.New.Type == "tag":
+				refs = append(refs, "refs/tags/"+c.New.Name)
+			}
+		}
+		return gitChanges(payload.Repository.Links.HTML.Href, refs...), nil
+	case "repo:refs_changed":
+		var payload struct {
+			Changes []struct {
+				RefID string `json:"refId"`
+				Type  string `json:"type"`
+			} `json:"changes"`
+			Repository struct {
+				Links struct {
+					Clone []struct {
+						Href string `json:"href"`
+					} `json:"clone"`
+				} `json:"links"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		if len(payload.Repository.Links.Clone) == 0 {
+			return nil, fmt.Errorf("no clone URL in payload")
+		}
+		var refs []string
+		for _, c := range payload.Changes {
+			if c.Type != "DELETE" {
+				refs = append(refs, c.RefID)
+			}
+		}
+		return gitChanges(payload.Repository.Links.Clone[0].Href, refs...), nil
+	}
+	return nil, nil
+}
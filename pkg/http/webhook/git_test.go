@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/api/v9"
+	"github.com/fluxcd/flux/pkg/git"
+	"github.com/fluxcd/flux/pkg/policy"
+)
+
+func TestGitPayloads(t *testing.T) {
+	for _, c := range []struct {
+		source   string
+		header   string
+		event    string
+		payload  string
+		expected []v9.GitUpdate
+	}{
+		{GitHub, "X-GitHub-Event", "push", `{"ref":"refs/heads/master","repository":{"clone_url":"https://github.com/fluxcd/flux.git"}}`,
+			[]v9.GitUpdate{{URL: "https://github.com/fluxcd/flux.git", Branch: "master"}}},
+		{GitHub, "X-GitHub-Event", "push", `{"ref":"refs/tags/v1.0.0","repository":{"clone_url":"https://github.com/fluxcd/flux.git"}}`,
+			[]v9.GitUpdate{{URL: "https://github.com/fluxcd/flux.git", Tag: "v1.0.0"}}},
+		{GitHub, "X-GitHub-Event", "ping", `{"zen":"Keep it logically awesome."}`,
+			nil},
+		{Gitea, "X-Gitea-Event", "push", `{"ref":"refs/heads/main","repository":{"clone_url":"https://gitea.example.com/ops/cluster.git"}}`,
+			[]v9.GitUpdate{{URL: "https://gitea.example.com/ops/cluster.git", Branch: "main"}}},
+		{GitLab, "X-Gitlab-Event", "Push Hook", `{"ref":"refs/heads/master","project":{"git_http_url":"https://gitlab.com/ops/cluster.git"}}`,
+			[]v9.GitUpdate{{URL: "https://gitlab.com/ops/cluster.git", Branch: "master"}}},
+		{GitLab, "X-Gitlab-Event", "Tag Push Hook", `{"ref":"refs/tags/v2.1.0","project":{"git_http_url":"https://gitlab.com/ops/cluster.git"}}`,
+			[]v9.GitUpdate{{URL: "https://gitlab.com/ops/cluster.git", Tag: "v2.1.0"}}},
+		{Bitbucket, "X-Event-Key", "repo:push", `{"push":{"changes":[{"new":{"type":"branch","name":"master"}},{"new":null},{"new":{"type":"tag","name":"v1"}}]},"repository":{"links":{"html":{"href":"https://bitbucket.org/ops/cluster"}}}}`,
+			[]v9.GitUpdate{{URL: "https://bitbucket.org/ops/cluster", Branch: "master"}, {URL: "https://bitbucket.org/ops/cluster", Tag: "v1"}}},
+		{Bitbucket, "X-Event-Key", "repo:refs_changed", `{"changes":[{"refId":"refs/heads/master","type":"UPDATE"},{"refId":"refs/heads/old","type":"DELETE"}],"repository":{"links":{"clone":[{"href":"ssh://git@bitbucket.example.com:7999/ops/cluster.git","name":"ssh"}]}}}`,
+			[]v9.GitUpdate{{URL: "ssh://git@bitbucket.example.com:7999/ops/cluster.git", Branch: "master"}}},
+	} {
+		header := http.Header{}
+		header.Set(c.header, c.event)
+		changes, err := gitSources[c.source].parse(header, []byte(c.payload))
+		if !assert.NoError(t, err, c.source) {
+			continue
+		}
+		var updates []v9.GitUpdate
+		for _, change := range changes {
+			assert.Equal(t, v9.GitChange, change.Kind)
+			updates = append(updates, change.Source.(v9.GitUpdate))
+		}
+		assert.Equal(t, c.expected, updates, c.source+" "+c.event)
+	}
+}
+
+func TestGitHandler(t *testing.T) {
+	secret := []byte("s3cr3t")
+	notifier := &recorder{}
+	h, err := NewGitHandler([]Endpoint{
+		{Source: GitHub, Verification: VerifyHMAC, Secret: secret},
+		{Source: Gitea, Verification: VerifyHMAC, Secret: secret},
+		{Source: GitLab, Verification: VerifyToken, Secret: secret},
+		{Source: Bitbucket, Verification: VerifyHMAC, Secret: secret},
+	}, git.Remote{URL: "git@github.com:fluxcd/flux-get-started"}, "master", nil, notifier, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(payload string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(payload))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	push := `{"ref":"refs/heads/master","repository":{"clone_url":"https://github.com/fluxcd/flux-get-started.git"}}`
+	otherBranch := `{"ref":"refs/heads/dev","repository":{"clone_url":"https://github.com/fluxcd/flux-get-started.git"}}`
+	tag := `{"ref":"refs/tags/v1.0.0","repository":{"clone_url":"https://github.com/fluxcd/flux-get-started.git"}}`
+	otherRepo := `{"ref":"refs/heads/master","repository":{"clone_url":"https://github.com/fluxcd/flux.git"}}`
+	gitlab := `{"ref":"refs/heads/master","project":{"git_http_url":"https://github.com/fluxcd/flux-get-started.git"}}`
+	bitbucket := `{"push":{"changes":[{"new":{"type":"branch","name":"dev"}},{"new":{"type":"branch","name":"master"}}]},"repository":{"links":{"html":{"href":"https://github.com/fluxcd/flux-get-started"}}}}`
+
+	for _, c := range []struct {
+		name     string
+		path     string
+		body     string
+		header   map[string]string
+		status   int
+		notified int
+	}{
+		{"signed push", "/github", push, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(push)}, http.StatusAccepted, 1},
+		{"unsigned push", "/github", push, map[string]string{"X-GitHub-Event": "push"}, http.StatusUnauthorized, 0},
+		{"badly signed push", "/github", push, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(otherRepo)}, http.StatusUnauthorized, 0},
+		{"other branch", "/github", otherBranch, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(otherBranch)}, http.StatusUnprocessableEntity, 0},
+		{"tag without ref policy", "/github", tag, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(tag)}, http.StatusUnprocessableEntity, 0},
+		{"other repo", "/github", otherRepo, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(otherRepo)}, http.StatusUnprocessableEntity, 0},
+		{"ping", "/github", `{}`, map[string]string{"X-GitHub-Event": "ping", "X-Hub-Signature-256": "sha256=" + sign(`{}`)}, http.StatusAccepted, 0},
+		{"gitea signature", "/gitea", push, map[string]string{"X-Gitea-Event": "push", "X-Gitea-Signature": sign(push)}, http.StatusAccepted, 1},
+		{"gitlab token", "/gitlab", gitlab, map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "s3cr3t"}, http.StatusAccepted, 1},
+		{"push to several branches", "/bitbucket", bitbucket, map[string]string{"X-Event-Key": "repo:push", "X-Hub-Signature": "sha256=" + sign(bitbucket)}, http.StatusAccepted, 1},
+	} {
+		notifier.changes = nil
+		req := httptest.NewRequest("POST", c.path, bytes.NewBufferString(c.body))
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, c.status, rec.Code, c.name)
+		assert.Len(t, notifier.changes, c.notified, c.name)
+	}
+}
+
+func TestGitHandler_RefPolicy(t *testing.T) {
+	notifier := &recorder{}
+	h, err := NewGitHandler([]Endpoint{
+		{Source: GitLab, Verification: VerifyToken, Secret: []byte("s3cr3t")},
+	}, git.Remote{URL: "git@github.com:fluxcd/flux-get-started"}, "master", policy.NewPattern("semver:^1.x"), notifier, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		ref      string
+		status   int
+		notified int
+	}{
+		{"refs/tags/v1.2.0", http.StatusAccepted, 1},
+		{"refs/tags/v2.0.0", http.StatusUnprocessableEntity, 0},
+		{"refs/heads/master", http.StatusAccepted, 1},
+	} {
+		notifier.changes = nil
+		body := `{"ref":"` + c.ref + `","project":{"git_http_url":"https://github.com/fluxcd/flux-get-started.git"}}`
+		req := httptest.NewRequest("POST", "/gitlab", bytes.NewBufferString(body))
+		req.Header.Set("X-Gitlab-Event", "Tag Push Hook")
+		req.Header.Set("X-Gitlab-Token", "s3cr3t")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, c.status, rec.Code, c.ref)
+		assert.Len(t, notifier.changes, c.notified, c.ref)
+	}
+}
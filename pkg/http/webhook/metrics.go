@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	LabelKind    = "kind"
+	LabelSource  = "source"
+	LabelOutcome = "outcome"
+
+	OutcomeAccepted     = "accepted"
+	OutcomeUnauthorized = "unauthorized"
+	OutcomeInvalid      = "invalid"
+	OutcomeUnmatched    = "unmatched"
+	OutcomeError        = "error"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "flux",
+		Subsystem: "webhook",
+		Name:      "requests_total",
+		Help:      "Number of webhook requests received from each source, by whether they were accepted or why not.",
+	}, []string{LabelKind, LabelSource, LabelOutcome})
+)
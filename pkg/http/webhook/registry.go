@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+
+	"github.com/fluxcd/flux/pkg/api/v9"
+	"github.com/fluxcd/flux/pkg/image"
+)
+
+// Image registry webhook sources
+const (
+	DockerHub    = "dockerhub"
+	Quay         = "quay"
+	Harbor       = "harbor"
+	GitLab       = "gitlab"
+	Distribution = "distribution"
+)
+
+var registrySources = map[string]source{
+	DockerHub:    {parse: parseDockerHub},
+	Quay:         {parse: parseQuay},
+	Harbor:       {parse: parseHarbor},
+	GitLab:       {parse: parseDistribution, tokenHeader: "X-Gitlab-Token"},
+	Distribution: {parse: parseDistribution},
+}
+
+// NewRegistryHandler returns a handler for the image registry
+// webhook endpoints given, which tells the notifier about each image
+// repository that has been pushed to.
+func NewRegistryHandler(endpoints []Endpoint, notifier Notifier, logger log.Logger) (*Handler, error) {
+	h, err := newHandler("registry", registrySources, endpoints, logger)
+	if err != nil {
+		return nil, err
+	}
+	h.notify = notifier.NotifyChange
+	return h, nil
+}
+
+// imageChanges turns image references into a change per repository.
+func imageChanges(refs ...string) ([]v9.Change, error) {
+	var changes []v9.Change
+	seen := map[image.CanonicalName]bool{}
+	for _, s := range refs {
+		ref, err := image.ParseRef(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[ref.CanonicalName()] {
+			continue
+		}
+		seen[ref.CanonicalName()] = true
+		changes = append(changes, v9.Change{
+			Kind:   v9.ImageChange,
+			Source: v9.ImageUpdate{Name: ref.Name},
+		})
+	}
+	return changes, nil
+}
+
+// See https://docs.docker.com/docker-hub/webhooks/
+func parseDockerHub(_ http.Header, body []byte) ([]v9.Change, error) {
+	var payload struct {
+		Repository struct {
+			RepoName string `json:"repo_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Repository.RepoName == "" {
+		return nil, errors.New("no repository in payload")
+	}
+	return imageChanges(payload.Repository.RepoName)
+}
+
+// See https://docs.quay.io/guides/notifications.html
+func parseQuay(_ http.Header, body []byte) ([]v9.Change, error) {
+	var payload struct {
+		DockerURL string `json:"docker_url"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if payload.DockerURL == "" {
+		return nil, errors.New("no docker_url in payload")
+	}
+	return imageChanges(payload.DockerURL)
+}
+
+// See https://goharbor.io/docs/latest/working-with-projects/project-configuration/configure-webhooks/
+func parseHarbor(_ http.Header, body []byte) ([]v9.Change, error) {
+	var payload struct {
+		Type      string `json:"type"`
+		EventData struct {
+			Resources []struct {
+				ResourceURL string `json:"resource_url"`
+			} `json:"resources"`
+		} `json:"event_data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	switch payload.Type {
+	case "PUSH_ARTIFACT", "pushImage":
+	default:
+		return nil, nil
+	}
+	var refs []string
+	for _, r := range payload.EventData.Resources {
+		refs = append(refs, r.ResourceURL)
+	}
+	return imageChanges(refs...)
+}
+
+// See https://docs.docker.com/registry/notifications/; this is also
+// what the GitLab container registry sends.
+func parseDistribution(_ http.Header, body []byte) ([]v9.Change, error) {
+	var payload struct {
+		Events []struct {
+			Action string `json:"action"`
+			Target struct {
+				MediaType  string `json:"mediaType"`
+				Repository string `json:"repository"`
+			} `json:"target"`
+			Request struct {
+				Host string `json:"host"`
+			} `json:"request"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, e := range payload.Events {
+		// Layers are pushed too; only the manifest being pushed
+		// means there's a new image.
+		if e.Action != "push" || !strings.Contains(e.Target.MediaType, "manifest") {
+			continue
+		}
+		// Without the host, the image can't be named.
+		if e.Request.Host == "" {
+			continue
+		}
+		refs = append(refs, e.Request.Host+"/"+e.Target.Repository)
+	}
+	return imageChanges(refs...)
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/api/v9"
+)
+
+type recorder struct {
+	changes []v9.Change
+}
+
+func (r *recorder) NotifyChange(_ context.Context, c v9.Change) error {
+	r.changes = append(r.changes, c)
+	return nil
+}
+
+func TestRegistryPayloads(t *testing.T) {
+	for _, c := range []struct {
+		source   string
+		payload  string
+		expected []string
+	}{
+		{DockerHub, `{"push_data":{"tag":"latest"},"repository":{"repo_name":"fluxcd/flux"}}`,
+			[]string{"index.docker.io/fluxcd/flux"}},
+		{DockerHub, `{"push_data":{"tag":"latest"},"repository":{"repo_name":"alpine"}}`,
+			[]string{"index.docker.io/library/alpine"}},
+		{Quay, `{"repository":"fluxcd/flux","docker_url":"quay.io/fluxcd/flux","updated_tags":["1.2.3"]}`,
+			[]string{"quay.io/fluxcd/flux"}},
+		{Harbor, `{"type":"PUSH_ARTIFACT","event_data":{"resources":[{"tag":"v1","resource_url":"harbor.example.com/library/app:v1"},{"tag":"v2","resource_url":"harbor.example.com/library/app:v2"}]}}`,
+			[]string{"harbor.example.com/library/app"}},
+		{Harbor, `{"type":"DELETE_ARTIFACT","event_data":{"resources":[{"resource_url":"harbor.example.com/library/app:v1"}]}}`,
+			nil},
+		{Distribution, `{"events":[
+  {"action":"push","target":{"mediaType":"application/octet-stream","repository":"team/app"},"request":{"host":"registry.example.com:5000"}},
+  {"action":"push","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","repository":"team/app","tag":"v1"},"request":{"host":"registry.example.com:5000"}},
+  {"action":"pull","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","repository":"team/other"},"request":{"host":"registry.example.com:5000"}}
+]}`,
+			[]string{"registry.example.com:5000/team/app"}},
+		{Distribution, `{"events":[{"action":"push","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","repository":"team/app"},"request":{}}]}`,
+			nil},
+		{GitLab, `{"events":[{"action":"push","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","repository":"group/project"},"request":{"host":"registry.gitlab.com"}}]}`,
+			[]string{"registry.gitlab.com/group/project"}},
+	} {
+		changes, err := registrySources[c.source].parse(http.Header{}, []byte(c.payload))
+		if !assert.NoError(t, err, c.source) {
+			continue
+		}
+		var names []string
+		for _, change := range changes {
+			assert.Equal(t, v9.ImageChange, change.Kind)
+			names = append(names, change.Source.(v9.ImageUpdate).Name.CanonicalName().String())
+		}
+		assert.Equal(t, c.expected, names, c.source)
+	}
+
+	_, err := registrySources[DockerHub].parse(http.Header{}, []byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestRegistryHandler_Verification(t *testing.T) {
+	secret := []byte("s3cr3t")
+	notifier := &recorder{}
+	h, err := NewRegistryHandler([]Endpoint{
+		{Source: DockerHub, Verification: VerifyToken, Secret: secret},
+		{Source: GitLab, Verification: VerifyToken, Secret: secret},
+		{Source: Quay, Verification: VerifyHMAC, Secret: secret},
+	}, notifier, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dockerhub := `{"repository":{"repo_name":"fluxcd/flux"}}`
+	quay := `{"docker_url":"quay.io/fluxcd/flux"}`
+	gitlab := `{"events":[{"action":"push","target":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","repository":"group/project"},"request":{"host":"registry.gitlab.com"}}]}`
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(quay))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, c := range []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		header  map[string]string
+		status  int
+		changes int
+	}{
+		{"token in query", "POST", "/dockerhub?token=s3cr3t", dockerhub, nil, http.StatusAccepted, 1},
+		{"bearer token", "POST", "/dockerhub", dockerhub, map[string]string{"Authorization": "Bearer s3cr3t"}, http.StatusAccepted, 1},
+		{"wrong token", "POST", "/dockerhub?token=guess", dockerhub, nil, http.StatusUnauthorized, 0},
+		{"no token", "POST", "/dockerhub", dockerhub, nil, http.StatusUnauthorized, 0},
+		{"source token header", "POST", "/gitlab", gitlab, map[string]string{"X-Gitlab-Token": "s3cr3t"}, http.StatusAccepted, 1},
+		{"signature", "POST", "/quay", quay, map[string]string{"X-Hub-Signature-256": signature}, http.StatusAccepted, 1},
+		{"token for signed source", "POST", "/quay?token=s3cr3t", quay, nil, http.StatusUnauthorized, 0},
+		{"signature of other payload", "POST", "/quay", `{"docker_url":"quay.io/other/image"}`, map[string]string{"X-Hub-Signature-256": signature}, http.StatusUnauthorized, 0},
+		{"unconfigured source", "POST", "/harbor?token=s3cr3t", `{}`, nil, http.StatusNotFound, 0},
+		{"not POST", "GET", "/dockerhub?token=s3cr3t", "", nil, http.StatusMethodNotAllowed, 0},
+		{"bad payload", "POST", "/dockerhub?token=s3cr3t", `not json`, nil, http.StatusBadRequest, 0},
+	} {
+		notifier.changes = nil
+		req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, c.status, rec.Code, c.name)
+		assert.Len(t, notifier.changes, c.changes, c.name)
+	}
+
+	_, err = NewRegistryHandler([]Endpoint{{Source: "github", Verification: VerifyHMAC, Secret: secret}}, notifier, log.NewNopLogger())
+	assert.Error(t, err)
+}
@@ -0,0 +1,246 @@
+// Package webhook serves endpoints that accept notifications (e.g.,
+// of an image having been pushed) from third-party services, and
+// passes them on to the daemon as changes.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+
+	"github.com/fluxcd/flux/pkg/api/v9"
+)
+
+// maxPayloadSize is the most we'll read of a webhook payload; the
+// services we accept them from send far less than this.
+const maxPayloadSize = 1 << 20
+
+// Verification is how the sender of a webhook is authenticated.
+type Verification string
+
+const (
+	// VerifyToken checks for a shared secret, given in a header
+	// (`Authorization`, or one particular to the source) or, for
+	// services that cannot set headers, the `token` query parameter.
+	VerifyToken Verification = "token"
+	// VerifyHMAC checks for an HMAC-SHA256 signature of the payload,
+	// made with the shared secret.
+	VerifyHMAC Verification = "hmac"
+)
+
+// Endpoint is the configuration of the webhook for one source.
+type Endpoint struct {
+	Source       string
+	Verification Verification
+	Secret       []byte
+}
+
+// ParseEndpoint parses the configuration of an endpoint, given as
+// `<source>:<verification>:<path to file with secret>`, reading the
+// secret from the file.
+func ParseEndpoint(s string) (Endpoint, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return Endpoint{}, fmt.Errorf("expected <source>:<verification>:<secret file>, got %q", s)
+	}
+	e := Endpoint{Source: parts[0], Verification: Verification(parts[1])}
+	switch e.Verification {
+	case VerifyToken, VerifyHMAC:
+	default:
+		return Endpoint{}, fmt.Errorf("unknown verification %q for webhook %s (expected %q or %q)", parts[1], e.Source, VerifyToken, VerifyHMAC)
+	}
+	secret, err := ioutil.ReadFile(parts[2])
+	if err != nil {
+		return Endpoint{}, errors.Wrapf(err, "reading secret for webhook %s", e.Source)
+	}
+	e.Secret = []byte(strings.TrimSpace(string(secret)))
+	if len(e.Secret) == 0 {
+		return Endpoint{}, fmt.Errorf("secret for webhook %s is empty", e.Source)
+	}
+	return e, nil
+}
+
+// Notifier is told about the changes reported by webhooks; the daemon
+// is one.
+type Notifier interface {
+	NotifyChange(context.Context, v9.Change) error
+}
+
+// source describes a kind of webhook sender: how to interpret its
+// payloads, and where it puts tokens or signatures, if it has its own
+// way of doing that.
+type source struct {
+	parse           func(header http.Header, body []byte) ([]v9.Change, error)
+	tokenHeader     string
+	signatureHeader string
+	signaturePrefix string
+}
+
+// Handler serves a webhook endpoint for each configured source, at
+// the path `/<source>` (relative to wherever it is mounted).
+type Handler struct {
+	kind      string
+	sources   map[string]source
+	endpoints map[string]Endpoint
+	// match returns an error if a change is not one we want to hear
+	// about; e.g., a push to some other git repo.
+	match  func(v9.Change) error
+	notify func(context.Context, v9.Change) error
+	logger log.Logger
+}
+
+func newHandler(kind string, sources map[string]source, endpoints []Endpoint, logger log.Logger) (*Handler, error) {
+	h := &Handler{
+		kind:      kind,
+		sources:   sources,
+		endpoints: map[string]Endpoint{},
+		logger:    logger,
+	}
+	for _, e := range endpoints {
+		if _, ok := sources[e.Source]; !ok {
+			return nil, fmt.Errorf("unknown %s webhook source %q (expected one of %s)", kind, e.Source, strings.Join(sourceNames(sources), ", "))
+		}
+		h.endpoints[e.Source] = e
+	}
+	return h, nil
+}
+
+func sourceNames(sources map[string]source) []string {
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(r.URL.Path, "/")
+	endpoint, ok := h.endpoints[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "webhooks must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	logger := log.With(h.logger, "source", name)
+	count := func(outcome string) {
+		requestsTotal.With(LabelKind, h.kind, LabelSource, name, LabelOutcome, outcome).Add(1)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		count(OutcomeInvalid)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	src := h.sources[name]
+	if err := verify(endpoint, src, r, body); err != nil {
+		count(OutcomeUnauthorized)
+		logger.Log("err", "rejected webhook", "reason", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	changes, err := src.parse(r.Header, body)
+	if err != nil {
+		count(OutcomeInvalid)
+		logger.Log("err", "could not parse webhook payload", "reason", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// A payload may report several changes (e.g., a push to more
+	// than one branch); those that aren't wanted are dropped, and the
+	// webhook is rejected only if none are.
+	if h.match != nil && len(changes) > 0 {
+		var matched []v9.Change
+		var unmatched error
+		for _, change := range changes {
+			if err := h.match(change); err != nil {
+				unmatched = err
+				continue
+			}
+			matched = append(matched, change)
+		}
+		if len(matched) == 0 {
+			count(OutcomeUnmatched)
+			logger.Log("err", "rejected webhook", "reason", unmatched)
+			http.Error(w, unmatched.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		changes = matched
+	}
+	for _, change := range changes {
+		if err := h.notify(r.Context(), change); err != nil {
+			count(OutcomeError)
+			logger.Log("err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	count(OutcomeAccepted)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verify checks that the request carries the shared secret, or a
+// signature of the payload made with it.
+func verify(e Endpoint, src source, r *http.Request, body []byte) error {
+	switch e.Verification {
+	case VerifyToken:
+		token := bearer(r.Header.Get("Authorization"))
+		if token == "" && src.tokenHeader != "" {
+			token = r.Header.Get(src.tokenHeader)
+		}
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+		if token == "" {
+			return errors.New("no token given")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), e.Secret) != 1 {
+			return errors.New("token does not match")
+		}
+		return nil
+	case VerifyHMAC:
+		header, prefix := "X-Hub-Signature-256", "sha256="
+		if src.signatureHeader != "" {
+			header, prefix = src.signatureHeader, src.signaturePrefix
+		}
+		given := r.Header.Get(header)
+		if given == "" {
+			return fmt.Errorf("no signature given in %s header", header)
+		}
+		sig, err := hex.DecodeString(strings.TrimPrefix(given, prefix))
+		if err != nil {
+			return errors.Wrap(err, "decoding signature")
+		}
+		mac := hmac.New(sha256.New, e.Secret)
+		mac.Write(body)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return errors.New("signature does not match")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown verification %q", e.Verification)
+}
+
+// bearer returns the credentials from an Authorization header,
+// without any scheme (e.g., `Bearer`) in front of it.
+func bearer(auth string) string {
+	if i := strings.IndexByte(auth, ' '); i >= 0 {
+		return strings.TrimSpace(auth[i+1:])
+	}
+	return auth
+}
@@ -22,6 +22,7 @@ var (
 	ErrInvalidImageID   = errors.New("invalid image ID")
 	ErrBlankImageID     = errors.Wrap(ErrInvalidImageID, "blank image name")
 	ErrMalformedImageID = errors.Wrap(ErrInvalidImageID, `expected image name as either <image>:<tag> or just <image>`)
+	ErrMalformedDigest  = errors.Wrap(ErrInvalidImageID, `expected digest as <algorithm>:<hex>`)
 )
 
 // Name represents an unversioned (i.e., untagged) image a.k.a.,
@@ -102,16 +103,21 @@ func (i Name) ToRef(tag string) Ref {
 
 // Ref represents a versioned (i.e., tagged) image. The tag is
 // allowed to be empty, though it is in general undefined what that
-// means. As such, `Ref` also includes all `Name` values.
+// means. As such, `Ref` also includes all `Name` values. A ref may
+// also be pinned to the digest of a manifest, in which case the
+// digest is what's used to pull the image, and the tag is for
+// information.
 //
 // Examples (stringified):
 //  * alpine:3.5
 //  * library/alpine:3.5
 //  * docker.io/fluxcd/flux:1.1.0
 //  * localhost:5000/arbitrary/path/to/repo:revision-sha1
+//  * fluxcd/flux:1.1.0@sha256:2c3a...
 type Ref struct {
 	Name
-	Tag string
+	Tag    string
+	Digest string
 }
 
 // CanonicalRef is an image ref with none of the fields left to be
@@ -126,7 +132,21 @@ func (i Ref) String() string {
 	if i.Tag != "" {
 		tag = ":" + i.Tag
 	}
-	return fmt.Sprintf("%s%s", i.Name.String(), tag)
+	var digest string
+	if i.Digest != "" {
+		digest = "@" + i.Digest
+	}
+	return fmt.Sprintf("%s%s%s", i.Name.String(), tag, digest)
+}
+
+// TagAndDigest returns the tag, followed by `@<digest>` if the ref is
+// pinned to a digest; i.e., what comes after the `:` in the string
+// form. This is for when the tag is kept separately from the name.
+func (i Ref) TagAndDigest() string {
+	if i.Digest == "" {
+		return i.Tag
+	}
+	return i.Tag + "@" + i.Digest
 }
 
 // ParseRef parses a string representation of an image id into an
@@ -138,6 +158,15 @@ func ParseRef(s string) (Ref, error) {
 	if s == "" {
 		return id, errors.Wrapf(ErrBlankImageID, "parsing %q", s)
 	}
+	// A digest, if present, comes last, and may contain a colon, so
+	// take it off before looking for a tag
+	if at := strings.LastIndex(s, "@"); at >= 0 {
+		id.Digest = s[at+1:]
+		if !digestRegexp.MatchString(id.Digest) {
+			return Ref{}, errors.Wrapf(ErrMalformedDigest, "parsing %q", s)
+		}
+		s = s[:at]
+	}
 	if strings.HasPrefix(s, "/") || strings.HasSuffix(s, "/") {
 		return id, errors.Wrapf(ErrMalformedImageID, "parsing %q", s)
 	}
@@ -182,6 +211,7 @@ var (
 	domainComponent = `([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
 	domain          = fmt.Sprintf(`localhost|(%s([.]%s)+)(:[0-9]+)?`, domainComponent, domainComponent)
 	domainRegexp    = regexp.MustCompile(domain)
+	digestRegexp    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[[:xdigit:]]{32,}$`)
 )
 
 // ImageID is serialized/deserialized as a string
@@ -205,8 +235,9 @@ func (i Ref) CanonicalRef() CanonicalRef {
 	name := i.CanonicalName()
 	return CanonicalRef{
 		Ref: Ref{
-			Name: name.Name,
-			Tag:  i.Tag,
+			Name:   name.Name,
+			Tag:    i.Tag,
+			Digest: i.Digest,
 		},
 	}
 }
@@ -215,11 +246,21 @@ func (i Ref) Components() (domain, repo, tag string) {
 	return i.Domain, i.Image, i.Tag
 }
 
-// WithNewTag makes a new copy of an ImageID with a new tag
+// WithNewTag makes a new copy of an ImageID with a new tag. Since
+// the digest is for the old tag, it is not kept.
 func (i Ref) WithNewTag(t string) Ref {
 	var img Ref
 	img = i
 	img.Tag = t
+	img.Digest = ""
+	return img
+}
+
+// WithDigest makes a new copy of an ImageID pinned to the digest
+// given; or, if the digest is empty, not pinned.
+func (i Ref) WithDigest(d string) Ref {
+	img := i
+	img.Digest = d
 	return img
 }
 
@@ -243,6 +284,80 @@ type Labels struct {
 	// Created holds the Open Container Image spec 'created' label
 	// Ref: https://github.com/opencontainers/image-spec/blob/master/annotations.md#pre-defined-annotation-keys
 	Created time.Time `json:"org.opencontainers.image.created,omitempty"`
+	// Other holds any other labels, by name; e.g., a build number
+	// used to order images
+	Other map[string]string `json:"-"`
+}
+
+const (
+	labelBuildDate = "org.label-schema.build-date"
+	labelCreated   = "org.opencontainers.image.created"
+
+	// Other labels are kept for every tag, so only a few short ones
+	// are; labels used to order images hold e.g., build numbers,
+	// rather than descriptions or licences.
+	maxOtherLabels      = 16
+	maxLabelValueLength = 64
+)
+
+// Get returns the value of the label named, other than the timestamp
+// labels, and whether it is present.
+func (l Labels) Get(name string) (string, bool) {
+	v, ok := l.Other[name]
+	return v, ok
+}
+
+// WithDefaults returns the labels, with any that are missing taken
+// from the defaults given.
+func (l Labels) WithDefaults(defaults Labels) Labels {
+	if l.BuildDate.IsZero() {
+		l.BuildDate = defaults.BuildDate
+	}
+	if l.Created.IsZero() {
+		l.Created = defaults.Created
+	}
+	if len(defaults.Other) > 0 {
+		other := make(map[string]string, len(l.Other)+len(defaults.Other))
+		for name, v := range defaults.Other {
+			other[name] = v
+		}
+		for name, v := range l.Other {
+			other[name] = v
+		}
+		l.Other = other
+	}
+	return l
+}
+
+// Trimmed returns the labels with only the other labels short
+// enough to order images by, and at most maxOtherLabels of those
+// (taking the first by name). The labels named in `keep`, e.g.,
+// those used to order images, are kept whatever else is dropped.
+func (l Labels) Trimmed(keep ...string) Labels {
+	other := make(map[string]string, len(keep))
+	for _, name := range keep {
+		if v, ok := l.Other[name]; ok {
+			other[name] = v
+		}
+	}
+	var names []string
+	for name, v := range l.Other {
+		if _, ok := other[name]; !ok && len(v) <= maxLabelValueLength {
+			names = append(names, name)
+		}
+	}
+	if len(names)+len(other) == len(l.Other) && len(l.Other) <= maxOtherLabels {
+		return l
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(other) >= maxOtherLabels {
+			break
+		}
+		other[name] = l.Other[name]
+	}
+	l.Other = other
+	return l
 }
 
 // MarshalJSON returns the Labels value in JSON (as bytes). It is
@@ -250,40 +365,47 @@ type Labels struct {
 // zero, which would otherwise be tricky for e.g., JavaScript to
 // detect.
 func (l Labels) MarshalJSON() ([]byte, error) {
-	var bd, c string
+	encode := make(map[string]string, len(l.Other)+2)
+	for name, v := range l.Other {
+		encode[name] = v
+	}
 	if !l.BuildDate.IsZero() {
-		bd = l.BuildDate.UTC().Format(time.RFC3339Nano)
+		encode[labelBuildDate] = l.BuildDate.UTC().Format(time.RFC3339Nano)
 	}
 	if !l.Created.IsZero() {
-		c = l.Created.UTC().Format(time.RFC3339Nano)
+		encode[labelCreated] = l.Created.UTC().Format(time.RFC3339Nano)
 	}
-	encode := struct {
-		BuildDate string `json:"org.label-schema.build-date,omitempty"`
-		Created   string `json:"org.opencontainers.image.created,omitempty"`
-	}{BuildDate: bd, Created: c}
 	return json.Marshal(encode)
 }
 
 // UnmarshalJSON populates Labels from JSON (as bytes). It's the
 // companion to MarshalJSON above.
 func (l *Labels) UnmarshalJSON(b []byte) error {
-	unencode := struct {
-		BuildDate string `json:"org.label-schema.build-date,omitempty"`
-		Created   string `json:"org.opencontainers.image.created,omitempty"`
-	}{}
+	var unencode map[string]interface{}
 	json.Unmarshal(b, &unencode)
+	*l = Labels{}
+	for name, v := range unencode {
+		if s, ok := v.(string); ok && name != labelBuildDate && name != labelCreated {
+			if l.Other == nil {
+				l.Other = map[string]string{}
+			}
+			l.Other[name] = s
+		}
+	}
+	buildDate, _ := unencode[labelBuildDate].(string)
+	created, _ := unencode[labelCreated].(string)
 	labelErr := LabelTimestampFormatError{}
-	if err := decodeTime(unencode.BuildDate, &l.BuildDate); err != nil {
+	if err := decodeTime(buildDate, &l.BuildDate); err != nil {
 		if _, ok := err.(*time.ParseError); !ok {
 			return err
 		}
-		labelErr.Labels = append(labelErr.Labels, "org.label-schema.build-date")
+		labelErr.Labels = append(labelErr.Labels, labelBuildDate)
 	}
-	if err := decodeTime(unencode.Created, &l.Created); err != nil {
+	if err := decodeTime(created, &l.Created); err != nil {
 		if _, ok := err.(*time.ParseError); !ok {
 			return err
 		}
-		labelErr.Labels = append(labelErr.Labels, "org.opencontainers.image.created")
+		labelErr.Labels = append(labelErr.Labels, labelCreated)
 	}
 	if len(labelErr.Labels) >= 1 {
 		return &labelErr
@@ -297,18 +419,33 @@ type Info struct {
 	// the reference to this image; probably a tagged image name
 	ID Ref `json:",omitempty"`
 	// the digest we got when fetching the metadata, which will be
-	// different each time a manifest is uploaded for the reference
+	// different each time a manifest is uploaded for the reference;
+	// for a multi-platform image, this is the digest of the image
+	// index (or manifest list)
 	Digest string `json:",omitempty"`
+	// the platforms (e.g., "linux/arm64") for which the image is
+	// available, with the digest of the manifest for each
+	Platforms map[string]string `json:",omitempty"`
 	// an identifier for the *image* this reference points to; this
 	// will be the same for references that point at the same image
 	// (but does not necessarily equal Docker's image ID)
 	ImageID string `json:",omitempty"`
 	// all labels we are interested in and could find for the image ref
 	Labels Labels `json:",omitempty"`
-	// the time at which the image pointed at was created
+	// the same, but from the annotations of the image manifest (OCI
+	// image manifests have annotations, with the same keys as labels)
+	Annotations Labels `json:",omitempty"`
+	// the time at which the image pointed at was created; as fetched,
+	// this is the time given in the image config, but it may be
+	// replaced with a time from another source (see CreatedAtSource)
 	CreatedAt time.Time `json:",omitempty"`
+	// where CreatedAt came from, if that has been decided
+	CreatedAtSource TimestampSource `json:",omitempty"`
 	// the last time this image manifest was fetched
 	LastFetched time.Time `json:",omitempty"`
+	// the first time this image manifest was fetched (with its
+	// current digest)
+	FirstSeen time.Time `json:",omitempty"`
 }
 
 // MarshalJSON returns the Info value in JSON (as bytes). It is
@@ -317,18 +454,22 @@ type Info struct {
 // detect.
 func (im Info) MarshalJSON() ([]byte, error) {
 	type InfoAlias Info // alias to shed existing MarshalJSON implementation
-	var ca, lf string
+	var ca, lf, fs string
 	if !im.CreatedAt.IsZero() {
 		ca = im.CreatedAt.UTC().Format(time.RFC3339Nano)
 	}
 	if !im.LastFetched.IsZero() {
 		lf = im.LastFetched.UTC().Format(time.RFC3339Nano)
 	}
+	if !im.FirstSeen.IsZero() {
+		fs = im.FirstSeen.UTC().Format(time.RFC3339Nano)
+	}
 	encode := struct {
 		InfoAlias
 		CreatedAt   string `json:",omitempty"`
 		LastFetched string `json:",omitempty"`
-	}{InfoAlias(im), ca, lf}
+		FirstSeen   string `json:",omitempty"`
+	}{InfoAlias(im), ca, lf, fs}
 	return json.Marshal(encode)
 }
 
@@ -340,13 +481,16 @@ func (im *Info) UnmarshalJSON(b []byte) error {
 		InfoAlias
 		CreatedAt   string `json:",omitempty"`
 		LastFetched string `json:",omitempty"`
+		FirstSeen   string `json:",omitempty"`
 	}{}
 	json.Unmarshal(b, &unencode)
 	*im = Info(unencode.InfoAlias)
 
 	var err error
 	if err = decodeTime(unencode.CreatedAt, &im.CreatedAt); err == nil {
-		err = decodeTime(unencode.LastFetched, &im.LastFetched)
+		if err = decodeTime(unencode.LastFetched, &im.LastFetched); err == nil {
+			err = decodeTime(unencode.FirstSeen, &im.FirstSeen)
+		}
 	}
 	return err
 }
@@ -364,10 +508,12 @@ type RepositoryMetadata struct {
 }
 
 // FindImageWithRef returns image.Info given an image ref. If the image cannot be
-// found, it returns the image.Info with the ID provided.
+// found, it returns the image.Info with the ID provided. Any digest the
+// ref is pinned to is not considered, since the image found is what
+// the tag points to now.
 func (rm RepositoryMetadata) FindImageWithRef(ref Ref) Info {
 	for _, img := range rm.Images {
-		if img.ID == ref {
+		if img.ID == ref.WithDigest("") {
 			return img
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,6 +53,9 @@ func TestParseRef(t *testing.T) {
 		{"quay.io/library/alpine:latest", "quay.io", "library/alpine", "quay.io/library/alpine:latest"},
 		{"quay.io/library/alpine:mytag", "quay.io", "library/alpine", "quay.io/library/alpine:mytag"},
 		{"localhost:5000/path/to/repo/alpine:mytag", "localhost:5000", "path/to/repo/alpine", "localhost:5000/path/to/repo/alpine:mytag"},
+		// A ref can be pinned to a digest, with or without a tag
+		{"alpine:mytag@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35", dockerHubHost, "library/alpine", "index.docker.io/library/alpine:mytag@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35"},
+		{"localhost:5000/hello@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35", "localhost:5000", "hello", "localhost:5000/hello@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35"},
 	} {
 		i, err := ParseRef(x.test)
 		if err != nil {
@@ -80,6 +84,9 @@ func TestParseRefErrorCases(t *testing.T) {
 		{":tag"},
 		{"/leading/slash"},
 		{"trailing/slash/"},
+		{"alpine:mytag@"},
+		{"alpine:mytag@sha256:not-hex"},
+		{"alpine:mytag@sha256"},
 	} {
 		_, err := ParseRef(x.test)
 		if err == nil {
@@ -112,6 +119,22 @@ func TestComponents(t *testing.T) {
 	}
 }
 
+func TestRefWithNewTag(t *testing.T) {
+	ref, err := ParseRef("quay.io/my/repo:v1@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tagged := ref.WithNewTag("v2"); tagged.String() != "quay.io/my/repo:v2" {
+		t.Errorf("Expected digest to be dropped with new tag, got %q", tagged.String())
+	}
+	if ref.TagAndDigest() != "v1@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35" {
+		t.Errorf("Expected tag and digest, got %q", ref.TagAndDigest())
+	}
+	if ref.WithDigest("").String() != "quay.io/my/repo:v1" {
+		t.Errorf("Expected no digest, got %q", ref.WithDigest("").String())
+	}
+}
+
 func TestRefSerialization(t *testing.T) {
 	for _, x := range []struct {
 		test     Ref
@@ -119,6 +142,7 @@ func TestRefSerialization(t *testing.T) {
 	}{
 		{Ref{Name: Name{Image: "alpine"}, Tag: "a123"}, `"alpine:a123"`},
 		{Ref{Name: Name{Domain: "quay.io", Image: "weaveworks/foobar"}, Tag: "baz"}, `"quay.io/weaveworks/foobar:baz"`},
+		{Ref{Name: Name{Image: "alpine"}, Tag: "a123", Digest: "sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35"}, `"alpine:a123@sha256:2c3a5bf4b0b1fb3f18a4bd41c8bfd1d4d6f3b7bbf6a6f54fac3c6a04a7dbdf35"`},
 	} {
 		serialized, err := json.Marshal(x.test)
 		if err != nil {
@@ -205,6 +229,9 @@ func TestImageInfoSerialisation(t *testing.T) {
 	info.Digest = "sha256:digest"
 	info.ImageID = "sha256:layerID"
 	info.LastFetched = t1
+	info.FirstSeen = t0
+	info.Annotations = Labels{Created: t0}
+	info.CreatedAtSource = TimestampAnnotation
 	bytes, err := json.Marshal(info)
 	if err != nil {
 		t.Fatal(err)
@@ -301,3 +328,68 @@ func reverse(imgs []Info) {
 		imgs[i], imgs[opp] = imgs[opp], imgs[i]
 	}
 }
+
+func TestImageLabelsOther(t *testing.T) {
+	str := `{
+	"org.opencontainers.image.created": "2019-05-23T00:00:00Z",
+	"build.number": "1234"
+}`
+	var labels Labels
+	if err := json.Unmarshal([]byte(str), &labels); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, time.Date(2019, 5, 23, 0, 0, 0, 0, time.UTC), labels.Created)
+	v, ok := labels.Get("build.number")
+	assert.True(t, ok)
+	assert.Equal(t, "1234", v)
+	_, ok = labels.Get("org.opencontainers.image.created")
+	assert.False(t, ok)
+
+	bytes, err := json.Marshal(labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels1 Labels
+	if err = json.Unmarshal(bytes, &labels1); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, labels, labels1)
+
+	defaulted := Labels{Other: map[string]string{"build.number": "1"}}.WithDefaults(labels)
+	assert.Equal(t, labels.Created, defaulted.Created)
+	assert.Equal(t, map[string]string{"build.number": "1"}, defaulted.Other)
+}
+
+func TestImageLabelsTrimmed(t *testing.T) {
+	other := map[string]string{
+		"description": strings.Repeat("x", maxLabelValueLength+1),
+	}
+	for i := 0; i < maxOtherLabels+2; i++ {
+		other[fmt.Sprintf("label.%02d", i)] = fmt.Sprintf("%d", i)
+	}
+	created := time.Date(2019, 5, 23, 0, 0, 0, 0, time.UTC)
+	labels := Labels{Created: created, Other: other}.Trimmed()
+	assert.Equal(t, created, labels.Created)
+	assert.Len(t, labels.Other, maxOtherLabels)
+	_, ok := labels.Get("description")
+	assert.False(t, ok)
+	v, ok := labels.Get("label.00")
+	assert.True(t, ok)
+	assert.Equal(t, "0", v)
+	_, ok = labels.Get(fmt.Sprintf("label.%02d", maxOtherLabels))
+	assert.False(t, ok)
+
+	// short enough labels are left as they are
+	short := Labels{Other: map[string]string{"build.number": "1234"}}
+	assert.Equal(t, short, short.Trimmed())
+
+	// labels asked for are kept, even if they would sort last
+	other["zz.build.number"] = "1234"
+	labels = Labels{Other: other}.Trimmed("zz.build.number")
+	assert.Len(t, labels.Other, maxOtherLabels)
+	v, ok = labels.Get("zz.build.number")
+	assert.True(t, ok)
+	assert.Equal(t, "1234", v)
+	_, ok = labels.Get(fmt.Sprintf("label.%02d", maxOtherLabels-1))
+	assert.False(t, ok)
+}
@@ -0,0 +1,66 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform is the operating system and architecture (and possibly
+// the variant of the architecture, e.g., `v7` for ARM) that an image
+// is built for.
+type Platform struct {
+	OS, Architecture, Variant string
+}
+
+// DefaultPlatform is the platform assumed when none is given.
+var DefaultPlatform = Platform{OS: "linux", Architecture: "amd64"}
+
+// ParsePlatform parses a platform given as
+// `<os>/<architecture>[/<variant>]`, e.g., `linux/arm64` or
+// `linux/arm/v7`.
+func ParsePlatform(s string) (Platform, error) {
+	parts := strings.Split(s, "/")
+	for _, part := range parts {
+		if part == "" {
+			return Platform{}, fmt.Errorf("platform %q is not in the form <os>/<architecture>[/<variant>]", s)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return Platform{OS: parts[0], Architecture: parts[1]}, nil
+	case 3:
+		return Platform{OS: parts[0], Architecture: parts[1], Variant: parts[2]}, nil
+	default:
+		return Platform{}, fmt.Errorf("platform %q is not in the form <os>/<architecture>[/<variant>]", s)
+	}
+}
+
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return p.OS + "/" + p.Architecture
+	}
+	return p.OS + "/" + p.Architecture + "/" + p.Variant
+}
+
+// Matches says whether an image for the other platform will do for
+// this platform. If this platform has no variant, any variant will
+// do.
+func (p Platform) Matches(other Platform) bool {
+	return p.OS == other.OS && p.Architecture == other.Architecture &&
+		(p.Variant == "" || p.Variant == other.Variant)
+}
+
+// SupportsPlatform says whether the image is available for the
+// platform, and whether that is known (it isn't, if the image was
+// fetched before platforms were recorded).
+func (im Info) SupportsPlatform(p Platform) (supported, known bool) {
+	if len(im.Platforms) == 0 {
+		return false, false
+	}
+	for s := range im.Platforms {
+		if other, err := ParsePlatform(s); err == nil && p.Matches(other) {
+			return true, true
+		}
+	}
+	return false, true
+}
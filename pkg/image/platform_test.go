@@ -0,0 +1,64 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePlatform(t *testing.T) {
+	for _, c := range []struct {
+		in       string
+		platform Platform
+		err      bool
+	}{
+		{in: "linux/amd64", platform: Platform{OS: "linux", Architecture: "amd64"}},
+		{in: "linux/arm/v7", platform: Platform{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{in: "linux", err: true},
+		{in: "linux/", err: true},
+		{in: "linux/arm/v7/extra", err: true},
+	} {
+		p, err := ParsePlatform(c.in)
+		if c.err {
+			assert.Error(t, err, c.in)
+			continue
+		}
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.platform, p, c.in)
+		assert.Equal(t, c.in, p.String())
+	}
+}
+
+func TestInfo_SupportsPlatform(t *testing.T) {
+	info := mustMakeInfo("my/image:tag", testTime)
+	_, known := info.SupportsPlatform(DefaultPlatform)
+	assert.False(t, known)
+
+	info.Platforms = map[string]string{
+		"linux/amd64":   "sha256:amd64",
+		"linux/arm/v7":  "sha256:armv7",
+		"windows/amd64": "sha256:windows",
+	}
+	for _, c := range []struct {
+		platform  string
+		supported bool
+	}{
+		{"linux/amd64", true},
+		{"linux/arm", true},
+		{"linux/arm/v7", true},
+		{"linux/arm/v6", false},
+		{"linux/arm64", false},
+	} {
+		supported, known := info.SupportsPlatform(mustParsePlatform(c.platform))
+		assert.True(t, known)
+		assert.Equal(t, c.supported, supported, c.platform)
+	}
+}
+
+func mustParsePlatform(s string) Platform {
+	p, err := ParsePlatform(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,99 @@
+package image
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimestampSource says where the creation time of an image came
+// from.
+type TimestampSource string
+
+const (
+	// TimestampAnnotation is the `org.opencontainers.image.created`
+	// (or `org.label-schema.build-date`) annotation of the image
+	// manifest, as used by OCI artifacts.
+	TimestampAnnotation TimestampSource = "annotation"
+	// TimestampLabel is the same, but from the image config labels.
+	TimestampLabel TimestampSource = "label"
+	// TimestampConfig is the created time in the image config.
+	TimestampConfig TimestampSource = "config"
+	// TimestampFirstSeen is when the image was first seen by Flux;
+	// it's always available, but depends on when Flux happened to
+	// look.
+	TimestampFirstSeen TimestampSource = "first-seen"
+)
+
+// TimestampSources are all the sources, in the order used if you
+// don't say otherwise.
+var TimestampSources = []TimestampSource{TimestampAnnotation, TimestampLabel, TimestampConfig, TimestampFirstSeen}
+
+// ParseTimestampSource parses the name of a timestamp source.
+func ParseTimestampSource(s string) (TimestampSource, error) {
+	for _, source := range TimestampSources {
+		if s == string(source) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unknown timestamp source %q (expected one of %v)", s, TimestampSources)
+}
+
+// Timestamp returns the time from the given source, and whether
+// there was one. The image config created time is taken from
+// CreatedAt, so this is only meaningful for an Info as fetched.
+func (im Info) Timestamp(source TimestampSource) (time.Time, bool) {
+	var t time.Time
+	switch source {
+	case TimestampAnnotation:
+		t = im.Annotations.Created
+		if t.IsZero() {
+			t = im.Annotations.BuildDate
+		}
+	case TimestampLabel:
+		t = im.Labels.Created
+		if t.IsZero() {
+			t = im.Labels.BuildDate
+		}
+	case TimestampConfig:
+		t = im.CreatedAt
+		// Reproducible builds set the created time to the epoch (or
+		// leave it out); either way, it says nothing about when the
+		// image was built.
+		if t.Unix() <= 0 {
+			t = time.Time{}
+		}
+	case TimestampFirstSeen:
+		t = im.FirstSeen
+	}
+	return t, !t.IsZero()
+}
+
+// WithCreatedAtFrom returns the Info with CreatedAt set from the first
+// of the sources given that has a timestamp, and CreatedAtSource set
+// to that source. If none has a timestamp, CreatedAt is zero.
+func (im Info) WithCreatedAtFrom(sources []TimestampSource) Info {
+	for _, source := range sources {
+		if t, ok := im.Timestamp(source); ok {
+			im.CreatedAt, im.CreatedAtSource = t, source
+			return im
+		}
+	}
+	im.CreatedAt, im.CreatedAtSource = time.Time{}, ""
+	return im
+}
+
+// Soaking says whether the image is younger than the minimum age
+// given, going by the later of its creation time and when it was
+// first seen (a tag can be pushed long after the image was built). An
+// image with neither time is taken to be old enough, since it was
+// seen before first-seen times were recorded.
+func (im Info) Soaking(minAge time.Duration, now time.Time) bool {
+	if minAge <= 0 {
+		return false
+	}
+	appeared := im.CreatedAt
+	if im.FirstSeen.After(appeared) {
+		appeared = im.FirstSeen
+	}
+	return !appeared.IsZero() && now.Sub(appeared) < minAge
+}
@@ -0,0 +1,65 @@
+package image
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfo_WithCreatedAtFrom(t *testing.T) {
+	annotated := testTime.Add(time.Hour)
+	labelled := testTime.Add(2 * time.Hour)
+	firstSeen := testTime.Add(3 * time.Hour)
+
+	info := mustMakeInfo("my/image:tag", testTime)
+	info.Annotations = Labels{Created: annotated}
+	info.Labels = Labels{BuildDate: labelled}
+	info.FirstSeen = firstSeen
+
+	for _, c := range []struct {
+		sources  []TimestampSource
+		expected time.Time
+		source   TimestampSource
+	}{
+		{[]TimestampSource{TimestampAnnotation, TimestampConfig}, annotated, TimestampAnnotation},
+		{[]TimestampSource{TimestampLabel, TimestampAnnotation}, labelled, TimestampLabel},
+		{[]TimestampSource{TimestampConfig, TimestampLabel}, testTime, TimestampConfig},
+		{[]TimestampSource{TimestampFirstSeen}, firstSeen, TimestampFirstSeen},
+		{nil, time.Time{}, ""},
+	} {
+		resolved := info.WithCreatedAtFrom(c.sources)
+		assert.Equal(t, c.expected, resolved.CreatedAt, "%v", c.sources)
+		assert.Equal(t, c.source, resolved.CreatedAtSource, "%v", c.sources)
+	}
+
+	// A zeroed config timestamp, from a reproducible build, doesn't count
+	reproducible := mustMakeInfo("my/image:tag", time.Unix(0, 0))
+	reproducible.FirstSeen = firstSeen
+	resolved := reproducible.WithCreatedAtFrom([]TimestampSource{TimestampConfig, TimestampFirstSeen})
+	assert.Equal(t, firstSeen, resolved.CreatedAt)
+	assert.Equal(t, TimestampFirstSeen, resolved.CreatedAtSource)
+}
+
+func TestParseTimestampSource(t *testing.T) {
+	for _, source := range TimestampSources {
+		parsed, err := ParseTimestampSource(string(source))
+		assert.NoError(t, err)
+		assert.Equal(t, source, parsed)
+	}
+	_, err := ParseTimestampSource("build-date")
+	assert.Error(t, err)
+}
+
+func TestInfo_Soaking(t *testing.T) {
+	now := testTime.Add(24 * time.Hour)
+	built := mustMakeInfo("my/image:built", now.Add(-3*time.Hour))
+	pushed := mustMakeInfo("my/image:pushed", now.Add(-3*time.Hour))
+	pushed.FirstSeen = now.Add(-time.Hour)
+	unknown := mustMakeInfo("my/image:unknown", time.Time{})
+
+	assert.False(t, built.Soaking(2*time.Hour, now))
+	assert.True(t, pushed.Soaking(2*time.Hour, now))
+	assert.False(t, pushed.Soaking(0, now))
+	assert.False(t, unknown.Soaking(2*time.Hour, now))
+}
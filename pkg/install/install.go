@@ -14,6 +14,10 @@ import (
 
 //go:generate go run generate.go
 
+// RegistryCacheDisk is the value of TemplateParameters.RegistryCache
+// for keeping image metadata in a file, rather than in memcached.
+const RegistryCacheDisk = "disk"
+
 type TemplateParameters struct {
 	GitURL                  string
 	GitBranch               string
@@ -23,6 +27,7 @@ type TemplateParameters struct {
 	GitEmail                string
 	GitReadOnly             bool
 	RegistryDisableScanning bool
+	RegistryCache           string
 	Namespace               string
 	ManifestGeneration      bool
 	AdditionalFluxArgs      []string
@@ -38,8 +43,9 @@ func FillInTemplates(params TemplateParameters) (map[string][]byte, error) {
 		if info.IsDir() {
 			return nil
 		}
-		if params.RegistryDisableScanning && strings.Contains(info.Name(), "memcache") {
-			// do not include memcached resources when registry scanning is disabled
+		if (params.RegistryDisableScanning || params.RegistryCache == RegistryCacheDisk) && strings.Contains(info.Name(), "memcache") {
+			// do not include memcached resources when registry scanning
+			// is disabled, or the cache is kept elsewhere
 			return nil
 		}
 		manifestTemplateBytes, err := ioutil.ReadAll(rs)
@@ -87,6 +87,20 @@ func TestFillInTemplatesNoMemcached(t *testing.T) {
 	})
 }
 
+func TestFillInTemplatesDiskRegistryCache(t *testing.T) {
+	manifests := testFillInTemplates(t, 3, TemplateParameters{
+		GitURL:        "git@github.com:fluxcd/flux-get-started",
+		GitBranch:     "branch",
+		GitPaths:      []string{},
+		GitLabel:      "label",
+		RegistryCache: RegistryCacheDisk,
+	})
+	fluxDeploy := string(manifests["flux-deployment.yaml"])
+	assert.Contains(t, fluxDeploy, "- --registry-cache=disk")
+	assert.Contains(t, fluxDeploy, "mountPath: /var/fluxd/cache")
+	assert.NotContains(t, fluxDeploy, "- --memcached-service=")
+}
+
 func TestTestFillInTemplatesAddSecurityContext(t *testing.T) {
 	params := TemplateParameters{
 		GitURL:             "git@github.com:fluxcd/flux-get-started",
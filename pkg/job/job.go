@@ -31,6 +31,7 @@ const (
 // they happen, it's (almost) duplicated here.
 type Result struct {
 	Revision string        `json:"revision,omitempty"`
+	Tag      string        `json:"tag,omitempty"` // the release tag synced, if syncing from tags
 	Spec     *update.Spec  `json:"spec,omitempty"`
 	Result   update.Result `json:"result,omitempty"`
 }
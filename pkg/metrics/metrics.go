@@ -14,4 +14,8 @@ const (
 	LabelReleaseType = "release_type"
 	LabelReleaseKind = "release_kind"
 	LabelStage       = "stage"
+
+	// Labels for automation metrics
+	LabelWorkload  = "workload"
+	LabelContainer = "container"
 )
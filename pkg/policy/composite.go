@@ -0,0 +1,307 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/fluxcd/flux/pkg/image"
+)
+
+const exprPrefix = "expr:"
+
+// CompositePattern combines other patterns with `&&` (and), `||`
+// (or) and `!` (not), grouped with parentheses if need be; e.g.,
+//
+//	expr:semver:^1.4 && !regexp:-rc
+//	expr:(glob:main-* || glob:release-*) && !glob:*-debug
+//
+// Each pattern in the expression is written as it would be on its
+// own; one that contains spaces can be put in single or double
+// quotes, e.g., `'semver:>= 1.4, < 2'`.
+//
+// Images are ordered as the first pattern in the expression that
+// isn't negated would order them; if all are negated, they are
+// ordered by timestamp.
+type CompositePattern struct {
+	pattern string // pattern without prefix
+	expr    patternExpr
+	err     error
+}
+
+// patternExpr is a node in the expression of a CompositePattern.
+type patternExpr interface {
+	matches(tag string) bool
+	// ordering returns the pattern that decides the ordering of
+	// images, or nil if there is none
+	ordering() Pattern
+}
+
+type andExpr []patternExpr
+type orExpr []patternExpr
+type notExpr struct{ patternExpr }
+type leafExpr struct{ Pattern }
+
+func (e andExpr) matches(tag string) bool {
+	for _, sub := range e {
+		if !sub.matches(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e andExpr) ordering() Pattern {
+	return firstOrdering(e)
+}
+
+func (e orExpr) matches(tag string) bool {
+	for _, sub := range e {
+		if sub.matches(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e orExpr) ordering() Pattern {
+	return firstOrdering(e)
+}
+
+func firstOrdering(exprs []patternExpr) Pattern {
+	for _, sub := range exprs {
+		if p := sub.ordering(); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+func (e notExpr) matches(tag string) bool {
+	return !e.patternExpr.matches(tag)
+}
+
+func (e notExpr) ordering() Pattern {
+	return nil
+}
+
+func (e leafExpr) matches(tag string) bool {
+	return e.Pattern.Matches(tag)
+}
+
+func (e leafExpr) ordering() Pattern {
+	return e.Pattern
+}
+
+func newCompositePattern(pattern string) CompositePattern {
+	p := CompositePattern{pattern: pattern}
+	p.expr, p.err = parsePatternExpr(pattern)
+	return p
+}
+
+// Matches returns true if the tag satisfies the expression. Unlike
+// the other kinds of pattern, an invalid expression matches nothing,
+// since it's not possible to say what was meant.
+func (c CompositePattern) Matches(tag string) bool {
+	if c.err != nil {
+		return false
+	}
+	return c.expr.matches(tag)
+}
+
+func (c CompositePattern) String() string {
+	return exprPrefix + c.pattern
+}
+
+func (c CompositePattern) Newer(a, b *image.Info) bool {
+	if c.err == nil {
+		if p := c.expr.ordering(); p != nil {
+			return p.Newer(a, b)
+		}
+	}
+	return image.NewerByCreated(a, b)
+}
+
+func (c CompositePattern) Valid() bool {
+	return c.err == nil
+}
+
+// Err says what is wrong with the expression, if anything.
+func (c CompositePattern) Err() error {
+	return c.err
+}
+
+func (c CompositePattern) RequiresTimestamp() bool {
+	if c.err == nil {
+		if p := c.expr.ordering(); p != nil {
+			return p.RequiresTimestamp()
+		}
+	}
+	return true
+}
+
+// The grammar of expressions, in order of increasing precedence:
+//
+//	or    = and { "||" and }
+//	and   = unary { "&&" unary }
+//	unary = "!" unary | "(" or ")" | pattern
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func parsePatternExpr(s string) (patternExpr, error) {
+	tokens, err := tokenizePatternExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &exprParser{tokens: tokens}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	return expr, nil
+}
+
+func (p *exprParser) peek() (string, bool) {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos], true
+	}
+	return "", false
+}
+
+func (p *exprParser) or() (patternExpr, error) {
+	var exprs orExpr
+	for {
+		expr, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if tok, _ := p.peek(); tok != "||" {
+			break
+		}
+		p.pos++
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *exprParser) and() (patternExpr, error) {
+	var exprs andExpr
+	for {
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if tok, _ := p.peek(); tok != "&&" {
+			break
+		}
+		p.pos++
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *exprParser) unary() (patternExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("expression ends where a pattern was expected")
+	}
+	p.pos++
+	switch tok {
+	case "!":
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	case "(":
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if tok, _ := p.peek(); tok != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	case "&&", "||", ")":
+		return nil, fmt.Errorf("unexpected %q where a pattern was expected", tok)
+	}
+
+	pattern := unquote(tok)
+	if strings.HasPrefix(pattern, exprPrefix) {
+		return nil, fmt.Errorf("pattern %q cannot be an expression itself; use parentheses", pattern)
+	}
+	leaf := NewPattern(pattern)
+	if !leaf.Valid() {
+		return nil, fmt.Errorf("invalid pattern %q", pattern)
+	}
+	return leafExpr{leaf}, nil
+}
+
+// tokenizePatternExpr splits an expression into operators,
+// parentheses, and patterns. A pattern that isn't quoted goes up to
+// whitespace, an operator, or a closing parenthesis that doesn't
+// belong to the pattern (e.g., as in `regexp:^(a|b)$`).
+func tokenizePatternExpr(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case c == '!' || c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("missing closing quote for pattern starting %s", s[i:])
+			}
+			tokens = append(tokens, s[i:i+end+2])
+			i += end + 2
+		default:
+			start, depth := i, 0
+		pattern:
+			for ; i < len(s); i++ {
+				switch {
+				case unicode.IsSpace(rune(s[i])),
+					strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"):
+					break pattern
+				case s[i] == '(':
+					depth++
+				case s[i] == ')':
+					if depth == 0 {
+						break pattern
+					}
+					depth--
+				}
+			}
+			tokens = append(tokens, s[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+func unquote(tok string) string {
+	if len(tok) >= 2 && (tok[0] == '\'' || tok[0] == '"') && tok[len(tok)-1] == tok[0] {
+		return tok[1 : len(tok)-1]
+	}
+	return tok
+}
@@ -14,6 +14,9 @@ const (
 	semverPrefix    = "semver:"
 	regexpPrefix    = "regexp:"
 	regexpAltPrefix = "regex:"
+	numericPrefix   = "numeric:"
+	calverPrefix    = "calver:"
+	labelPrefix     = "label:"
 )
 
 var (
@@ -51,11 +54,46 @@ type RegexpPattern struct {
 	regexp  *regexp.Regexp
 }
 
+// NumericPattern matches tags by regular expression, and orders
+// them by the number in the first capture group (or the whole match,
+// if there is no group); e.g., `numeric:^build-(\d+)$`.
+type NumericPattern struct {
+	pattern string // pattern without prefix
+	regexp  *regexp.Regexp
+}
+
+// CalVerPattern matches tags that are calendar versions, e.g.,
+// `2024.05.17-3`, and also match the glob given (if any), and orders
+// them by version.
+// See https://calver.org/
+type CalVerPattern struct {
+	glob string // glob without prefix
+}
+
+// LabelPattern matches all tags, and orders them by the value of the
+// image label named; e.g., `label:build.number`. To filter tags as
+// well, it can be combined with another pattern in a
+// CompositePattern.
+type LabelPattern struct {
+	label string
+}
+
 // NewPattern instantiates a Pattern according to the prefix
 // it finds. The prefix can be either `glob:` (default if omitted),
-// `semver:` or `regexp:`.
+// `semver:`, `regexp:`, `numeric:`, `calver:`, `label:`, or `expr:`
+// for a CompositePattern.
 func NewPattern(pattern string) Pattern {
 	switch {
+	case strings.HasPrefix(pattern, exprPrefix):
+		return newCompositePattern(strings.TrimPrefix(pattern, exprPrefix))
+	case strings.HasPrefix(pattern, numericPrefix):
+		pattern = strings.TrimPrefix(pattern, numericPrefix)
+		r, _ := regexp.Compile(pattern)
+		return NumericPattern{pattern, r}
+	case strings.HasPrefix(pattern, calverPrefix):
+		return CalVerPattern{strings.TrimPrefix(pattern, calverPrefix)}
+	case strings.HasPrefix(pattern, labelPrefix):
+		return LabelPattern{strings.TrimPrefix(pattern, labelPrefix)}
 	case strings.HasPrefix(pattern, semverPrefix):
 		pattern = strings.TrimPrefix(pattern, semverPrefix)
 		c, _ := semver.NewConstraint(pattern)
@@ -144,3 +182,204 @@ func (r RegexpPattern) Valid() bool {
 func (r RegexpPattern) RequiresTimestamp() bool {
 	return true
 }
+
+// number returns the part of the tag used to order it, and whether
+// the tag matched.
+func (n NumericPattern) number(tag string) (string, bool) {
+	if n.regexp == nil {
+		return "", false
+	}
+	m := n.regexp.FindStringSubmatch(tag)
+	if m == nil {
+		return "", false
+	}
+	num := m[0]
+	if len(m) > 1 {
+		num = m[1]
+	}
+	return num, isDigits(num)
+}
+
+// Matches returns true if the tag matches the regexp and has a
+// number where expected. Unlike RegexpPattern, an invalid regexp
+// matches nothing, since there would be no way to order the tags.
+func (n NumericPattern) Matches(tag string) bool {
+	_, ok := n.number(tag)
+	return ok
+}
+
+func (n NumericPattern) String() string {
+	return numericPrefix + n.pattern
+}
+
+func (n NumericPattern) Newer(a, b *image.Info) bool {
+	an, aok := n.number(a.ID.Tag)
+	bn, bok := n.number(b.ID.Tag)
+	return newerByValue(a, b, an, bn, aok, bok, compareNumbers)
+}
+
+func (n NumericPattern) Valid() bool {
+	return n.regexp != nil
+}
+
+func (n NumericPattern) RequiresTimestamp() bool {
+	return false
+}
+
+func (c CalVerPattern) Matches(tag string) bool {
+	if _, ok := parseCalVer(tag); !ok {
+		return false
+	}
+	return c.glob == "" || glob.Glob(c.glob, tag)
+}
+
+func (c CalVerPattern) String() string {
+	return calverPrefix + c.glob
+}
+
+func (c CalVerPattern) Newer(a, b *image.Info) bool {
+	av, aok := parseCalVer(a.ID.Tag)
+	bv, bok := parseCalVer(b.ID.Tag)
+	return newerByValue(a, b, av, bv, aok, bok, compareCalVers)
+}
+
+func (c CalVerPattern) Valid() bool {
+	return true
+}
+
+func (c CalVerPattern) RequiresTimestamp() bool {
+	return false
+}
+
+func (l LabelPattern) Matches(tag string) bool {
+	return true
+}
+
+func (l LabelPattern) String() string {
+	return labelPrefix + l.label
+}
+
+// Newer orders images by the value of the label, numerically if both
+// values are numbers and otherwise as strings. Images without the
+// label are older than those with it.
+func (l LabelPattern) Newer(a, b *image.Info) bool {
+	av, aok := a.Labels.Get(l.label)
+	bv, bok := b.Labels.Get(l.label)
+	return newerByValue(a, b, av, bv, aok, bok, compareLabels)
+}
+
+func (l LabelPattern) Valid() bool {
+	return l.label != ""
+}
+
+func (l LabelPattern) RequiresTimestamp() bool {
+	return false
+}
+
+// OrderingLabel returns the name of the image label by which the
+// pattern orders images, and whether it orders them by a label.
+func OrderingLabel(p Pattern) (string, bool) {
+	switch p := p.(type) {
+	case LabelPattern:
+		return p.label, p.Valid()
+	case CompositePattern:
+		if p.err == nil {
+			if o := p.expr.ordering(); o != nil {
+				return OrderingLabel(o)
+			}
+		}
+	}
+	return "", false
+}
+
+// newerByValue returns true if image `a` is newer than image `b`,
+// given the values used to order them, and whether each has a
+// value. An image without a value is older than one with; when
+// neither has, or the values are the same, images are ordered by
+// ref, as in image.NewerBySemver.
+func newerByValue(a, b *image.Info, av, bv interface{}, aok, bok bool, compare func(a, b interface{}) int) bool {
+	switch {
+	case aok && !bok:
+		return true
+	case bok && !aok:
+		return false
+	case aok && bok:
+		if cmp := compare(av, bv); cmp != 0 {
+			return cmp > 0
+		}
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareDigits compares two strings of decimal digits by their
+// numeric value, however long they are.
+func compareDigits(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) > len(b) {
+			return 1
+		}
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareNumbers(a, b interface{}) int {
+	return compareDigits(a.(string), b.(string))
+}
+
+func compareLabels(a, b interface{}) int {
+	as, bs := a.(string), b.(string)
+	if isDigits(as) && isDigits(bs) {
+		return compareDigits(as, bs)
+	}
+	return strings.Compare(as, bs)
+}
+
+// parseCalVer splits a calendar version into its numbers. A calendar
+// version is two or more numbers separated by `.`, `-` or `_`, the
+// first being a year (of two or four digits); e.g., `2024.05.17-3`
+// or `24.05`.
+func parseCalVer(tag string) ([]string, bool) {
+	parts := strings.FieldsFunc(tag, func(c rune) bool {
+		return c == '.' || c == '-' || c == '_'
+	})
+	if len(parts) < 2 || (len(parts[0]) != 2 && len(parts[0]) != 4) {
+		return nil, false
+	}
+	// FieldsFunc skips empty fields, so check nothing was skipped
+	if len(strings.Join(parts, ".")) != len(tag) {
+		return nil, false
+	}
+	for _, p := range parts {
+		if !isDigits(p) {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// compareCalVers compares calendar versions number by number; if
+// one is the other with more numbers, it's the newer (so
+// `2024.05.17-3` is newer than `2024.05.17`).
+func compareCalVers(a, b interface{}) int {
+	av, bv := a.([]string), b.([]string)
+	for i := 0; i < len(av) && i < len(bv); i++ {
+		if cmp := compareDigits(av[i], bv[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(av) - len(bv)
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/image"
 )
 
 func TestGlobPattern_Matches(t *testing.T) {
@@ -127,3 +129,168 @@ func TestRegexpPattern_Matches(t *testing.T) {
 		}
 	}
 }
+
+func TestCompositePattern_Matches(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		pattern string
+		true    []string
+		false   []string
+	}{
+		{
+			name:    "semver but not release candidates",
+			pattern: "expr:semver:^1.4 && !regexp:-rc",
+			true:    []string{"1.4.0", "1.5.2"},
+			false:   []string{"1.5.0-rc.1", "1.3.9", "2.0.0", "latest"},
+		},
+		{
+			name:    "glob but not debug",
+			pattern: "expr:glob:main-* && !glob:main-*-debug",
+			true:    []string{"main-abc123"},
+			false:   []string{"main-abc123-debug", "dev-abc123"},
+		},
+		{
+			name:    "grouping and precedence",
+			pattern: "expr:(glob:main-* || release-*) && !regexp:^(main|release)-.*-debug$",
+			true:    []string{"main-a", "release-b"},
+			false:   []string{"main-a-debug", "release-b-debug", "dev-c"},
+		},
+		{
+			name:    "quoted patterns",
+			pattern: `expr:'semver:>= 1.4, < 2' || "glob:stable"`,
+			true:    []string{"1.4.0", "1.9.9", "stable"},
+			false:   []string{"2.0.0", "1.3.0", "unstable"},
+		},
+	} {
+		pattern := NewPattern(tt.pattern)
+		assert.IsType(t, CompositePattern{}, pattern)
+		assert.True(t, pattern.Valid(), tt.pattern)
+		assert.Equal(t, tt.pattern, pattern.String())
+		for _, tag := range tt.true {
+			t.Run(fmt.Sprintf("%s[%q]", tt.name, tag), func(t *testing.T) {
+				assert.True(t, pattern.Matches(tag))
+			})
+		}
+		for _, tag := range tt.false {
+			t.Run(fmt.Sprintf("%s[%q]", tt.name, tag), func(t *testing.T) {
+				assert.False(t, pattern.Matches(tag))
+			})
+		}
+	}
+}
+
+func TestCompositePattern_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"expr:",
+		"expr:glob:main-* &&",
+		"expr:(glob:main-*",
+		"expr:glob:main-* glob:dev-*",
+		"expr:semver:not-a-constraint && glob:*",
+		"expr:!regexp:(",
+		"expr:expr:glob:*",
+		"expr:'semver:^1.0",
+	} {
+		pattern := NewPattern(expr)
+		assert.False(t, pattern.Valid(), expr)
+		assert.False(t, pattern.Matches("1.0.0"), expr)
+	}
+}
+
+func TestCompositePattern_Ordering(t *testing.T) {
+	semverFirst := NewPattern("expr:!glob:*-rc* && semver:^1 && glob:*")
+	assert.False(t, semverFirst.RequiresTimestamp())
+	a, b := &image.Info{ID: image.Ref{Tag: "1.10.0"}}, &image.Info{ID: image.Ref{Tag: "1.9.0"}}
+	assert.True(t, semverFirst.Newer(a, b))
+
+	onlyNegated := NewPattern("expr:!glob:*-debug")
+	assert.True(t, onlyNegated.RequiresTimestamp())
+	globFirst := NewPattern("expr:glob:1.* || semver:^1")
+	assert.True(t, globFirst.RequiresTimestamp())
+}
+
+func TestNumericPattern(t *testing.T) {
+	pattern := NewPattern(`numeric:^build-(\d+)$`)
+	assert.IsType(t, NumericPattern{}, pattern)
+	assert.True(t, pattern.Valid())
+	assert.False(t, pattern.RequiresTimestamp())
+	for _, tag := range []string{"build-1", "build-1234"} {
+		assert.True(t, pattern.Matches(tag), tag)
+	}
+	for _, tag := range []string{"", "build-", "build-12a", "latest"} {
+		assert.False(t, pattern.Matches(tag), tag)
+	}
+
+	newer, older := &image.Info{ID: image.Ref{Tag: "build-1000"}}, &image.Info{ID: image.Ref{Tag: "build-999"}}
+	assert.True(t, pattern.Newer(newer, older))
+	assert.False(t, pattern.Newer(older, newer))
+
+	whole := NewPattern(`numeric:^\d+$`)
+	assert.True(t, whole.Newer(&image.Info{ID: image.Ref{Tag: "10"}}, &image.Info{ID: image.Ref{Tag: "9"}}))
+
+	invalid := NewPattern("numeric:(")
+	assert.False(t, invalid.Valid())
+	assert.False(t, invalid.Matches("1"))
+}
+
+func TestCalVerPattern(t *testing.T) {
+	pattern := NewPattern("calver:")
+	assert.IsType(t, CalVerPattern{}, pattern)
+	assert.True(t, pattern.Valid())
+	assert.False(t, pattern.RequiresTimestamp())
+	for _, tag := range []string{"2024.05.17", "2024.05.17-3", "24.05", "2024_05_17"} {
+		assert.True(t, pattern.Matches(tag), tag)
+	}
+	for _, tag := range []string{"", "2024", "1.2.3", "2024..05", "2024.05-rc1", "latest"} {
+		assert.False(t, pattern.Matches(tag), tag)
+	}
+
+	year := NewPattern("calver:2024.*")
+	assert.True(t, year.Matches("2024.12.01"))
+	assert.False(t, year.Matches("2023.12.01"))
+
+	for _, tt := range []struct{ newer, older string }{
+		{"2024.05.17", "2024.05.16"},
+		{"2024.10.01", "2024.9.30"},
+		{"2024.05.17-3", "2024.05.17"},
+		{"2024.05.17-10", "2024.05.17-9"},
+	} {
+		newer, older := &image.Info{ID: image.Ref{Tag: tt.newer}}, &image.Info{ID: image.Ref{Tag: tt.older}}
+		assert.True(t, pattern.Newer(newer, older), tt.newer)
+		assert.False(t, pattern.Newer(older, newer), tt.newer)
+	}
+}
+
+func TestLabelPattern(t *testing.T) {
+	pattern := NewPattern("label:build.number")
+	assert.IsType(t, LabelPattern{}, pattern)
+	assert.True(t, pattern.Valid())
+	assert.False(t, pattern.RequiresTimestamp())
+	assert.True(t, pattern.Matches("anything"))
+	assert.False(t, NewPattern("label:").Valid())
+
+	build := func(tag, number string) *image.Info {
+		info := &image.Info{ID: image.Ref{Tag: tag}}
+		if number != "" {
+			info.Labels.Other = map[string]string{"build.number": number}
+		}
+		return info
+	}
+	assert.True(t, pattern.Newer(build("a", "10"), build("b", "9")))
+	assert.False(t, pattern.Newer(build("a", "9"), build("b", "10")))
+	assert.True(t, pattern.Newer(build("a", "1"), build("b", "")))
+	assert.False(t, pattern.Newer(build("a", ""), build("b", "1")))
+
+	// combined with a filter, the label still decides the order
+	combined := NewPattern("expr:label:build.number && glob:main-*")
+	assert.True(t, combined.Matches("main-abc"))
+	assert.False(t, combined.Matches("dev-abc"))
+	assert.True(t, combined.Newer(build("main-b", "10"), build("main-a", "9")))
+
+	label, ok := OrderingLabel(combined)
+	assert.True(t, ok)
+	assert.Equal(t, "build.number", label)
+	_, ok = OrderingLabel(NewPattern("expr:glob:main-* && label:build.number"))
+	assert.False(t, ok)
+	_, ok = OrderingLabel(NewPattern("semver:^1.0"))
+	assert.False(t, ok)
+}
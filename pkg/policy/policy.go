@@ -2,16 +2,21 @@ package policy
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 )
 
 const (
-	Ignore     = Policy("ignore")
-	Locked     = Policy("locked")
-	LockedUser = Policy("locked_user")
-	LockedMsg  = Policy("locked_msg")
-	Automated  = Policy("automated")
-	TagAll     = Policy("tag_all")
+	Ignore      = Policy("ignore")
+	Locked      = Policy("locked")
+	LockedUser  = Policy("locked_user")
+	LockedMsg   = Policy("locked_msg")
+	LockedUntil = Policy("locked_until")
+	Automated   = Policy("automated")
+	TagAll      = Policy("tag_all")
+	PinDigest   = Policy("pin_digest")
+	ImageGroup  = Policy("image-group")
 )
 
 const IgnoreSyncOnly = "sync_only"
@@ -22,7 +27,7 @@ type Policy string
 
 func Boolean(policy Policy) bool {
 	switch policy {
-	case Locked, Automated, Ignore:
+	case Locked, Automated, Ignore, PinDigest:
 		return true
 	}
 	return false
@@ -36,6 +41,188 @@ func Tag(policy Policy) bool {
 	return strings.HasPrefix(string(policy), "tag.")
 }
 
+// AutomatedPropose is the value of the automated policy (for a
+// workload or a container) that has automation propose updates,
+// which wait to be approved, rather than make them.
+const AutomatedPropose = "propose"
+
+// AutomatedNotify is the value of the automated policy that has
+// automation only report the updates it finds, rather than make or
+// propose them.
+const AutomatedNotify = "notify"
+
+// AutomatedPrefix is the policy for automating (or not) a particular
+// container, which takes precedence over Automated for the workload;
+// e.g., to leave a sidecar out of the automation of a workload.
+func AutomatedPrefix(container string) Policy {
+	return Policy("automated." + container)
+}
+
+// getAutomation returns the value of the automated policy that
+// applies to the container.
+func getAutomation(policies Set, container string) string {
+	if v, ok := policies.Get(AutomatedPrefix(container)); ok {
+		return v
+	}
+	v, _ := policies.Get(Automated)
+	return v
+}
+
+// GetAutomated says whether automation should update the container.
+func GetAutomated(policies Set, container string) bool {
+	return automates(getAutomation(policies, container))
+}
+
+// automates says whether the value of an automated policy has
+// automation make updates; any value other than `false` and the
+// modes that hold updates back does, as the policy did before there
+// were modes.
+func automates(value string) bool {
+	switch value {
+	case "", "false", AutomatedPropose, AutomatedNotify:
+		return false
+	}
+	return true
+}
+
+// GetProposed says whether automation should propose updates to the
+// container, rather than make them.
+func GetProposed(policies Set, container string) bool {
+	return getAutomation(policies, container) == AutomatedPropose
+}
+
+// GetNotify says whether automation should only report updates
+// available to the container.
+func GetNotify(policies Set, container string) bool {
+	return getAutomation(policies, container) == AutomatedNotify
+}
+
+// AnyAutomated says whether automation could update any container
+// of a workload with the policies given; that is, whether the
+// workload is automated, or any of its containers are.
+func AnyAutomated(policies Set) bool {
+	return anyAutomation(policies, automates)
+}
+
+// AnyProposed says whether automation could propose updates to any
+// container of a workload with the policies given.
+func AnyProposed(policies Set) bool {
+	return anyAutomation(policies, func(v string) bool { return v == AutomatedPropose })
+}
+
+// AnyNotify says whether automation could report updates available
+// to any container of a workload with the policies given.
+func AnyNotify(policies Set) bool {
+	return anyAutomation(policies, func(v string) bool { return v == AutomatedNotify })
+}
+
+func anyAutomation(policies Set, mode func(value string) bool) bool {
+	if v, ok := policies.Get(Automated); ok && mode(v) {
+		return true
+	}
+	for p, v := range policies {
+		if strings.HasPrefix(string(p), "automated.") && mode(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetImageGroup returns the image group the container belongs to, if
+// any. Automation keeps all the containers in an image group on the
+// same tag; since only containers that are automated (or have updates
+// proposed or reported) are looked at by automation, only those belong
+// to the group of their workload.
+func GetImageGroup(policies Set, container string) (string, bool) {
+	group, ok := policies.Get(ImageGroup)
+	if !ok || group == "" || !(GetAutomated(policies, container) || GetProposed(policies, container) || GetNotify(policies, container)) {
+		return "", false
+	}
+	return group, true
+}
+
+// PinDigestPrefix is the policy for pinning (or not) the image of a
+// particular container to a digest, which takes precedence over
+// PinDigest for the workload.
+func PinDigestPrefix(container string) Policy {
+	return Policy("pin_digest." + container)
+}
+
+// GetPinDigest says whether images for the container should be
+// written with the digest as well as the tag, and whether there is a
+// policy saying either way.
+func GetPinDigest(policies Set, container string) (bool, bool) {
+	if v, ok := policies.Get(PinDigestPrefix(container)); ok {
+		return v == "true", true
+	}
+	if v, ok := policies.Get(PinDigest); ok {
+		return v == "true", true
+	}
+	return false, false
+}
+
+// MinAgePrefix is the policy giving how old an image must be before
+// automation will update the container to it; e.g., `2h`.
+func MinAgePrefix(container string) Policy {
+	return Policy("min-age." + container)
+}
+
+func MinAge(policy Policy) bool {
+	return strings.HasPrefix(string(policy), "min-age.")
+}
+
+// ParseMinAge parses the value of a min-age policy.
+func ParseMinAge(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative minimum age %q", value)
+	}
+	return d, nil
+}
+
+// GetMinAge returns the minimum age of images for the container, or
+// zero if there's no minimum.
+func GetMinAge(policies Set, container string) (time.Duration, error) {
+	value, ok := policies.Get(MinAgePrefix(container))
+	if !ok {
+		return 0, nil
+	}
+	return ParseMinAge(value)
+}
+
+// ParseLockedUntil parses the value of the locked_until policy, which
+// is a time in RFC3339 format; e.g., `2020-10-11T09:41:00Z`.
+func ParseLockedUntil(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
+// GetLockedUntil returns when the lock on a workload runs out, if it
+// is locked and the lock is for a limited time.
+func GetLockedUntil(policies Set) (time.Time, bool) {
+	if !policies.Has(Locked) {
+		return time.Time{}, false
+	}
+	value, ok := policies.Get(LockedUntil)
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := ParseLockedUntil(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// LockExpired says whether a workload is locked, but only until a
+// time that has passed.
+func LockExpired(policies Set, now time.Time) bool {
+	until, ok := GetLockedUntil(policies)
+	return ok && !now.Before(until)
+}
+
 func GetTagPattern(policies Set, container string) Pattern {
 	if policies == nil {
 		return PatternAll
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -88,3 +89,124 @@ func Test_GetTagPattern(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetPinDigest(t *testing.T) {
+	container := "helloContainer"
+	tests := []struct {
+		name     string
+		policies Set
+		want     bool
+		set      bool
+	}{
+		{name: "Nil policies", policies: nil, want: false},
+		{name: "Workload", policies: Set{PinDigest: "true"}, want: true, set: true},
+		{name: "Workload unpinned", policies: Set{PinDigest: "false"}, want: false, set: true},
+		{name: "Container", policies: Set{PinDigestPrefix(container): "true"}, want: true, set: true},
+		{name: "Other container", policies: Set{PinDigestPrefix("other"): "true"}, want: false},
+		{name: "Container overrides workload", policies: Set{PinDigest: "true", PinDigestPrefix(container): "false"}, want: false, set: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pin, set := GetPinDigest(tt.policies, container)
+			assert.Equal(t, tt.want, pin)
+			assert.Equal(t, tt.set, set)
+		})
+	}
+}
+
+func Test_GetProposed(t *testing.T) {
+	container := "helloContainer"
+	tests := []struct {
+		name               string
+		policies           Set
+		proposed, automate bool
+		anyAutomated       bool
+	}{
+		{name: "Nil policies", policies: nil},
+		{name: "Workload", policies: Set{Automated: AutomatedPropose}, proposed: true},
+		{name: "Container", policies: Set{AutomatedPrefix(container): AutomatedPropose}, proposed: true},
+		{name: "Container automated in proposed workload", policies: Set{Automated: AutomatedPropose, AutomatedPrefix(container): "true"}, automate: true, anyAutomated: true},
+		{name: "Container excluded", policies: Set{Automated: AutomatedPropose, AutomatedPrefix(container): "false"}},
+		{name: "Workload automated with another value", policies: Set{Automated: "yes"}, automate: true, anyAutomated: true},
+		{name: "Container excluded from automated workload", policies: Set{Automated: "yes", AutomatedPrefix(container): "false"}, anyAutomated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.proposed, GetProposed(tt.policies, container))
+			assert.Equal(t, tt.automate, GetAutomated(tt.policies, container))
+			assert.Equal(t, tt.anyAutomated, AnyAutomated(tt.policies))
+		})
+	}
+}
+
+func Test_GetNotify(t *testing.T) {
+	container := "helloContainer"
+	tests := []struct {
+		name             string
+		policies         Set
+		notify, proposed bool
+		anyNotify, group bool
+	}{
+		{name: "Nil policies", policies: nil},
+		{name: "Workload", policies: Set{Automated: AutomatedNotify}, notify: true, anyNotify: true},
+		{name: "Container", policies: Set{AutomatedPrefix(container): AutomatedNotify}, notify: true, anyNotify: true},
+		{name: "Container proposed in notify workload", policies: Set{Automated: AutomatedNotify, AutomatedPrefix(container): AutomatedPropose}, proposed: true, anyNotify: true},
+		{name: "Grouped", policies: Set{Automated: AutomatedNotify, ImageGroup: "app"}, notify: true, anyNotify: true, group: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.notify, GetNotify(tt.policies, container))
+			assert.Equal(t, tt.anyNotify, AnyNotify(tt.policies))
+			assert.Equal(t, tt.proposed, GetProposed(tt.policies, container))
+			_, grouped := GetImageGroup(tt.policies, container)
+			assert.Equal(t, tt.group, grouped)
+		})
+	}
+}
+
+func Test_LockExpired(t *testing.T) {
+	now := time.Date(2020, 10, 11, 9, 41, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		policies Set
+		until    bool
+		want     bool
+	}{
+		{name: "Nil policies", policies: nil},
+		{name: "Locked indefinitely", policies: Set{Locked: "true"}},
+		{name: "Not expired", policies: Set{Locked: "true", LockedUntil: "2020-10-11T10:00:00Z"}, until: true},
+		{name: "Expired", policies: Set{Locked: "true", LockedUntil: "2020-10-11T09:00:00Z"}, until: true, want: true},
+		{name: "Not locked", policies: Set{LockedUntil: "2020-10-11T09:00:00Z"}},
+		{name: "Invalid expiry", policies: Set{Locked: "true", LockedUntil: "tomorrow"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := GetLockedUntil(tt.policies)
+			assert.Equal(t, tt.until, ok)
+			assert.Equal(t, tt.want, LockExpired(tt.policies, now))
+		})
+	}
+}
+
+func Test_GetImageGroup(t *testing.T) {
+	container := "helloContainer"
+	tests := []struct {
+		name     string
+		policies Set
+		want     string
+	}{
+		{name: "Nil policies", policies: nil, want: ""},
+		{name: "Not automated", policies: Set{ImageGroup: "billing"}, want: ""},
+		{name: "Automated", policies: Set{ImageGroup: "billing", Automated: "true"}, want: "billing"},
+		{name: "Container automated", policies: Set{ImageGroup: "billing", AutomatedPrefix(container): "true"}, want: "billing"},
+		{name: "Container excluded", policies: Set{ImageGroup: "billing", Automated: "true", AutomatedPrefix(container): "false"}, want: ""},
+		{name: "Container proposed", policies: Set{ImageGroup: "billing", Automated: "true", AutomatedPrefix(container): AutomatedPropose}, want: "billing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group, ok := GetImageGroup(tt.policies, container)
+			assert.Equal(t, tt.want, group)
+			assert.Equal(t, tt.want != "", ok)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+// Package disk implements an image DB cache using an embedded,
+// on-disk key-value store (bbolt), for when running memcached
+// alongside fluxd is more trouble than it's worth.
+//
+// Items are given an expiry in the same way as with memcached: based
+// on their refresh deadline, with a minimum duration so that things
+// will expire well after they would have been refreshed.
+//
+// The total size of the items stored is kept under a maximum; when it
+// goes over, expired items are removed, then those closest to
+// expiring, until there is room again. As with memcached evicting
+// things, we can recover from that -- we'll just get a cache miss,
+// and fetch it again.
+//
+// Since the store is a file, what's cached survives fluxd restarting,
+// so long as the file is somewhere that survives too.
+package disk
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/fluxcd/flux/pkg/registry/cache"
+)
+
+const (
+	// The minimum expiry given to an entry.
+	MinExpiry = time.Hour
+	// When evicting items to make room, go this far under the
+	// maximum size, so we don't have to evict again straight away.
+	evictTo = 0.9
+)
+
+var (
+	entriesBucket = []byte("entries")
+	expiryBucket  = []byte("expiry")
+	metaBucket    = []byte("meta")
+	sizeKey       = []byte("size")
+)
+
+// DiskClient is a cache client that keeps items in a file on disk.
+type DiskClient struct {
+	db      *bolt.DB
+	maxSize int64
+	logger  log.Logger
+
+	quit chan struct{}
+	wait sync.WaitGroup
+}
+
+// DiskConfig defines how a DiskClient should be constructed.
+type DiskConfig struct {
+	// Path is the file in which to keep the cache; it's created if
+	// it doesn't exist.
+	Path string
+	// MaxSize is the most, in bytes, that the items stored should
+	// take up.
+	MaxSize int64
+	// GCInterval is how often to remove expired items.
+	GCInterval time.Duration
+	Logger     log.Logger
+}
+
+func NewDiskClient(config DiskConfig) (*DiskClient, error) {
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0700); err != nil {
+		return nil, errors.Wrap(err, "creating directory for registry cache file")
+	}
+	db, err := bolt.Open(config.Path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening registry cache file %s", config.Path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{entriesBucket, expiryBucket, metaBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, errors.Wrapf(err, "initialising registry cache file %s", config.Path)
+	}
+
+	client := &DiskClient{
+		db:      db,
+		maxSize: config.MaxSize,
+		logger:  config.Logger,
+		quit:    make(chan struct{}),
+	}
+	client.wait.Add(1)
+	go client.gcLoop(config.GCInterval)
+	return client, nil
+}
+
+// An entry is stored as the refresh deadline and expiry (as Unix
+// times), followed by the value.
+func encodeEntry(deadline, expiry time.Time, v []byte) []byte {
+	buf := make([]byte, 16, 16+len(v))
+	binary.BigEndian.PutUint64(buf[:8], uint64(deadline.Unix()))
+	binary.BigEndian.PutUint64(buf[8:16], uint64(expiry.Unix()))
+	return append(buf, v...)
+}
+
+func decodeEntry(entry []byte) (deadline, expiry time.Time, v []byte) {
+	deadline = time.Unix(int64(binary.BigEndian.Uint64(entry[:8])), 0)
+	expiry = time.Unix(int64(binary.BigEndian.Uint64(entry[8:16])), 0)
+	return deadline, expiry, entry[16:]
+}
+
+// Entries are indexed by expiry, so that those expiring soonest can
+// be found (and removed) quickly.
+func expiryIndexKey(expiry time.Time, key []byte) []byte {
+	buf := make([]byte, 8, 8+len(key))
+	binary.BigEndian.PutUint64(buf, uint64(expiry.Unix()))
+	return append(buf, key...)
+}
+
+// GetKey gets the value and its refresh deadline from the cache.
+func (c *DiskClient) GetKey(k cache.Keyer) ([]byte, time.Time, error) {
+	var (
+		value    []byte
+		deadline time.Time
+		expired  bool
+	)
+	err := c.db.View(func(tx *bolt.Tx) error {
+		entry := tx.Bucket(entriesBucket).Get([]byte(k.Key()))
+		if entry == nil {
+			return cache.ErrNotCached
+		}
+		var expiry time.Time
+		var v []byte
+		deadline, expiry, v = decodeEntry(entry)
+		if time.Now().After(expiry) {
+			expired = true
+			return cache.ErrNotCached
+		}
+		// The slice is only valid during the transaction
+		value = append([]byte(nil), v...)
+		return nil
+	})
+	if err == cache.ErrNotCached {
+		if expired {
+			if err := c.db.Update(func(tx *bolt.Tx) error {
+				return removeExpired(tx, []byte(k.Key()), time.Now())
+			}); err != nil {
+				c.logger.Log("err", errors.Wrap(err, "removing expired entry from cache"))
+			}
+		}
+		// Don't log on cache miss
+		return []byte{}, time.Time{}, cache.ErrNotCached
+	}
+	if err != nil {
+		c.logger.Log("err", errors.Wrap(err, "fetching from cache"))
+		return []byte{}, time.Time{}, err
+	}
+	return value, deadline, nil
+}
+
+// SetKey sets the value and its refresh deadline at a key. NB the key
+// expiry is set _longer_ than the deadline, to give us a grace period
+// in which to refresh the value.
+func (c *DiskClient) SetKey(k cache.Keyer, refreshDeadline time.Time, v []byte) error {
+	now := time.Now()
+	expiry := refreshDeadline.Sub(now) * 2
+	if expiry < MinExpiry {
+		expiry = MinExpiry
+	}
+
+	key := []byte(k.Key())
+	entry := encodeEntry(refreshDeadline, now.Add(expiry), v)
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		size, err := remove(tx, key)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(entriesBucket).Put(key, entry); err != nil {
+			return err
+		}
+		if err := tx.Bucket(expiryBucket).Put(expiryIndexKey(now.Add(expiry), key), []byte{}); err != nil {
+			return err
+		}
+		size += int64(len(key) + len(entry))
+		if c.maxSize > 0 && size > c.maxSize {
+			if size, err = evict(tx, size, int64(float64(c.maxSize)*evictTo)); err != nil {
+				return err
+			}
+		}
+		return putSize(tx, size)
+	})
+	if err != nil {
+		c.logger.Log("err", errors.Wrap(err, "storing in cache"))
+		return err
+	}
+	return nil
+}
+
+// Stop the disk client, and close the file.
+func (c *DiskClient) Stop() {
+	close(c.quit)
+	c.wait.Wait()
+	if err := c.db.Close(); err != nil {
+		c.logger.Log("err", errors.Wrap(err, "closing registry cache file"))
+	}
+}
+
+func (c *DiskClient) gcLoop(interval time.Duration) {
+	defer c.wait.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.db.Update(func(tx *bolt.Tx) error {
+				size, err := getSize(tx)
+				if err != nil {
+					return err
+				}
+				if size, err = evictExpired(tx, size, time.Now()); err != nil {
+					return err
+				}
+				return putSize(tx, size)
+			}); err != nil {
+				c.logger.Log("err", errors.Wrap(err, "removing expired entries from cache"))
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+func getSize(tx *bolt.Tx) (int64, error) {
+	b := tx.Bucket(metaBucket).Get(sizeKey)
+	if b == nil {
+		return 0, nil
+	}
+	if len(b) != 8 {
+		return 0, errors.New("corrupt size record in cache")
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+func putSize(tx *bolt.Tx, size int64) error {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(size))
+	return tx.Bucket(metaBucket).Put(sizeKey, b)
+}
+
+// remove deletes the entry at the key, if there is one, returning
+// the size of what's left.
+func remove(tx *bolt.Tx, key []byte) (int64, error) {
+	size, err := getSize(tx)
+	if err != nil {
+		return 0, err
+	}
+	entries := tx.Bucket(entriesBucket)
+	entry := entries.Get(key)
+	if entry == nil {
+		return size, nil
+	}
+	_, expiry, _ := decodeEntry(entry)
+	size -= int64(len(key) + len(entry))
+	if err := tx.Bucket(expiryBucket).Delete(expiryIndexKey(expiry, key)); err != nil {
+		return 0, err
+	}
+	return size, entries.Delete(key)
+}
+
+// removeExpired deletes the entry at the key if it has expired; it
+// may have been refreshed since it was looked at.
+func removeExpired(tx *bolt.Tx, key []byte, now time.Time) error {
+	entry := tx.Bucket(entriesBucket).Get(key)
+	if entry == nil {
+		return nil
+	}
+	if _, expiry, _ := decodeEntry(entry); !now.After(expiry) {
+		return nil
+	}
+	size, err := remove(tx, key)
+	if err != nil {
+		return err
+	}
+	return putSize(tx, size)
+}
+
+// evictExpired removes all the entries that have expired, returning
+// the size of what's left.
+func evictExpired(tx *bolt.Tx, size int64, now time.Time) (int64, error) {
+	return evictWhile(tx, size, func(expiry time.Time, size int64) bool {
+		return now.After(expiry)
+	})
+}
+
+// evict removes expired entries, then those closest to expiring,
+// until the size of what's left is no more than the target.
+func evict(tx *bolt.Tx, size, target int64) (int64, error) {
+	return evictWhile(tx, size, func(_ time.Time, size int64) bool {
+		return size > target
+	})
+}
+
+func evictWhile(tx *bolt.Tx, size int64, more func(expiry time.Time, size int64) bool) (int64, error) {
+	entries := tx.Bucket(entriesBucket)
+	index := tx.Bucket(expiryBucket).Cursor()
+	for k, _ := index.First(); k != nil; k, _ = index.First() {
+		expiry := time.Unix(int64(binary.BigEndian.Uint64(k[:8])), 0)
+		if !more(expiry, size) {
+			break
+		}
+		key := append([]byte(nil), k[8:]...)
+		if entry := entries.Get(key); entry != nil {
+			// Only remove the entry if it's the one indexed; the
+			// index should be consistent, but be careful.
+			if _, e, _ := decodeEntry(entry); e.Equal(expiry) {
+				size -= int64(len(key) + len(entry))
+				if err := entries.Delete(key); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if err := index.Delete(); err != nil {
+			return 0, err
+		}
+	}
+	if size < 0 {
+		size = 0
+	}
+	return size, nil
+}
@@ -0,0 +1,134 @@
+package disk
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/fluxcd/flux/pkg/registry/cache"
+)
+
+type testKey string
+
+func (t testKey) Key() string {
+	return string(t)
+}
+
+func newClient(t *testing.T, path string, maxSize int64) *DiskClient {
+	c, err := NewDiskClient(DiskConfig{
+		Path:       path,
+		MaxSize:    maxSize,
+		GCInterval: time.Minute,
+		Logger:     log.NewNopLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestDisk_ExpiryReadWriteRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-registry-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "registry.db")
+
+	c := newClient(t, path, 0)
+	if _, _, err := c.GetKey(testKey("test")); err != cache.ErrNotCached {
+		t.Fatalf("expected ErrNotCached, got %v", err)
+	}
+
+	now := time.Now().Round(time.Second)
+	val := []byte("test bytes")
+	if err := c.SetKey(testKey("test"), now, val); err != nil {
+		t.Fatal(err)
+	}
+	c.Stop()
+
+	// What's stored survives being reopened
+	c = newClient(t, path, 0)
+	defer c.Stop()
+	cached, deadline, err := c.GetKey(testKey("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deadline.Equal(now) {
+		t.Errorf("deadline should be %s, but is %s", now, deadline)
+	}
+	if !bytes.Equal(cached, val) {
+		t.Errorf("should have returned %q, but got %q", val, cached)
+	}
+
+	// An entry past its expiry is a cache miss, and is removed
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		size, err := remove(tx, []byte("test"))
+		if err != nil {
+			return err
+		}
+		key, entry := []byte("test"), encodeEntry(now, now.Add(-time.Second), val)
+		if err := tx.Bucket(entriesBucket).Put(key, entry); err != nil {
+			return err
+		}
+		if err := tx.Bucket(expiryBucket).Put(expiryIndexKey(now.Add(-time.Second), key), []byte{}); err != nil {
+			return err
+		}
+		return putSize(tx, size+int64(len(key)+len(entry)))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetKey(testKey("test")); err != cache.ErrNotCached {
+		t.Errorf("expected ErrNotCached for expired entry, got %v", err)
+	}
+	c.db.View(func(tx *bolt.Tx) error {
+		size, _ := getSize(tx)
+		if size != 0 || tx.Bucket(expiryBucket).Stats().KeyN != 0 {
+			t.Errorf("expected expired entry to be removed, but size is %d", size)
+		}
+		return nil
+	})
+}
+
+func TestDisk_Eviction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-registry-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const maxSize = 10000
+	c := newClient(t, filepath.Join(dir, "registry.db"), maxSize)
+	defer c.Stop()
+
+	now := time.Now()
+	val := bytes.Repeat([]byte("x"), 100)
+	// Each entry is given a later deadline than the last, so the
+	// first ones written are the first to go.
+	for i := 0; i < 200; i++ {
+		if err := c.SetKey(testKey(fmt.Sprintf("key%03d", i)), now.Add(time.Duration(i)*time.Hour), val); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c.db.View(func(tx *bolt.Tx) error {
+		size, _ := getSize(tx)
+		if size > maxSize {
+			t.Errorf("expected size to be at most %d, got %d", maxSize, size)
+		}
+		return nil
+	})
+	if _, _, err := c.GetKey(testKey("key000")); err != cache.ErrNotCached {
+		t.Errorf("expected earliest expiring entry to have been evicted, got %v", err)
+	}
+	if _, _, err := c.GetKey(testKey("key199")); err != nil {
+		t.Errorf("expected latest entry to be present, got %v", err)
+	}
+}
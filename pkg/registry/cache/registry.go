@@ -3,6 +3,7 @@ package cache
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -67,13 +68,68 @@ func (l TimestampLabelWhitelist) apply(r *ImageRepository) {
 		switch {
 		case !i.Labels.Created.IsZero():
 			i.CreatedAt = i.Labels.Created
+			i.CreatedAtSource = image.TimestampLabel
 		case !i.Labels.BuildDate.IsZero():
 			i.CreatedAt = i.Labels.BuildDate
+			i.CreatedAtSource = image.TimestampLabel
 		}
 		r.Images[k] = i
 	}
 }
 
+// TimestampSourceRule says where to get the creation timestamp for
+// images with canonical names matching a glob pattern: from the first
+// of the sources that has one.
+type TimestampSourceRule struct {
+	Pattern string
+	Sources []image.TimestampSource
+}
+
+// ParseTimestampSourceRule parses a rule given as
+// `<glob pattern>=<source>,<source>,...`; e.g.,
+// `ghcr.io/example/*=annotation,label,first-seen`.
+func ParseTimestampSourceRule(s string) (TimestampSourceRule, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return TimestampSourceRule{}, fmt.Errorf("timestamp sources %q not in the form <glob pattern>=<source>,...", s)
+	}
+	rule := TimestampSourceRule{Pattern: parts[0]}
+	for _, name := range strings.Split(parts[1], ",") {
+		source, err := image.ParseTimestampSource(strings.TrimSpace(name))
+		if err != nil {
+			return TimestampSourceRule{}, err
+		}
+		rule.Sources = append(rule.Sources, source)
+	}
+	return rule, nil
+}
+
+// TimestampSources decides the creation timestamp of images using
+// the first rule that matches each image's canonical name. Images
+// matching no rule are left as they are.
+type TimestampSources []TimestampSourceRule
+
+func (rules TimestampSources) apply(r *ImageRepository) {
+	var sources []image.TimestampSource
+	for k, i := range r.Images {
+		// All the images are from the same repository, so the rule
+		// found for one is the rule for all.
+		if sources == nil {
+			name := i.ID.CanonicalName().String()
+			for _, rule := range rules {
+				if glob.Glob(rule.Pattern, name) {
+					sources = rule.Sources
+					break
+				}
+			}
+			if sources == nil {
+				return
+			}
+		}
+		r.Images[k] = i.WithCreatedAtFrom(sources)
+	}
+}
+
 // GetImageRepositoryMetadata returns the metadata from an image
 // repository (e.g,. at "docker.io/fluxcd/flux")
 func (c *Cache) GetImageRepositoryMetadata(id image.Name) (image.RepositoryMetadata, error) {
@@ -72,3 +72,31 @@ func mustMakeInfo(ref string, label time.Time, created time.Time) image.Info {
 	}
 	return image.Info{ID: r, Labels: labels, CreatedAt: created}
 }
+
+func Test_TimestampSourcesDecorator(t *testing.T) {
+	firstSeen := time.Now().Add(-time.Hour).UTC()
+	reproducible := mustMakeInfo("ghcr.io/example/app:v1", time.Time{}, time.Unix(0, 0))
+	reproducible.FirstSeen = firstSeen
+	annotated := mustMakeInfo("ghcr.io/example/app:v2", time.Time{}, time.Unix(0, 0))
+	annotated.Annotations.Created = time.Now().UTC()
+
+	r := mockReader()
+	r.appendImage(reproducible)
+	r.appendImage(annotated)
+
+	rule, err := ParseTimestampSourceRule("ghcr.io/example/*=annotation,config,first-seen")
+	assert.NoError(t, err)
+	c := Cache{r, []Decorator{TimestampSources{rule, {Pattern: "*", Sources: []image.TimestampSource{image.TimestampConfig}}}}}
+
+	rm, err := c.GetImageRepositoryMetadata(image.Name{})
+	assert.NoError(t, err)
+	assert.Equal(t, firstSeen, rm.Images["v1"].CreatedAt)
+	assert.Equal(t, image.TimestampFirstSeen, rm.Images["v1"].CreatedAtSource)
+	assert.Equal(t, annotated.Annotations.Created, rm.Images["v2"].CreatedAt)
+	assert.Equal(t, image.TimestampAnnotation, rm.Images["v2"].CreatedAtSource)
+
+	for _, bad := range []string{"ghcr.io/*", "ghcr.io/*=", "ghcr.io/*=build-date"} {
+		_, err := ParseTimestampSourceRule(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -14,12 +14,14 @@ import (
 
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/registry"
+	"github.com/fluxcd/flux/pkg/registry/middleware"
 )
 
 type imageToUpdate struct {
-	ref             image.Ref
-	previousDigest  string
-	previousRefresh time.Duration
+	ref               image.Ref
+	previousDigest    string
+	previousRefresh   time.Duration
+	previousFirstSeen time.Time
 }
 
 // repoCacheManager handles cache operations for a container image repository
@@ -32,12 +34,16 @@ type repoCacheManager struct {
 	trace         bool
 	logger        log.Logger
 	cacheClient   Client
+
+	// labels to keep when trimming those of images, since they
+	// are used to order images
+	keepLabels []string
 	sync.Mutex
 }
 
 func newRepoCacheManager(now time.Time,
 	repoID image.Name, clientFactory registry.ClientFactory, creds registry.Credentials, repoClientTimeout time.Duration,
-	burst int, trace bool, logger log.Logger, cacheClient Client) (*repoCacheManager, error) {
+	burst int, trace bool, keepLabels []string, logger log.Logger, cacheClient Client) (*repoCacheManager, error) {
 	client, err := clientFactory.ClientFor(repoID.CanonicalName(), creds)
 	if err != nil {
 		return nil, err
@@ -49,6 +55,7 @@ func newRepoCacheManager(now time.Time,
 		clientTimeout: repoClientTimeout,
 		burst:         burst,
 		trace:         trace,
+		keepLabels:    keepLabels,
 		logger:        logger,
 		cacheClient:   cacheClient,
 	}
@@ -146,7 +153,7 @@ func (c *repoCacheManager) fetchImages(tags []string) (fetchImagesResult, error)
 						if !lastFetched.IsZero() {
 							previousRefresh = deadline.Sub(lastFetched)
 						}
-						toUpdate = append(toUpdate, imageToUpdate{ref: newID, previousRefresh: previousRefresh, previousDigest: entry.Info.Digest})
+						toUpdate = append(toUpdate, imageToUpdate{ref: newID, previousRefresh: previousRefresh, previousDigest: entry.Info.Digest, previousFirstSeen: entry.Info.FirstSeen})
 						refresh++
 					}
 				} else {
@@ -200,8 +207,8 @@ updates:
 		awaitFetchers.Add(1)
 		go func() {
 			defer func() { awaitFetchers.Done(); <-fetchers }()
-			ctxcc, cancel := context.WithTimeout(ctxc, c.clientTimeout)
-			defer cancel()
+			ctxcc, cancelcc := context.WithTimeout(ctxc, c.clientTimeout)
+			defer cancelcc()
 			entry, err := c.updateImage(ctxcc, upCopy)
 			if err != nil {
 				if err, ok := errors.Cause(err).(net.Error); (ok && err.Timeout()) || ctxcc.Err() == context.DeadlineExceeded {
@@ -209,6 +216,10 @@ updates:
 					return
 				}
 				switch {
+				case middleware.IsBackingOff(err):
+					// the host has asked us to hold off; the rate
+					// limiter will report when it's done, so just stop
+					cancel()
 				case strings.Contains(err.Error(), "429"):
 					// abort the image tags fetching if we've been rate limited
 					warnAboutRateLimit.Do(func() {
@@ -259,6 +270,7 @@ func (c *repoCacheManager) updateImage(ctx context.Context, update imageToUpdate
 		}
 		c.logger.Log("err", err, "ref", imageID)
 	}
+	entry.Info.Labels = entry.Info.Labels.Trimmed(c.keepLabels...)
 
 	refresh := update.previousRefresh
 	reason := ""
@@ -269,14 +281,19 @@ func (c *repoCacheManager) updateImage(ctx context.Context, update imageToUpdate
 		reason = "image is excluded"
 	case update.previousDigest == "":
 		entry.Info.LastFetched = c.now
+		entry.Info.FirstSeen = c.now
 		refresh = update.previousRefresh
 		reason = "no prior cache entry for image"
-	case entry.Info.Digest == update.previousDigest:
+	case sameImage(entry.Info, update.previousDigest):
 		entry.Info.LastFetched = c.now
+		// Entries cached before first seen was recorded keep a zero
+		// time, so that they don't count as new (see Info.Soaking)
+		entry.Info.FirstSeen = update.previousFirstSeen
 		refresh = clipRefresh(refresh * 2)
 		reason = "image digest is same"
 	default: // i.e., not excluded, but the digests differ -> the tag was moved
 		entry.Info.LastFetched = c.now
+		entry.Info.FirstSeen = c.now
 		refresh = clipRefresh(refresh / 2)
 		reason = "image digest is different"
 	}
@@ -298,6 +315,23 @@ func (c *repoCacheManager) updateImage(ctx context.Context, update imageToUpdate
 	return entry, nil
 }
 
+// sameImage says whether the image fetched for a tag is the one
+// cached for it, given the digest cached. Entries for multi-platform
+// images cached before image index digests were recorded have the
+// digest of the manifest for one platform, which doesn't mean the tag
+// has moved.
+func sameImage(fetched image.Info, previousDigest string) bool {
+	if fetched.Digest == previousDigest {
+		return true
+	}
+	for _, digest := range fetched.Platforms {
+		if digest == previousDigest {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *repoCacheManager) clientTimeoutError() error {
 	return fmt.Errorf("client timeout (%s) exceeded", r.clientTimeout)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/registry"
+	"github.com/fluxcd/flux/pkg/registry/mock"
 )
 
 func Test_ClientTimeouts(t *testing.T) {
@@ -44,6 +46,7 @@ func Test_ClientTimeouts(t *testing.T) {
 		timeout,
 		1,
 		false,
+		nil,
 		logger,
 		nil,
 	)
@@ -52,3 +55,52 @@ func Test_ClientTimeouts(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "client timeout (1ms) exceeded", err.Error())
 }
+
+func Test_UpdateImage_FirstSeen(t *testing.T) {
+	now := time.Date(2020, 10, 11, 9, 41, 0, 0, time.UTC)
+	earlier := now.Add(-24 * time.Hour)
+	index := "sha256:" + strings.Repeat("1", 64)
+	amd64 := "sha256:" + strings.Repeat("2", 64)
+	moved := "sha256:" + strings.Repeat("3", 64)
+	fetched := image.Info{
+		ID:        ref,
+		Digest:    index,
+		Platforms: map[string]string{"linux/amd64": amd64},
+	}
+
+	for _, tt := range []struct {
+		name              string
+		previousDigest    string
+		previousFirstSeen time.Time
+		firstSeen         time.Time
+	}{
+		{name: "no prior entry", firstSeen: now},
+		{name: "same digest", previousDigest: index, previousFirstSeen: earlier, firstSeen: earlier},
+		{name: "cached before first seen was recorded", previousDigest: index},
+		{name: "cached before image index digests were recorded", previousDigest: amd64, previousFirstSeen: earlier, firstSeen: earlier},
+		{name: "tag moved", previousDigest: moved, previousFirstSeen: earlier, firstSeen: now},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rcm := &repoCacheManager{
+				now:           now,
+				repoID:        repo,
+				clientTimeout: time.Minute,
+				logger:        log.NewNopLogger(),
+				cacheClient:   &mem{},
+				client: &mock.Client{
+					ManifestFn: func(string) (registry.ImageEntry, error) {
+						return registry.ImageEntry{Info: fetched}, nil
+					},
+				},
+			}
+			entry, err := rcm.updateImage(context.Background(), imageToUpdate{
+				ref:               ref,
+				previousDigest:    tt.previousDigest,
+				previousRefresh:   initialRefresh,
+				previousFirstSeen: tt.previousFirstSeen,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.firstSeen, entry.Info.FirstSeen)
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/registry"
+	"github.com/fluxcd/flux/pkg/registry/middleware"
 )
 
 const askForNewImagesInterval = time.Minute
@@ -53,6 +54,17 @@ type Warmer struct {
 	Trace         bool
 	Priority      chan image.Name
 	Notify        func()
+	// TagsInUse, if set, is consulted along with the images to fetch,
+	// so that manifests are fetched only for the tags that might be
+	// used.
+	TagsInUse func() registry.ImageTags
+
+	// the result of calling `TagsInUse`, as of the last time around
+	// the loop
+	tagsInUse registry.ImageTags
+	// the last failure to get credentials, per host, so each is
+	// reported once rather than for every image
+	credentialsFailures map[string]string
 }
 
 // NewWarmer creates cache warmer that (when Loop is invoked) will
@@ -82,6 +94,7 @@ func (w *Warmer) Loop(logger log.Logger, stop <-chan struct{}, wg *sync.WaitGrou
 	refresh := time.Tick(askForNewImagesInterval)
 	imageCreds := imagesToFetchFunc()
 	backlog := imageCredsToBacklog(imageCreds)
+	w.refreshTagsInUse()
 
 	// We have some fine control over how long to spend on each fetch
 	// operation, since they are given a `context`. For now though,
@@ -93,8 +106,32 @@ func (w *Warmer) Loop(logger log.Logger, stop <-chan struct{}, wg *sync.WaitGrou
 	// requested the credentials.
 	priorityWarm := func(name image.Name) {
 		logger.Log("priority", name.String())
-		if creds, ok := imageCreds[name]; ok {
+		creds, ok := imageCreds[name]
+		if !ok {
+			// Names from elsewhere (e.g., a registry webhook) may
+			// not be spelt the same as in the cluster; look for
+			// the same repository by its canonical name.
+			for n, c := range imageCreds {
+				if n.CanonicalName() == name.CanonicalName() {
+					name, creds, ok = n, c, true
+					break
+				}
+			}
+		}
+		if ok {
 			w.warm(ctx, time.Now(), logger, name, creds)
+			// Something was pushed; now the cache has it, look for
+			// updates to automated workloads straight away.
+			if w.Notify != nil {
+				w.Notify()
+			}
+			// No need to fetch it again until it comes around next time
+			for i := range backlog {
+				if backlog[i].Name.CanonicalName() == name.CanonicalName() {
+					backlog = append(backlog[:i], backlog[i+1:]...)
+					break
+				}
+			}
 		} else {
 			logger.Log("priority", name.String(), "err", "no creds available")
 		}
@@ -129,6 +166,7 @@ func (w *Warmer) Loop(logger log.Logger, stop <-chan struct{}, wg *sync.WaitGrou
 			case <-refresh:
 				imageCreds = imagesToFetchFunc()
 				backlog = imageCredsToBacklog(imageCreds)
+				w.refreshTagsInUse()
 			case name := <-w.Priority:
 				priorityWarm(name)
 			}
@@ -136,6 +174,28 @@ func (w *Warmer) Loop(logger log.Logger, stop <-chan struct{}, wg *sync.WaitGrou
 	}
 }
 
+func (w *Warmer) refreshTagsInUse() {
+	if w.TagsInUse != nil {
+		w.tagsInUse = w.TagsInUse()
+	}
+}
+
+// selectTags returns the tags for which manifests should be fetched:
+// all of them, unless we know which are in use for the image.
+func (w *Warmer) selectTags(id image.Name, tags []string) []string {
+	selection, ok := w.tagsInUse[id.CanonicalName()]
+	if !ok || selection.All() {
+		return tags
+	}
+	var selected []string
+	for _, tag := range tags {
+		if selection.Matches(tag) {
+			selected = append(selected, tag)
+		}
+	}
+	return selected
+}
+
 func imageCredsToBacklog(imageCreds registry.ImageCreds) []backlogItem {
 	backlog := make([]backlogItem, len(imageCreds))
 	var i int
@@ -146,14 +206,42 @@ func imageCredsToBacklog(imageCreds registry.ImageCreds) []backlogItem {
 	return backlog
 }
 
+// credentialsFailed reports a failure to get credentials for a host,
+// unless it's the same as the last failure for the host.
+func (w *Warmer) credentialsFailed(logger log.Logger, err *registry.CredentialsError) {
+	if w.credentialsFailures == nil {
+		w.credentialsFailures = map[string]string{}
+	}
+	if w.credentialsFailures[err.Host] == err.Error() {
+		return
+	}
+	w.credentialsFailures[err.Host] = err.Error()
+	logger.Log("host", err.Host, "helper", err.Helper, "err", err.Err)
+}
+
+// credentialsSucceeded notes that credentials were available for a
+// host, reporting it if they weren't before.
+func (w *Warmer) credentialsSucceeded(logger log.Logger, host string) {
+	if _, ok := w.credentialsFailures[host]; ok {
+		delete(w.credentialsFailures, host)
+		logger.Log("host", host, "info", "credentials available again")
+	}
+}
+
 func (w *Warmer) warm(ctx context.Context, now time.Time, logger log.Logger, id image.Name, creds registry.Credentials) {
 	errorLogger := log.With(logger, "canonical_name", id.CanonicalName(), "auth", creds)
 
-	cacheManager, err := newRepoCacheManager(now, id, w.clientFactory, creds, time.Minute, w.burst, w.Trace, errorLogger, w.cache)
+	keepLabels := w.tagsInUse[id.CanonicalName()].Labels()
+	cacheManager, err := newRepoCacheManager(now, id, w.clientFactory, creds, time.Minute, w.burst, w.Trace, keepLabels, errorLogger, w.cache)
+	if credsErr, ok := err.(*registry.CredentialsError); ok {
+		w.credentialsFailed(logger, credsErr)
+		return
+	}
 	if err != nil {
 		errorLogger.Log("err", err.Error())
 		return
 	}
+	w.credentialsSucceeded(logger, id.CanonicalName().Domain)
 
 	// This is what we're going to write back to the cache
 	var repo ImageRepository
@@ -176,14 +264,18 @@ func (w *Warmer) warm(ctx context.Context, now time.Time, logger log.Logger, id
 
 	tags, err := cacheManager.getTags(ctx)
 	if err != nil {
-		if !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) && !strings.Contains(err.Error(), "net/http: request canceled") {
+		if !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) && !strings.Contains(err.Error(), "net/http: request canceled") && !middleware.IsBackingOff(err) {
 			errorLogger.Log("err", errors.Wrap(err, "requesting tags"))
 			repo.LastError = err.Error()
 		}
 		return
 	}
 
-	fetchResult, err := cacheManager.fetchImages(tags)
+	// Only the tags in use need their manifests fetched; all tags are
+	// still recorded, so that they can be listed.
+	selectedTags := w.selectTags(id, tags)
+
+	fetchResult, err := cacheManager.fetchImages(selectedTags)
 	if err != nil {
 		logger.Log("err", err, "tags", tags)
 		repo.LastError = err.Error()
@@ -195,7 +287,7 @@ func (w *Warmer) warm(ctx context.Context, now time.Time, logger log.Logger, id
 	var manifestUnknownCount int
 
 	if len(fetchResult.imagesToUpdate) > 0 {
-		logger.Log("info", "refreshing image", "image", id, "tag_count", len(tags),
+		logger.Log("info", "refreshing image", "image", id, "tag_count", len(tags), "selected_count", len(selectedTags),
 			"to_update", len(fetchResult.imagesToUpdate),
 			"of_which_refresh", fetchResult.imagesToUpdateRefreshCount, "of_which_missing", fetchResult.imagesToUpdateMissingCount)
 		var images map[string]image.Info
@@ -228,15 +320,15 @@ func (w *Warmer) warm(ctx context.Context, now time.Time, logger log.Logger, id
 			cacheTags[t] = struct{}{}
 		}
 
-		// If there's more tags than there used to be, there must be
-		// at least one new tag.
-		if len(cacheTags) < len(tags) {
+		// If there's more tags (of those in use) than there used to
+		// be, there must be at least one new tag.
+		if len(cacheTags) < len(selectedTags) {
 			w.Notify()
 			return
 		}
 		// Otherwise, check whether there are any entries in the
 		// fetched tags that aren't in the cached tags.
-		tagSet := NewStringSet(tags)
+		tagSet := NewStringSet(selectedTags)
 		if !tagSet.Subset(cacheTags) {
 			w.Notify()
 		}
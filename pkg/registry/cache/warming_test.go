@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry"
 	"github.com/fluxcd/flux/pkg/registry/mock"
 )
@@ -181,3 +183,71 @@ func setup(t *testing.T, digest *string) (*Warmer, Client) {
 	warmer := &Warmer{clientFactory: factory, cache: c, burst: 10}
 	return warmer, c
 }
+
+func TestWarmOnlyTagsInUse(t *testing.T) {
+	var fetched []string
+	client := &mock.Client{
+		TagsFn: func() ([]string, error) {
+			return []string{"1.0.0", "1.1.0", "2.0.0", "ci-abc123", "ci-def456"}, nil
+		},
+		ManifestFn: func(tag string) (registry.ImageEntry, error) {
+			fetched = append(fetched, tag)
+			return registry.ImageEntry{Info: image.Info{ID: repo.ToRef(tag), Digest: "abc"}}, nil
+		},
+	}
+	factory := &mock.ClientFactory{Client: client}
+	cache := &mem{}
+	warmer := &Warmer{clientFactory: factory, cache: cache, burst: 1}
+	logger := log.NewNopLogger()
+
+	selection := registry.TagSelection{}
+	selection.Add(policy.NewPattern("semver:^1.0"), "ci-abc123")
+	warmer.tagsInUse = registry.ImageTags{repo.CanonicalName(): selection}
+	warmer.warm(context.TODO(), time.Now(), logger, repo, registry.NoCredentials())
+
+	assert.ElementsMatch(t, []string{"1.0.0", "1.1.0", "ci-abc123"}, fetched)
+	repoInfo, err := (&Cache{Reader: cache}).GetImageRepositoryMetadata(repo)
+	assert.NoError(t, err)
+	// All the tags are still listed
+	assert.Len(t, repoInfo.Tags, 5)
+	assert.Len(t, repoInfo.Images, 3)
+
+	// If anything uses all the tags, all manifests are fetched
+	fetched = nil
+	selection.Add(policy.PatternAll, "")
+	warmer.tagsInUse = registry.ImageTags{repo.CanonicalName(): selection}
+	warmer.warm(context.TODO(), time.Now(), logger, repo, registry.NoCredentials())
+	assert.ElementsMatch(t, []string{"2.0.0", "ci-def456"}, fetched)
+}
+
+func TestWarmKeepsOrderingLabels(t *testing.T) {
+	labels := map[string]string{"zz.build.number": "42"}
+	for i := 0; i < 20; i++ {
+		labels[fmt.Sprintf("label.%02d", i)] = "x"
+	}
+	client := &mock.Client{
+		TagsFn: func() ([]string, error) {
+			return []string{"main-abc"}, nil
+		},
+		ManifestFn: func(tag string) (registry.ImageEntry, error) {
+			info := image.Info{ID: repo.ToRef(tag), Digest: "abc", Labels: image.Labels{Other: labels}}
+			return registry.ImageEntry{Info: info}, nil
+		},
+	}
+	factory := &mock.ClientFactory{Client: client}
+	cache := &mem{}
+	warmer := &Warmer{clientFactory: factory, cache: cache, burst: 1}
+
+	selection := registry.TagSelection{}
+	selection.Add(policy.NewPattern("label:zz.build.number"), "main-abc")
+	warmer.tagsInUse = registry.ImageTags{repo.CanonicalName(): selection}
+	warmer.warm(context.TODO(), time.Now(), log.NewNopLogger(), repo, registry.NoCredentials())
+
+	repoInfo, err := (&Cache{Reader: cache}).GetImageRepositoryMetadata(repo)
+	assert.NoError(t, err)
+	info := repoInfo.Images["main-abc"]
+	assert.NotEmpty(t, info.Labels.Other)
+	v, ok := info.Labels.Get("zz.build.number")
+	assert.True(t, ok)
+	assert.Equal(t, "42", v)
+}
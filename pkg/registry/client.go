@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"time"
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/distribution/registry/client"
@@ -76,6 +78,12 @@ type Remote struct {
 	transport http.RoundTripper
 	repo      image.CanonicalName
 	base      string
+	// platform is that of the image to use from an image index;
+	// if not set, it's image.DefaultPlatform
+	platform image.Platform
+	// path is the repository path to use in requests, if it's not
+	// that of the repo (e.g., for a mirror)
+	path string
 }
 
 // Adapt to docker distribution `reference.Named`.
@@ -91,9 +99,17 @@ func (n named) Name() string {
 	return n.Image
 }
 
+func (a *Remote) named() named {
+	n := named{a.repo}
+	if a.path != "" {
+		n.Image = a.path
+	}
+	return n
+}
+
 // Return the tags for this repository.
 func (a *Remote) Tags(ctx context.Context) ([]string, error) {
-	repository, err := client.NewRepository(named{a.repo}, a.base, a.transport)
+	repository, err := client.NewRepository(a.named(), a.base, a.transport)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +119,7 @@ func (a *Remote) Tags(ctx context.Context) ([]string, error) {
 // Manifest fetches the metadata for an image reference; currently
 // assumed to be in the same repo as that provided to `NewRemote(...)`
 func (a *Remote) Manifest(ctx context.Context, ref string) (ImageEntry, error) {
-	repository, err := client.NewRepository(named{a.repo}, a.base, a.transport)
+	repository, err := client.NewRepository(a.named(), a.base, a.transport)
 	if err != nil {
 		return ImageEntry{}, err
 	}
@@ -114,6 +130,12 @@ func (a *Remote) Manifest(ctx context.Context, ref string) (ImageEntry, error) {
 	var manifestDigest digest.Digest
 	digestOpt := client.ReturnContentDigest(&manifestDigest)
 	manifest, fetchErr := manifests.Get(ctx, digest.Digest(ref), digestOpt, distribution.WithTagOption{ref})
+	// if the tag is for an image index (or manifest list), these
+	// record its digest, the platforms it has, and its annotations
+	// (and those of the manifest used from it)
+	var indexDigest digest.Digest
+	var platforms map[string]string
+	var indexAnnotations []map[string]string
 
 interpret:
 	if fetchErr != nil {
@@ -121,7 +143,10 @@ interpret:
 	}
 
 	var labelErr error
-	info := image.Info{ID: a.repo.ToRef(ref), Digest: manifestDigest.String()}
+	info := image.Info{ID: a.repo.ToRef(ref), Digest: manifestDigest.String(), Platforms: platforms}
+	if indexDigest != "" {
+		info.Digest = indexDigest.String()
+	}
 
 	// TODO(michael): can we type switch? Not sure how dependable the
 	// underlying types are.
@@ -163,56 +188,143 @@ interpret:
 		info.Labels = config.Config.Labels
 	case *schema2.DeserializedManifest:
 		var man schema2.Manifest = deserialised.Manifest
-		configBytes, err := repository.Blobs(ctx).Get(ctx, man.Config.Digest)
-		if err != nil {
+		if labelErr, err = readConfig(ctx, repository, man.Config.Digest, &info); err != nil {
 			return ImageEntry{}, err
 		}
-
-		// Ref: https://github.com/docker/distribution/blob/master/docs/spec/manifest-v2-2.md
-		var config struct {
-			Arch            string    `json:"architecture"`
-			Created         time.Time `json:"created"`
-			OS              string    `json:"os"`
+	case *ocischema.DeserializedManifest:
+		var man ocischema.Manifest = deserialised.Manifest
+		if labelErr, err = readConfig(ctx, repository, man.Config.Digest, &info); err != nil {
+			return ImageEntry{}, err
 		}
-		if err = json.Unmarshal(configBytes, &config); err != nil {
-			return ImageEntry{}, nil
+		// OCI manifests can have annotations, which use the same
+		// keys as labels
+		info.Annotations.Created = createdAnnotation(man.Annotations)
+	case *manifestlist.DeserializedManifestList:
+		var list manifestlist.ManifestList = deserialised.ManifestList
+		// The annotations of an OCI image index aren't decoded
+		var index struct {
+			Annotations map[string]string `json:"annotations"`
 		}
-
-		// Ref: https://github.com/moby/moby/blob/39e6def2194045cb206160b66bf309f486bd7e64/image/image.go#L47
-		var container struct {
-			ContainerConfig struct {
-				Labels image.Labels `json:"labels"`
-			} `json:"container_config"`
+		if _, payload, err := deserialised.Payload(); err == nil {
+			json.Unmarshal(payload, &index)
 		}
-		// We need to unmarshal the labels separately as the validation error
-		// that may be returned stops the unmarshalling which would result
-		// in no data at all for the image.
-		if err = json.Unmarshal(configBytes, &container); err != nil {
-			if _, ok := err.(*image.LabelTimestampFormatError); !ok {
-				return ImageEntry{}, err
-			}
-			labelErr = err
+		platform := a.platform
+		if platform == (image.Platform{}) {
+			platform = image.DefaultPlatform
 		}
-
-		// This _is_ what Docker uses as its Image ID.
-		info.ImageID = man.Config.Digest.String()
-		info.CreatedAt = config.Created
-		info.Labels = container.ContainerConfig.Labels
-	case *manifestlist.DeserializedManifestList:
-		var list manifestlist.ManifestList = deserialised.ManifestList
-		// TODO(michael): is it valid to just pick the first one that matches?
+		platforms = map[string]string{}
+		var selected digest.Digest
 		for _, m := range list.Manifests {
-			if m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
-				manifest, fetchErr = manifests.Get(ctx, m.Digest, digestOpt)
-				goto interpret
+			p := image.Platform{OS: m.Platform.OS, Architecture: m.Platform.Architecture, Variant: m.Platform.Variant}
+			// Indexes can include things other than images, e.g.,
+			// attestations, which have no platform
+			if p.OS == "" || p.OS == "unknown" {
+				continue
+			}
+			if _, ok := platforms[p.String()]; !ok {
+				platforms[p.String()] = m.Digest.String()
 			}
+			// The first manifest for the platform is the one used
+			if selected == "" && platform.Matches(p) {
+				selected = m.Digest
+				indexAnnotations = []map[string]string{m.Annotations, index.Annotations}
+			}
+		}
+		if selected != "" {
+			indexDigest = manifestDigest
+			manifest, fetchErr = manifests.Get(ctx, selected, digestOpt)
+			goto interpret
 		}
 		entry := ImageEntry{}
-		entry.ExcludedReason = "no suitable manifest (linux amd64) in manifestlist"
+		entry.ExcludedReason = fmt.Sprintf("no suitable manifest (%s) in manifestlist", platform)
 		return entry, nil
 	default:
 		t := reflect.TypeOf(manifest)
 		return ImageEntry{}, errors.New("unknown manifest type: " + t.String())
 	}
+	// Images can be annotated in the index rather than the manifest
+	for _, annotations := range indexAnnotations {
+		if !info.Annotations.Created.IsZero() {
+			break
+		}
+		info.Annotations.Created = createdAnnotation(annotations)
+	}
 	return ImageEntry{Info: info}, labelErr
 }
+
+// createdAnnotation returns the time given by the
+// `org.opencontainers.image.created` annotation, if there is one that
+// can be parsed, or else the zero time.
+func createdAnnotation(annotations map[string]string) time.Time {
+	if created, ok := annotations["org.opencontainers.image.created"]; ok {
+		if t, err := time.Parse(time.RFC3339, created); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// readConfig fills in the info from the image config, for manifests
+// that refer to one (i.e., schema2 and OCI manifests).
+func readConfig(ctx context.Context, repository distribution.Repository, configDigest digest.Digest, info *image.Info) (labelErr error, err error) {
+	configBytes, err := repository.Blobs(ctx).Get(ctx, configDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ref: https://github.com/docker/distribution/blob/master/docs/spec/manifest-v2-2.md
+	var config struct {
+		Arch    string    `json:"architecture"`
+		Created time.Time `json:"created"`
+		OS      string    `json:"os"`
+		Variant string    `json:"variant"`
+	}
+	if err = json.Unmarshal(configBytes, &config); err != nil {
+		// an unreadable config gets an empty result, rather than an error
+		*info = image.Info{}
+		return nil, nil
+	}
+
+	// Ref: https://github.com/moby/moby/blob/39e6def2194045cb206160b66bf309f486bd7e64/image/image.go#L47
+	var container struct {
+		ContainerConfig struct {
+			Labels image.Labels `json:"labels"`
+		} `json:"container_config"`
+	}
+	// We need to unmarshal the labels separately as the validation error
+	// that may be returned stops the unmarshalling which would result
+	// in no data at all for the image.
+	if err = json.Unmarshal(configBytes, &container); err != nil {
+		if _, ok := err.(*image.LabelTimestampFormatError); !ok {
+			return nil, err
+		}
+		labelErr = err
+	}
+	// Images built with BuildKit have no container_config, so labels
+	// are also taken from the config proper, where not given above.
+	var imageConfig struct {
+		Config struct {
+			Labels image.Labels `json:"labels"`
+		} `json:"config"`
+	}
+	if err = json.Unmarshal(configBytes, &imageConfig); err != nil {
+		if _, ok := err.(*image.LabelTimestampFormatError); !ok {
+			return nil, err
+		}
+		if labelErr == nil {
+			labelErr = err
+		}
+	}
+
+	// An image that's not in an index is for the one platform
+	if info.Platforms == nil && config.OS != "" && config.Arch != "" {
+		platform := image.Platform{OS: config.OS, Architecture: config.Arch, Variant: config.Variant}
+		info.Platforms = map[string]string{platform.String(): info.Digest}
+	}
+
+	// This _is_ what Docker uses as its Image ID.
+	info.ImageID = configDigest.String()
+	info.CreatedAt = config.Created
+	info.Labels = container.ContainerConfig.Labels.WithDefaults(imageConfig.Config.Labels)
+	return labelErr, nil
+}
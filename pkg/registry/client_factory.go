@@ -26,9 +26,28 @@ type RemoteClientFactory struct {
 	// hosts with which to tolerate insecure connections (e.g., with
 	// TLS_INSECURE_SKIP_VERIFY, or as a fallback, using HTTP).
 	InsecureHosts []string
+	// TLS configuration (CA certificates and client certificates)
+	// for particular hosts
+	TLS *HostTLS
+
+	// registries to use in place of others, e.g., pull-through
+	// caches; and, whether to use the original registry if the
+	// mirrors fail
+	Mirrors        Mirrors
+	MirrorFallback bool
+
+	// programs to run to get credentials for hosts that don't have
+	// them otherwise
+	CredentialPlugins []CredentialPlugin
+
+	// the platform of the image to use, when a tag is for an image
+	// index (a.k.a. manifest list); if not set, it's
+	// image.DefaultPlatform
+	Platform image.Platform
 
 	mu               sync.Mutex
 	challengeManager challenge.Manager
+	helpers          credentialHelpers
 }
 
 type logging struct {
@@ -93,9 +112,57 @@ attemptChallenge:
 }
 
 func (f *RemoteClientFactory) ClientFor(repo image.CanonicalName, creds Credentials) (Client, error) {
-	repoHosts := []string{repo.Domain}
+	mirrors := f.Mirrors[repo.Domain]
+	if len(mirrors) == 0 {
+		return f.clientFor(repo, repo.Domain, repo.Image, creds)
+	}
+
+	// Use the mirrors, and the upstream registry if allowed; in any
+	// case the client is for the canonical repo, so that's how
+	// images are identified.
+	var endpoints []mirrorEndpoint
+	for _, m := range mirrors {
+		m := m
+		path := repo.Image
+		if m.PathPrefix != "" {
+			path = m.PathPrefix + "/" + repo.Image
+		}
+		endpoints = append(endpoints, mirrorEndpoint{
+			name: m.String(),
+			newClient: func() (Client, error) {
+				return f.clientFor(repo, m.Host, path, creds)
+			},
+		})
+	}
+	if f.MirrorFallback {
+		endpoints = append(endpoints, mirrorEndpoint{
+			name: repo.Domain,
+			newClient: func() (Client, error) {
+				return f.clientFor(repo, repo.Domain, repo.Image, creds)
+			},
+		})
+	}
+	logger := f.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	client := &mirroredClient{repo: repo, endpoints: endpoints, logger: logger}
+	// With nothing to fall back to, problems creating the client
+	// (e.g., getting credentials) may as well be reported now.
+	if len(endpoints) == 1 {
+		if _, err := client.client(0); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// clientFor makes a client for the repo, using the registry at the
+// host given, and the repository path given.
+func (f *RemoteClientFactory) clientFor(repo image.CanonicalName, host, path string, creds Credentials) (Client, error) {
+	repoHosts := []string{host}
 	// allow the insecure hosts list to contain hosts with or without the port
-	repoHostWithoutPort, _, err := net.SplitHostPort(repo.Domain)
+	repoHostWithoutPort, _, err := net.SplitHostPort(host)
 	if err == nil {
 		// parsing fails if no port is present
 		repoHosts = append(repoHosts, repoHostWithoutPort)
@@ -111,9 +178,17 @@ insecureCheckLoop:
 		}
 	}
 
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: insecure,
+	tlsConfig := &tls.Config{}
+	if f.TLS != nil {
+		hostConfig, err := f.TLS.ConfigFor(host)
+		if err != nil {
+			return nil, err
+		}
+		if hostConfig != nil {
+			tlsConfig = hostConfig.Clone()
+		}
 	}
+	tlsConfig.InsecureSkipVerify = insecure
 	// Since we construct one of these per scan, be fairly ruthless
 	// about throttling the number, and closing of, idle connections.
 	var tx http.RoundTripper = &http.Transport{
@@ -123,7 +198,7 @@ insecureCheckLoop:
 		Proxy:           http.ProxyFromEnvironment,
 	}
 	if f.Limiters != nil {
-		tx = f.Limiters.RoundTripper(tx, repo.Domain)
+		tx = f.Limiters.RoundTripper(tx, host)
 	}
 	if f.Trace {
 		tx = &logging{f.Logger, tx}
@@ -136,25 +211,31 @@ insecureCheckLoop:
 	manager := f.challengeManager
 	f.mu.Unlock()
 
-	registryURL, err := f.doChallenge(manager, tx, repo.Domain, insecure)
+	registryURL, err := f.doChallenge(manager, tx, host, insecure)
 	if err != nil {
 		return nil, err
 	}
 
-	cred := creds.credsFor(repo.Domain)
+	cred, err := f.credsFor(host, creds)
+	if err != nil {
+		return nil, err
+	}
 	if f.Trace {
-		f.Logger.Log("repo", repo.String(), "auth", cred.String(), "api", registryURL.String())
+		f.Logger.Log("repo", repo.String(), "host", host, "auth", cred.String(), "api", registryURL.String())
 	}
 
 	authHandlers := []auth.AuthenticationHandler{
-		auth.NewTokenHandler(tx, &store{cred}, repo.Image, "pull"),
+		auth.NewTokenHandler(tx, &store{cred}, path, "pull"),
 		auth.NewBasicHandler(&store{cred}),
 	}
 	tx = transport.NewTransport(tx, auth.NewAuthorizer(manager, authHandlers...))
 
 	// For the API base we want only the scheme and host.
 	registryURL.Path = ""
-	client := &Remote{transport: tx, repo: repo, base: registryURL.String()}
+	client := &Remote{transport: tx, repo: repo, base: registryURL.String(), platform: f.Platform}
+	if path != repo.Image {
+		client.path = path
+	}
 	return NewInstrumentedClient(client), nil
 }
 
@@ -164,7 +245,36 @@ insecureCheckLoop:
 func (f *RemoteClientFactory) Succeed(repo image.CanonicalName) {
 	if f.Limiters != nil {
 		f.Limiters.Recover(repo.Domain)
+		for _, m := range f.Mirrors[repo.Domain] {
+			f.Limiters.Recover(m.Host)
+		}
+	}
+}
+
+// credsFor finds credentials for the host: those given explicitly,
+// then those from any credential helper, then from any credential
+// plugin for the host, and lastly from the cloud provider. A helper
+// or plugin that has no credentials for the host doesn't stop the
+// search.
+func (f *RemoteClientFactory) credsFor(host string, cs Credentials) (creds, error) {
+	if cred, ok := cs.m[host]; ok {
+		return cred, nil
+	}
+	if helper := cs.helperFor(host); helper != "" {
+		cred, err := f.helpers.get(credentialHelperPrefix+helper, host, time.Now())
+		if err != nil || cred != (creds{}) {
+			return cred, err
+		}
+	}
+	for _, plugin := range f.CredentialPlugins {
+		if plugin.Matches(host) {
+			cred, err := f.helpers.get(plugin.Command, host, time.Now())
+			if err != nil || cred != (creds{}) {
+				return cred, err
+			}
+		}
 	}
+	return cs.credsFor(host), nil
 }
 
 // store adapts a set of pre-selected creds to be an
@@ -178,7 +288,7 @@ func (s *store) Basic(url *url.URL) (string, string) {
 }
 
 func (s *store) RefreshToken(*url.URL, string) string {
-	return ""
+	return s.auth.identityToken
 }
 
 func (s *store) SetRefreshToken(*url.URL, string, string) {
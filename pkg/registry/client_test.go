@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/image"
+)
+
+func sha256Digest(b string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(b)))
+}
+
+func TestRemote_ManifestList(t *testing.T) {
+	const (
+		listType     = "application/vnd.docker.distribution.manifest.list.v2+json"
+		manifestType = "application/vnd.docker.distribution.manifest.v2+json"
+		configType   = "application/vnd.docker.container.image.v1+json"
+	)
+	config := map[string]string{
+		"amd64": `{"architecture":"amd64","os":"linux","created":"2020-01-01T00:00:00Z"}`,
+		"arm64": `{"architecture":"arm64","os":"linux","variant":"v8","created":"2020-01-02T00:00:00Z"}`,
+	}
+	manifest := map[string]string{}
+	for arch, c := range config {
+		manifest[arch] = fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"config":{"mediaType":%q,"size":%d,"digest":%q},"layers":[]}`,
+			manifestType, configType, len(c), sha256Digest(c))
+	}
+	list := fmt.Sprintf(`{"schemaVersion":2,"mediaType":%q,"manifests":[
+{"mediaType":%q,"size":%d,"digest":%q,"platform":{"architecture":"amd64","os":"linux"}},
+{"mediaType":%q,"size":%d,"digest":%q,"platform":{"architecture":"arm64","os":"linux","variant":"v8"},"annotations":{"org.opencontainers.image.created":"2021-03-05T00:00:00Z"}},
+{"mediaType":%q,"size":0,"digest":"sha256:0000000000000000000000000000000000000000000000000000000000000000","platform":{"architecture":"unknown","os":"unknown"}}],
+"annotations":{"org.opencontainers.image.created":"2021-03-04T00:00:00Z"}}`,
+		listType,
+		manifestType, len(manifest["amd64"]), sha256Digest(manifest["amd64"]),
+		manifestType, len(manifest["arm64"]), sha256Digest(manifest["arm64"]),
+		manifestType)
+
+	content := map[string]struct{ mediaType, body string }{
+		"/v2/example/app/manifests/v1": {listType, list},
+	}
+	for arch := range config {
+		content["/v2/example/app/manifests/"+sha256Digest(manifest[arch])] = struct{ mediaType, body string }{manifestType, manifest[arch]}
+		content["/v2/example/app/blobs/"+sha256Digest(config[arch])] = struct{ mediaType, body string }{configType, config[arch]}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		c, ok := content[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", c.mediaType)
+		w.Header().Set("Docker-Content-Digest", sha256Digest(c.body))
+		w.Header().Set("Content-Length", fmt.Sprint(len(c.body)))
+		if r.Method != http.MethodHead {
+			fmt.Fprint(w, c.body)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	repo := image.Name{Domain: u.Host, Image: "example/app"}.CanonicalName()
+
+	for _, c := range []struct {
+		platform image.Platform
+		arch     string
+		created  time.Time
+	}{
+		// annotated in the index
+		{image.Platform{}, "amd64", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)},
+		// annotated in the index's entry for the manifest
+		{image.Platform{OS: "linux", Architecture: "arm64"}, "arm64", time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)},
+	} {
+		f := &RemoteClientFactory{
+			Logger:        log.NewNopLogger(),
+			InsecureHosts: []string{u.Host},
+			Platform:      c.platform,
+		}
+		client, err := f.ClientFor(repo, NoCredentials())
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := client.Manifest(context.Background(), "v1")
+		assert.NoError(t, err)
+		assert.Empty(t, entry.ExcludedReason)
+		assert.Equal(t, sha256Digest(list), entry.Digest)
+		assert.Equal(t, sha256Digest(config[c.arch]), entry.ImageID)
+		assert.True(t, c.created.Equal(entry.Annotations.Created), "expected created %s, got %s", c.created, entry.Annotations.Created)
+		assert.Equal(t, map[string]string{
+			"linux/amd64":    sha256Digest(manifest["amd64"]),
+			"linux/arm64/v8": sha256Digest(manifest["arm64"]),
+		}, entry.Platforms)
+	}
+
+	f := &RemoteClientFactory{
+		Logger:        log.NewNopLogger(),
+		InsecureHosts: []string{u.Host},
+		Platform:      image.Platform{OS: "linux", Architecture: "s390x"},
+	}
+	client, err := f.ClientFor(repo, NoCredentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := client.Manifest(context.Background(), "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "no suitable manifest (linux/s390x) in manifestlist", entry.ExcludedReason)
+}
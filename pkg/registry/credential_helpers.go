@@ -0,0 +1,218 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Credential helpers named in a docker config are programs with
+	// this prefix, e.g., `docker-credential-ecr-login`.
+	credentialHelperPrefix = "docker-credential-"
+	// How long to let a credential helper run.
+	credentialHelperTimeout = 30 * time.Second
+	// How long to keep credentials, if the helper doesn't say.
+	credentialHelperTTL = 10 * time.Minute
+	// How long to wait before asking again, after a helper fails.
+	credentialHelperFailureTTL = time.Minute
+	// Get new credentials this long before the old ones expire.
+	credentialHelperExpiryMargin = time.Minute
+	// What a helper answers with when it doesn't have credentials
+	// for a host.
+	credentialsNotFound = "credentials not found"
+	// Helpers give this as the username when the secret is an
+	// identity token, rather than a password.
+	identityTokenUsername = "<token>"
+
+	dockerHubHost      = "index.docker.io"
+	dockerHubServerURL = "https://index.docker.io/v1/"
+)
+
+// CredentialPlugin is a program to run to get credentials for
+// registry hosts matching a pattern (e.g., `*.registry.example.com`).
+//
+// It's run the same way as a docker credential helper: as `<command>
+// get`, given the registry host on stdin, and expected to print
+// `{"Username": ..., "Secret": ...}`. It may also give
+// `"ExpiresAt"`, as an RFC3339 time, to say how long the credentials
+// can be used for; otherwise, they are used for ten minutes before
+// asking again.
+type CredentialPlugin struct {
+	HostPattern string
+	Command     string
+}
+
+// ParseCredentialPlugin parses a plugin given as
+// `<host pattern>=<command>`.
+func ParseCredentialPlugin(s string) (CredentialPlugin, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return CredentialPlugin{}, fmt.Errorf("credential plugin %q is not in the form <host pattern>=<command>", s)
+	}
+	if _, err := path.Match(parts[0], ""); err != nil {
+		return CredentialPlugin{}, errors.Wrapf(err, "host pattern %q", parts[0])
+	}
+	return CredentialPlugin{HostPattern: parts[0], Command: parts[1]}, nil
+}
+
+// Matches says whether the plugin is for the given host.
+func (p CredentialPlugin) Matches(host string) bool {
+	ok, _ := path.Match(p.HostPattern, host)
+	return ok
+}
+
+// CredentialsError is returned when a credential helper or plugin
+// could not give credentials for a host.
+type CredentialsError struct {
+	Host   string
+	Helper string
+	Err    error
+}
+
+func (err *CredentialsError) Error() string {
+	return fmt.Sprintf("getting credentials for %s from %s: %s", err.Host, err.Helper, err.Err)
+}
+
+// credentialHelpers runs credential helpers and plugins, and keeps
+// what they give until it expires. Only one helper is run at a time
+// for any host, but helpers for different hosts run independently.
+type credentialHelpers struct {
+	mu    sync.Mutex
+	cache map[helperKey]helperResult
+	// held while getting credentials for a host, so that a slow
+	// helper only holds up requests for that host
+	hostLocks map[helperKey]*sync.Mutex
+	// run runs a command with the given input, returning what it
+	// prints; it's a field so that it can be replaced in tests.
+	run func(ctx context.Context, command string, input string) ([]byte, error)
+}
+
+type helperKey struct {
+	command, host string
+}
+
+type helperResult struct {
+	creds  creds
+	err    error
+	expiry time.Time
+}
+
+type helperResponse struct {
+	Username  string
+	Secret    string
+	ExpiresAt *time.Time `json:",omitempty"`
+}
+
+// get returns credentials for the host from the given command,
+// running it if there is nothing cached.
+func (h *credentialHelpers) get(command, host string, now time.Time) (creds, error) {
+	key := helperKey{command, host}
+	hostLock := h.lockFor(key)
+	hostLock.Lock()
+	defer hostLock.Unlock()
+
+	h.mu.Lock()
+	result, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && now.Before(result.expiry) {
+		return result.creds, result.err
+	}
+
+	result = h.fetch(command, host, now)
+	h.mu.Lock()
+	h.cache[key] = result
+	h.mu.Unlock()
+	return result.creds, result.err
+}
+
+func (h *credentialHelpers) lockFor(key helperKey) *sync.Mutex {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.cache == nil {
+		h.cache = map[helperKey]helperResult{}
+		h.hostLocks = map[helperKey]*sync.Mutex{}
+	}
+	l, ok := h.hostLocks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		h.hostLocks[key] = l
+	}
+	return l
+}
+
+func (h *credentialHelpers) fetch(command, host string, now time.Time) helperResult {
+	run := h.run
+	if run == nil {
+		run = runHelper
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	out, err := run(ctx, command, helperServerURL(host))
+	if err != nil {
+		if strings.Contains(string(out), credentialsNotFound) {
+			return helperResult{expiry: now.Add(credentialHelperTTL)}
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = errors.Wrap(err, msg)
+		}
+		return helperResult{
+			err:    &CredentialsError{Host: host, Helper: command, Err: err},
+			expiry: now.Add(credentialHelperFailureTTL),
+		}
+	}
+
+	var resp helperResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return helperResult{
+			err:    &CredentialsError{Host: host, Helper: command, Err: errors.Wrap(err, "parsing output")},
+			expiry: now.Add(credentialHelperFailureTTL),
+		}
+	}
+
+	cred := creds{
+		registry:   host,
+		provenance: command,
+	}
+	if resp.Username == identityTokenUsername {
+		cred.identityToken = resp.Secret
+	} else {
+		cred.username, cred.password = resp.Username, resp.Secret
+	}
+	expiry := now.Add(credentialHelperTTL)
+	if resp.ExpiresAt != nil {
+		expiry = resp.ExpiresAt.Add(-credentialHelperExpiryMargin)
+	}
+	return helperResult{creds: cred, expiry: expiry}
+}
+
+// helperServerURL gives the server URL helpers expect for a host;
+// for Docker Hub, that's the URL docker itself uses.
+func helperServerURL(host string) string {
+	if host == dockerHubHost {
+		return dockerHubServerURL
+	}
+	return host
+}
+
+func runHelper(ctx context.Context, command, input string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, command, "get")
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		// Helpers report problems on stdout, or stderr; either is
+		// useful for explaining the error.
+		return append(stdout.Bytes(), stderr.Bytes()...), err
+	}
+	return stdout.Bytes(), nil
+}
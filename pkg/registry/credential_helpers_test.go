@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCredentialPlugin(t *testing.T) {
+	p, err := ParseCredentialPlugin("*.registry.example.com=/usr/local/bin/example-creds")
+	assert.NoError(t, err)
+	assert.True(t, p.Matches("eu.registry.example.com"))
+	assert.False(t, p.Matches("registry.example.com"))
+	assert.Equal(t, "/usr/local/bin/example-creds", p.Command)
+
+	for _, bad := range []string{"", "registry.example.com", "=/bin/creds", "[=/bin/creds"} {
+		_, err := ParseCredentialPlugin(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestCredentialHelpers(t *testing.T) {
+	now := time.Now()
+	var runs []string
+	answers := map[string]func() ([]byte, error){
+		"https://index.docker.io/v1/": func() ([]byte, error) {
+			return []byte(`{"ServerURL":"https://index.docker.io/v1/","Username":"user","Secret":"pass"}`), nil
+		},
+		"quay.io": func() ([]byte, error) {
+			return []byte("credentials not found in native keychain\n"), errors.New("exit status 1")
+		},
+		"broken.example.com": func() ([]byte, error) {
+			return []byte("no network\n"), errors.New("exit status 1")
+		},
+		"expiring.example.com": func() ([]byte, error) {
+			return []byte(fmt.Sprintf(`{"Username":"<token>","Secret":"tok","ExpiresAt":%q}`, now.Add(5*time.Minute).Format(time.RFC3339))), nil
+		},
+	}
+	h := &credentialHelpers{
+		run: func(_ context.Context, command, input string) ([]byte, error) {
+			runs = append(runs, input)
+			return answers[input]()
+		},
+	}
+
+	cred, err := h.get("docker-credential-pass", "index.docker.io", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", cred.username)
+	assert.Equal(t, "pass", cred.password)
+	// cached
+	_, _ = h.get("docker-credential-pass", "index.docker.io", now.Add(time.Minute))
+	assert.Len(t, runs, 1)
+	// .. until it expires
+	_, _ = h.get("docker-credential-pass", "index.docker.io", now.Add(credentialHelperTTL))
+	assert.Len(t, runs, 2)
+
+	cred, err = h.get("docker-credential-pass", "quay.io", now)
+	assert.NoError(t, err)
+	assert.Equal(t, creds{}, cred)
+
+	_, err = h.get("docker-credential-pass", "broken.example.com", now)
+	credsErr, ok := err.(*CredentialsError)
+	if assert.True(t, ok, "expected CredentialsError, got %v", err) {
+		assert.Equal(t, "broken.example.com", credsErr.Host)
+		assert.Contains(t, credsErr.Error(), "no network")
+	}
+
+	cred, err = h.get("example-creds", "expiring.example.com", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "tok", cred.identityToken)
+	assert.Equal(t, "", cred.username)
+	runs = nil
+	_, _ = h.get("example-creds", "expiring.example.com", now.Add(4*time.Minute))
+	assert.Len(t, runs, 1, "credentials should be refreshed before they expire")
+}
+
+func TestCredentialHelpers_SlowHost(t *testing.T) {
+	slow := make(chan struct{})
+	h := &credentialHelpers{
+		run: func(_ context.Context, command, input string) ([]byte, error) {
+			if input == "slow.example.com" {
+				<-slow
+			}
+			return []byte(`{"Username":"user","Secret":"pass"}`), nil
+		},
+	}
+	go h.get("example-creds", "slow.example.com", time.Now())
+
+	done := make(chan struct{})
+	go func() {
+		h.get("example-creds", "fast.example.com", time.Now())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("getting credentials for one host was held up by a slow helper for another")
+	}
+	close(slow)
+}
+
+func TestCredsFor_HelperWithoutCredentials(t *testing.T) {
+	f := &RemoteClientFactory{
+		CredentialPlugins: []CredentialPlugin{{HostPattern: "*.example.com", Command: "example-creds"}},
+	}
+	f.helpers.run = func(_ context.Context, command, input string) ([]byte, error) {
+		if command == "example-creds" {
+			return []byte(`{"Username":"user","Secret":"pass"}`), nil
+		}
+		return []byte("credentials not found in native keychain\n"), errors.New("exit status 1")
+	}
+	cs := NoCredentials()
+	cs.store = "pass"
+
+	// the store has nothing, so the plugin is asked
+	cred, err := f.credsFor("registry.example.com", cs)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", cred.username)
+
+	// neither has anything, so there are no credentials
+	cred, err = f.credsFor("quay.io", cs)
+	assert.NoError(t, err)
+	assert.Equal(t, creds{}, cred)
+}
@@ -15,6 +15,9 @@ import (
 type creds struct {
 	username, password   string
 	registry, provenance string
+	// identityToken is given by some credential helpers in place of a
+	// password, to be exchanged for a bearer token
+	identityToken string
 }
 
 func (c creds) String() string {
@@ -27,12 +30,19 @@ func (c creds) String() string {
 // Credentials to a (Docker) registry.
 type Credentials struct {
 	m map[string]creds
+	// helpers are the credential helpers (as named in a docker
+	// config's `credHelpers`) to ask for credentials, per host
+	helpers map[string]string
+	// store is the credential helper (as named in a docker config's
+	// `credsStore`) to ask for credentials for any other host
+	store string
 }
 
 // NoCredentials returns a usable but empty credentials object.
 func NoCredentials() Credentials {
 	return Credentials{
-		m: map[string]creds{},
+		m:       map[string]creds{},
+		helpers: map[string]string{},
 	}
 }
 
@@ -57,53 +67,79 @@ func ParseCredentials(from string, b []byte) (Credentials, error) {
 		Auths map[string]struct {
 			Auth string
 		}
+		CredHelpers map[string]string
+		CredsStore  string
 	}
 	if err := json.Unmarshal(b, &config); err != nil {
 		return Credentials{}, err
 	}
 	// If it's in k8s format, it won't have the surrounding "Auth". Try that too.
-	if len(config.Auths) == 0 {
+	if len(config.Auths) == 0 && len(config.CredHelpers) == 0 && config.CredsStore == "" {
 		if err := json.Unmarshal(b, &config.Auths); err != nil {
 			return Credentials{}, err
 		}
 	}
 	m := map[string]creds{}
 	for host, entry := range config.Auths {
+		// When there are credential helpers, entries without an
+		// auth just record that a helper has credentials for the
+		// host.
+		if entry.Auth == "" && (config.CredsStore != "" || len(config.CredHelpers) > 0) {
+			continue
+		}
 		creds, err := parseAuth(entry.Auth)
 		if err != nil {
 			return Credentials{}, err
 		}
 
-		if host == "http://" || host == "https://" {
-			return Credentials{}, errors.New("Empty registry auth url")
+		host, err = parseHost(host)
+		if err != nil {
+			return Credentials{}, err
 		}
 
-		// Some users were passing in credentials in the form of
-		// http://docker.io and http://docker.io/v1/, etc.
-		// So strip everything down to the host.
-		// Also, the registry might be local and on a different port.
-		// So we need to check for that because url.Parse won't parse the ip:port format very well.
-		u, err := url.Parse(host)
-
-		// if anything went wrong try to prepend https://
-		if err != nil || u.Host == "" {
-			u, err = url.Parse(fmt.Sprintf("https://%s/", host))
-			if err != nil {
-				return Credentials{}, err
-			}
-		}
+		creds.registry = host
+		creds.provenance = from
+		m[host] = creds
+	}
 
-		if u.Host == "" { // If host is still empty the url must be broken.
-			return Credentials{}, errors.New("Invalid registry auth url. Must be a valid http address (e.g. https://gcr.io/v1/)")
+	helpers := map[string]string{}
+	for host, helper := range config.CredHelpers {
+		host, err := parseHost(host)
+		if err != nil {
+			return Credentials{}, err
 		}
+		helpers[host] = helper
+	}
+	return Credentials{m: m, helpers: helpers, store: config.CredsStore}, nil
+}
 
-		host = u.Host
+// parseHost gets the host from the key of an entry in a docker
+// config.
+func parseHost(host string) (string, error) {
+	if host == "http://" || host == "https://" {
+		return "", errors.New("Empty registry auth url")
+	}
 
-		creds.registry = host
-		creds.provenance = from
-		m[host] = creds
+	// Some users were passing in credentials in the form of
+	// http://docker.io and http://docker.io/v1/, etc.
+	// So strip everything down to the host.
+	// Also, the registry might be local and on a different port.
+	// So we need to check for that because url.Parse won't parse the ip:port format very well.
+	u, err := url.Parse(host)
+
+	// if anything went wrong try to prepend https://
+	if err != nil || u.Host == "" {
+		u, err = url.Parse(fmt.Sprintf("https://%s/", host))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if u.Host == "" { // If host is still empty the url must be broken.
+		return "", errors.New("Invalid registry auth url. Must be a valid http address (e.g. https://gcr.io/v1/)")
 	}
-	return Credentials{m: m}, nil
+
+	return u.Host, nil
 }
 
 func ImageCredsWithDefaults(lookup func() ImageCreds, configPath string) (func() ImageCreds, error) {
@@ -164,12 +200,42 @@ func (cs Credentials) Hosts() []string {
 	return hosts
 }
 
-func (cs Credentials) Merge(c Credentials) {
+// WithoutHelpers returns just the credentials given for hosts,
+// leaving out any credential helpers or store. Helpers are programs
+// that fluxd runs as itself, so they are honoured only in fluxd's own
+// docker config, and not in image pull secrets, which anyone who can
+// create a secret in a namespace could name.
+func (cs Credentials) WithoutHelpers() Credentials {
+	return Credentials{m: cs.m, helpers: map[string]string{}}
+}
+
+// helperFor returns the name of the credential helper to ask for
+// credentials for the host, if there is one.
+func (cs Credentials) helperFor(host string) string {
+	if helper, ok := cs.helpers[host]; ok {
+		return helper
+	}
+	return cs.store
+}
+
+func (cs *Credentials) Merge(c Credentials) {
 	for k, v := range c.m {
 		cs.m[k] = v
 	}
+	if len(c.helpers) > 0 && cs.helpers == nil {
+		cs.helpers = map[string]string{}
+	}
+	for k, v := range c.helpers {
+		cs.helpers[k] = v
+	}
+	if c.store != "" {
+		cs.store = c.store
+	}
 }
 
 func (cs Credentials) String() string {
-	return fmt.Sprintf("{%v}", cs.m)
+	if len(cs.helpers) == 0 && cs.store == "" {
+		return fmt.Sprintf("{%v}", cs.m)
+	}
+	return fmt.Sprintf("{%v helpers:%v store:%q}", cs.m, cs.helpers, cs.store)
 }
@@ -122,3 +122,27 @@ func TestStringShouldNotLeakPasswords(t *testing.T) {
 	assert.Equal(t, "{map[localhost:5000:<registry creds for testuser@localhost:5000, from test>]}", fmt.Sprintf("%v", c)) // In comparison standard String() method typically yields: "{map[localhost:5000:{testuser testpassword localhost:5000 test}]}".
 	assert.Equal(t, "testpassword", c.credsFor("localhost:5000").password, "Password is incorrect")                        // Actual password is left untouched.
 }
+
+func TestParseCreds_helpers(t *testing.T) {
+	config := []byte(`{
+  "auths": {"https://index.docker.io/v1/": {}, "localhost:5000": {"auth": "dGVzdHVzZXI6dGVzdHBhc3N3b3Jk"}},
+  "credHelpers": {"123456789.dkr.ecr.eu-west-1.amazonaws.com": "ecr-login", "https://gcr.io": "gcr"},
+  "credsStore": "pass"
+}`)
+	c, err := ParseCredentials("test", config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"localhost:5000"}, c.Hosts())
+	assert.Equal(t, "ecr-login", c.helperFor("123456789.dkr.ecr.eu-west-1.amazonaws.com"))
+	assert.Equal(t, "gcr", c.helperFor("gcr.io"))
+	assert.Equal(t, "pass", c.helperFor("index.docker.io"))
+
+	merged := NoCredentials()
+	merged.Merge(c)
+	assert.Equal(t, "gcr", merged.helperFor("gcr.io"))
+	assert.Equal(t, "pass", merged.helperFor("quay.io"))
+
+	stripped := c.WithoutHelpers()
+	assert.Equal(t, []string{"localhost:5000"}, stripped.Hosts())
+	assert.Equal(t, "", stripped.helperFor("gcr.io"))
+	assert.Equal(t, "", stripped.helperFor("quay.io"))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quota is what a registry has told us about how many more requests
+// we can make, and when it will let us make more.
+type quota struct {
+	// remaining is the requests left in the current window, or -1 if
+	// not reported
+	remaining int
+	// reset is when the current window ends, or zero if not reported
+	reset time.Time
+	// window is the length of the window in which the quota
+	// applies, or zero if not reported
+	window time.Duration
+	// retryAfter is when the registry has said to try again, or zero
+	// if it hasn't
+	retryAfter time.Time
+}
+
+// parseQuota looks for the headers registries use to report rate
+// limits:
+//
+//   - `Retry-After`, as either seconds or a date (RFC 7231);
+//   - `RateLimit-Remaining` and `RateLimit-Reset`, as in the IETF
+//     draft and as sent (without a reset) by Docker Hub, which gives
+//     e.g., `RateLimit-Remaining: 76;w=21600`;
+//   - `X-RateLimit-Remaining` and `X-RateLimit-Reset`, the latter
+//     as a Unix time, as sent by GitHub (and GHCR).
+func parseQuota(h http.Header, now time.Time) quota {
+	q := quota{remaining: -1}
+
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && secs >= 0 {
+			q.retryAfter = now.Add(time.Duration(secs) * time.Second)
+		} else if t, err := http.ParseTime(v); err == nil {
+			q.retryAfter = t
+		}
+	}
+
+	if v := firstHeader(h, "RateLimit-Remaining", "X-RateLimit-Remaining"); v != "" {
+		if n, err := strconv.Atoi(quotaValue(v)); err == nil && n >= 0 {
+			q.remaining = n
+		}
+		q.window = quotaWindow(v)
+	}
+
+	if v := h.Get("RateLimit-Reset"); v != "" {
+		// seconds until the window resets
+		if secs, err := strconv.Atoi(quotaValue(v)); err == nil && secs >= 0 {
+			q.reset = now.Add(time.Duration(secs) * time.Second)
+		}
+	} else if v := h.Get("X-RateLimit-Reset"); v != "" {
+		// Unix time at which the window resets
+		if epoch, err := strconv.ParseInt(quotaValue(v), 10, 64); err == nil && epoch > 0 {
+			q.reset = time.Unix(epoch, 0)
+		}
+	}
+	return q
+}
+
+func firstHeader(h http.Header, names ...string) string {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// quotaValue strips any parameters (e.g., `;w=21600`) from a rate
+// limit header value.
+func quotaValue(v string) string {
+	if i := strings.IndexByte(v, ';'); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+// quotaWindow finds the window parameter (e.g., `;w=21600`) in a rate
+// limit header value, if there is one.
+func quotaWindow(v string) time.Duration {
+	params := strings.Split(v, ";")
+	for _, param := range params[1:] {
+		param = strings.TrimSpace(param)
+		if strings.HasPrefix(param, "w=") {
+			if secs, err := strconv.Atoi(param[2:]); err == nil && secs > 0 {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	LabelHost = "host"
+)
+
+var (
+	rateLimitRemaining = prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "flux",
+		Subsystem: "registry",
+		Name:      "rate_limit_remaining",
+		Help:      "Requests remaining in the current rate limit window, as last reported by the registry host.",
+	}, []string{LabelHost})
+	rateLimitRPS = prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "flux",
+		Subsystem: "registry",
+		Name:      "rate_limit_rps",
+		Help:      "Requests per second currently allowed to the registry host.",
+	}, []string{LabelHost})
+	backingOff = prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+		Namespace: "flux",
+		Subsystem: "registry",
+		Name:      "backing_off",
+		Help:      "Whether requests to the registry host are paused (1) or not (0), after it has said to slow down.",
+	}, []string{LabelHost})
+	deferredRequests = prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "flux",
+		Subsystem: "registry",
+		Name:      "deferred_requests_total",
+		Help:      "Requests not made to the registry host because it has said to slow down.",
+	}, []string{LabelHost})
+)
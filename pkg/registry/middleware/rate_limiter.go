@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
+	stderrors "errors"
+
 	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
 	"golang.org/x/time/rate"
@@ -16,8 +19,45 @@ const (
 	backOffBy         = 2.0
 	recoverBy         = 1.5
 	coolDownInSeconds = 1200
+	// How long to pause requests to a host that says `HTTP 429`
+	// without saying for how long.
+	defaultPause = time.Minute
 )
 
+// BackOffError is returned, in place of making a request, while
+// requests to a host are paused and the request would not be able to
+// wait until the pause ends.
+type BackOffError struct {
+	Host  string
+	Until time.Time
+}
+
+func (err *BackOffError) Error() string {
+	return fmt.Sprintf("rate limited by %s until %s", err.Host, err.Until.Format(time.RFC3339))
+}
+
+// IsBackingOff says whether an error is, or wraps, a BackOffError;
+// these are expected while a host is rate limiting us, so callers
+// needn't report them.
+func IsBackingOff(err error) bool {
+	var backOff *BackOffError
+	if stderrors.As(err, &backOff) {
+		return true
+	}
+	_, ok := errors.Cause(err).(*BackOffError)
+	return ok
+}
+
+// backOffEpisode records a period during which requests to a host are
+// paused, so it can be summarised once it's over.
+type backOffEpisode struct {
+	start    time.Time
+	until    time.Time
+	reason   string
+	deferred int
+	timer    *time.Timer
+}
+
 // RateLimiters keeps track of per-host rate limiting for an arbitrary
 // set of hosts.
 
@@ -27,6 +67,13 @@ const (
 // host. It will only do so once, so that concurrent requests don't
 // *also* reduce the limit.
 //
+// If a host says how long to wait (with `Retry-After`), or that
+// there's no quota left until some time (with `RateLimit-Remaining`
+// and `RateLimit-Reset`, or the `X-RateLimit-` equivalents), all
+// requests to that host are paused until then; and when it says how
+// much quota is left, the limit is lowered so that it lasts until the
+// quota is reset. A single line is logged when each pause is over.
+//
 // Call `*RateLimiter.Recover(host)` when an operation has succeeded
 // without incident, which will increase the rate limit modestly back
 // towards the given ideal.
@@ -36,6 +83,7 @@ type RateLimiters struct {
 	Logger               log.Logger
 	perHost              map[string]*rate.Limiter
 	latestBackOffPerHost map[string]time.Time
+	episodes             map[string]*backOffEpisode
 	mu                   sync.Mutex
 }
 
@@ -75,6 +123,7 @@ func (limiters *RateLimiters) backOff(host string) {
 	}
 	backOffTime := time.Now()
 	limiter.SetLimitAt(backOffTime, rate.Limit(newLimit))
+	rateLimitRPS.With(LabelHost, host).Set(newLimit)
 	if limiters.latestBackOffPerHost == nil {
 		limiters.latestBackOffPerHost = map[string]time.Time{}
 	}
@@ -101,7 +150,118 @@ func (limiters *RateLimiters) Recover(host string) {
 			limiters.Logger.Log("info", "increasing rate limit", "host", host, "limit", strconv.FormatFloat(newLimit, 'f', 2, 64))
 		}
 		limiter.SetLimit(rate.Limit(newLimit))
+		rateLimitRPS.With(LabelHost, host).Set(newLimit)
+	}
+}
+
+// observe looks at the rate limit headers in a response from a host,
+// to see whether it should pause requests to the host, or slow them
+// down.
+func (limiters *RateLimiters) observe(host string, resp *http.Response) {
+	now := time.Now()
+	q := parseQuota(resp.Header, now)
+	if q.remaining >= 0 {
+		rateLimitRemaining.With(LabelHost, host).Set(float64(q.remaining))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		until, reason := q.retryAfter, "too many requests"
+		if until.IsZero() {
+			until = q.reset
+		}
+		if !until.After(now) {
+			until = now.Add(defaultPause)
+		}
+		limiters.pause(host, until, reason)
+	case q.remaining == 0 && q.reset.After(now):
+		limiters.pause(host, q.reset, "quota used up")
+	case q.remaining > 0 && q.reset.After(now):
+		// Spread what's left of the quota over what's left of the
+		// window, if that's slower than we're going now.
+		limiters.lowerTo(host, float64(q.remaining)/q.reset.Sub(now).Seconds())
+	case q.remaining > 0 && q.window > 0:
+		// Without knowing when the window resets, assume the
+		// worst: that the quota has to last the whole window.
+		limiters.lowerTo(host, float64(q.remaining)/q.window.Seconds())
+	}
+}
+
+func (limiters *RateLimiters) lowerTo(host string, limit float64) {
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+	limiter, ok := limiters.perHost[host]
+	if !ok {
+		return
+	}
+	oldLimit := float64(limiter.Limit())
+	newLimit := limiters.clip(limit)
+	if newLimit >= oldLimit {
+		return
 	}
+	limiter.SetLimit(rate.Limit(newLimit))
+	rateLimitRPS.With(LabelHost, host).Set(newLimit)
+	if limiters.latestBackOffPerHost == nil {
+		limiters.latestBackOffPerHost = map[string]time.Time{}
+	}
+	limiters.latestBackOffPerHost[host] = time.Now()
+}
+
+// pause stops requests to the host until the time given (or later, if
+// it's already paused for longer).
+func (limiters *RateLimiters) pause(host string, until time.Time, reason string) {
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+	if limiters.episodes == nil {
+		limiters.episodes = map[string]*backOffEpisode{}
+	}
+	episode, ok := limiters.episodes[host]
+	if !ok {
+		episode = &backOffEpisode{start: time.Now(), reason: reason}
+		limiters.episodes[host] = episode
+		backingOff.With(LabelHost, host).Set(1)
+	}
+	if !until.After(episode.until) {
+		return
+	}
+	episode.until = until
+	if episode.timer != nil {
+		episode.timer.Stop()
+	}
+	episode.timer = time.AfterFunc(time.Until(until), func() {
+		limiters.endPause(host)
+	})
+}
+
+func (limiters *RateLimiters) endPause(host string) {
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+	episode, ok := limiters.episodes[host]
+	if !ok || time.Now().Before(episode.until) {
+		// it's been extended, and will be ended later
+		return
+	}
+	delete(limiters.episodes, host)
+	backingOff.With(LabelHost, host).Set(0)
+	if limiters.Logger != nil {
+		limiters.Logger.Log("info", "resuming requests after rate limiting", "host", host, "reason", episode.reason,
+			"paused_for", time.Since(episode.start).Round(time.Second).String(), "deferred_requests", episode.deferred)
+	}
+}
+
+// pausedUntil returns when requests to the host can resume, or the
+// zero time if they are not paused; the request asking is counted as
+// deferred.
+func (limiters *RateLimiters) pausedUntil(host string) time.Time {
+	limiters.mu.Lock()
+	defer limiters.mu.Unlock()
+	episode, ok := limiters.episodes[host]
+	if !ok || !time.Now().Before(episode.until) {
+		return time.Time{}
+	}
+	episode.deferred++
+	deferredRequests.With(LabelHost, host).Add(1)
+	return episode.until
 }
 
 // Limit returns a RoundTripper for a particular host. We expect to do
@@ -116,11 +276,14 @@ func (limiters *RateLimiters) RoundTripper(rt http.RoundTripper, host string) ht
 	if _, ok := limiters.perHost[host]; !ok {
 		rl := rate.NewLimiter(rate.Limit(limiters.RPS), limiters.Burst)
 		limiters.perHost[host] = rl
+		rateLimitRPS.With(LabelHost, host).Set(limiters.RPS)
 	}
 	var reduceOnce sync.Once
 	return &roundTripRateLimiter{
-		rl: limiters.perHost[host],
-		tx: rt,
+		rl:     limiters.perHost[host],
+		tx:     rt,
+		host:   host,
+		limits: limiters,
 		slowDown: func() {
 			reduceOnce.Do(func() { limiters.backOff(host) })
 		},
@@ -130,10 +293,27 @@ func (limiters *RateLimiters) RoundTripper(rt http.RoundTripper, host string) ht
 type roundTripRateLimiter struct {
 	rl       *rate.Limiter
 	tx       http.RoundTripper
+	host     string
+	limits   *RateLimiters
 	slowDown func()
 }
 
 func (t *roundTripRateLimiter) RoundTrip(r *http.Request) (*http.Response, error) {
+	// If the host has told us to stop for a while, wait it out --
+	// unless the request would time out before then, in which case
+	// give up now rather than bothering the host.
+	if until := t.limits.pausedUntil(t.host); !until.IsZero() {
+		if deadline, ok := r.Context().Deadline(); ok && deadline.Before(until) {
+			return nil, &BackOffError{Host: t.host, Until: until}
+		}
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, r.Context().Err()
+		}
+	}
 	// Wait errors out if the request cannot be processed within
 	// the deadline. This is pre-emptive, instead of waiting the
 	// entire duration.
@@ -147,5 +327,6 @@ func (t *roundTripRateLimiter) RoundTrip(r *http.Request) (*http.Response, error
 	if resp.StatusCode == http.StatusTooManyRequests {
 		t.slowDown()
 	}
+	t.limits.observe(t.host, resp)
 	return resp, err
 }
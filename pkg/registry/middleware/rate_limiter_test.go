@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuota(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	for _, c := range []struct {
+		name     string
+		header   map[string]string
+		expected quota
+	}{
+		{"none", nil, quota{remaining: -1}},
+		{"retry after seconds", map[string]string{"Retry-After": "120"},
+			quota{remaining: -1, retryAfter: now.Add(2 * time.Minute)}},
+		{"retry after date", map[string]string{"Retry-After": now.Add(time.Hour).UTC().Format(http.TimeFormat)},
+			quota{remaining: -1, retryAfter: now.Add(time.Hour)}},
+		{"docker hub", map[string]string{"RateLimit-Limit": "100;w=21600", "RateLimit-Remaining": "76;w=21600"},
+			quota{remaining: 76, window: 6 * time.Hour}},
+		{"draft", map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "30"},
+			quota{remaining: 0, reset: now.Add(30 * time.Second)}},
+		{"github", map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "1600000600"},
+			quota{remaining: 10, reset: now.Add(10 * time.Minute)}},
+		{"garbage", map[string]string{"Retry-After": "soon", "RateLimit-Remaining": "lots"},
+			quota{remaining: -1}},
+	} {
+		h := http.Header{}
+		for k, v := range c.header {
+			h.Set(k, v)
+		}
+		q := parseQuota(h, now)
+		assert.Equal(t, c.expected.remaining, q.remaining, c.name)
+		assert.Equal(t, c.expected.window, q.window, c.name)
+		assert.True(t, c.expected.reset.Equal(q.reset), "%s: reset %s != %s", c.name, c.expected.reset, q.reset)
+		assert.True(t, c.expected.retryAfter.Equal(q.retryAfter), "%s: retry after %s != %s", c.name, c.expected.retryAfter, q.retryAfter)
+	}
+}
+
+func TestRoundTripper_PausesOnRetryAfter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	host := mustHost(t, server.URL)
+
+	limiters := &RateLimiters{RPS: 100, Burst: 10, Logger: log.NewNopLogger()}
+	client := &http.Client{Transport: limiters.RoundTripper(http.DefaultTransport, host)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 50.0, float64(limiters.perHost[host].Limit()), "limit should have been halved")
+
+	// A request that can't wait until the pause is over fails
+	// without reaching the server.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	_, err = client.Do(req.WithContext(ctx))
+	assert.True(t, IsBackingOff(err), "expected back off error, got %v", err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// A request that can wait, waits.
+	start := time.Now()
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, time.Since(start) > 500*time.Millisecond, "request should have waited for the pause to end")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// The episode is over and summarised.
+	time.Sleep(50 * time.Millisecond)
+	limiters.mu.Lock()
+	_, paused := limiters.episodes[host]
+	limiters.mu.Unlock()
+	assert.False(t, paused)
+}
+
+func TestRoundTripper_LowersRateToQuota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", "60")
+		w.Header().Set("RateLimit-Reset", "60")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	host := mustHost(t, server.URL)
+
+	limiters := &RateLimiters{RPS: 100, Burst: 10}
+	client := &http.Client{Transport: limiters.RoundTripper(http.DefaultTransport, host)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	assert.InDelta(t, 1.0, float64(limiters.perHost[host].Limit()), 0.1)
+	assert.Empty(t, limiters.episodes)
+}
+
+func mustHost(t *testing.T, s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
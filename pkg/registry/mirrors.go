@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+
+	"github.com/fluxcd/flux/pkg/image"
+)
+
+// Mirror is a registry to use in place of another, e.g., a
+// pull-through cache of Docker Hub. Images are looked for at the same
+// path in the mirror, after any path prefix (e.g., Harbor proxy
+// caches are projects, so images are at
+// `harbor.example.com/<project>/library/alpine`).
+type Mirror struct {
+	Host       string
+	PathPrefix string
+}
+
+func (m Mirror) String() string {
+	if m.PathPrefix == "" {
+		return m.Host
+	}
+	return m.Host + "/" + m.PathPrefix
+}
+
+// Mirrors gives the mirrors to use, in order, for each (canonical)
+// registry host.
+type Mirrors map[string][]Mirror
+
+// ParseMirror parses a mirror given as
+// `<registry host>=<mirror host>[/<path prefix>]`, returning the
+// canonical registry host and the mirror.
+func ParseMirror(s string) (string, Mirror, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", Mirror{}, fmt.Errorf("mirror %q is not in the form <registry host>=<mirror host>[/<path prefix>]", s)
+	}
+	// e.g., docker.io means the same as index.docker.io
+	host := image.Name{Domain: parts[0]}.Registry()
+	mirror := strings.Trim(parts[1], "/")
+	if strings.Contains(mirror, "://") {
+		return "", Mirror{}, fmt.Errorf("mirror %q should be a host and optional path, without a scheme", parts[1])
+	}
+	hostAndPrefix := strings.SplitN(mirror, "/", 2)
+	m := Mirror{Host: hostAndPrefix[0]}
+	if len(hostAndPrefix) == 2 {
+		m.PathPrefix = hostAndPrefix[1]
+	}
+	return host, m, nil
+}
+
+// mirroredClient uses each of a series of clients in turn -- mirrors,
+// then possibly the upstream registry -- until one succeeds. Clients
+// are only created when needed, so an unreachable upstream doesn't
+// matter unless the mirrors fail.
+type mirroredClient struct {
+	repo      image.CanonicalName
+	endpoints []mirrorEndpoint
+	logger    log.Logger
+
+	mu      sync.Mutex
+	clients map[int]Client
+	// so that falling back is logged once per client, rather than
+	// for every manifest
+	warnOnce sync.Once
+}
+
+type mirrorEndpoint struct {
+	name      string
+	newClient func() (Client, error)
+}
+
+func (c *mirroredClient) client(i int) (Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[i]; ok {
+		return client, nil
+	}
+	client, err := c.endpoints[i].newClient()
+	if err != nil {
+		return nil, err
+	}
+	if c.clients == nil {
+		c.clients = map[int]Client{}
+	}
+	c.clients[i] = client
+	return client, nil
+}
+
+// try calls the function with each client in turn, until it succeeds
+// or there are no more clients to try.
+func (c *mirroredClient) try(ctx context.Context, f func(Client) error) error {
+	var err error
+	for i := range c.endpoints {
+		var client Client
+		if client, err = c.client(i); err == nil {
+			if err = f(client); err == nil {
+				return nil
+			}
+		}
+		if ctx.Err() != nil || i == len(c.endpoints)-1 {
+			break
+		}
+		failedErr, next := err, c.endpoints[i+1].name
+		c.warnOnce.Do(func() {
+			c.logger.Log("warning", "registry mirror failed; trying next", "canonical_name", c.repo.String(),
+				"mirror", c.endpoints[i].name, "next", next, "err", failedErr)
+		})
+	}
+	return err
+}
+
+func (c *mirroredClient) Tags(ctx context.Context) ([]string, error) {
+	var tags []string
+	err := c.try(ctx, func(client Client) (err error) {
+		tags, err = client.Tags(ctx)
+		return err
+	})
+	return tags, err
+}
+
+func (c *mirroredClient) Manifest(ctx context.Context, ref string) (ImageEntry, error) {
+	var entry ImageEntry
+	var labelErr error
+	err := c.try(ctx, func(client Client) (err error) {
+		entry, err = client.Manifest(ctx, ref)
+		// This comes with a usable entry, so isn't a failure
+		if _, ok := err.(*image.LabelTimestampFormatError); ok {
+			labelErr = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return entry, err
+	}
+	return entry, labelErr
+}
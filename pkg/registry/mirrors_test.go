@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/image"
+)
+
+func TestParseMirror(t *testing.T) {
+	for _, c := range []struct {
+		in     string
+		host   string
+		mirror Mirror
+		err    bool
+	}{
+		{in: "docker.io=mirror.example.com:5000", host: "index.docker.io", mirror: Mirror{Host: "mirror.example.com:5000"}},
+		{in: "index.docker.io=harbor.example.com/dockerhub-proxy/", host: "index.docker.io", mirror: Mirror{Host: "harbor.example.com", PathPrefix: "dockerhub-proxy"}},
+		{in: "quay.io=harbor.example.com/proxies/quay", host: "quay.io", mirror: Mirror{Host: "harbor.example.com", PathPrefix: "proxies/quay"}},
+		{in: "quay.io", err: true},
+		{in: "quay.io=", err: true},
+		{in: "quay.io=https://mirror.example.com", err: true},
+	} {
+		host, mirror, err := ParseMirror(c.in)
+		if c.err {
+			assert.Error(t, err, c.in)
+			continue
+		}
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.host, host, c.in)
+		assert.Equal(t, c.mirror, mirror, c.in)
+	}
+}
+
+type fakeClient struct {
+	tags []string
+	err  error
+}
+
+func (c fakeClient) Tags(context.Context) ([]string, error) {
+	return c.tags, c.err
+}
+
+func (c fakeClient) Manifest(ctx context.Context, ref string) (ImageEntry, error) {
+	return ImageEntry{}, c.err
+}
+
+func TestMirroredClient_Fallback(t *testing.T) {
+	var created []string
+	endpoint := func(name string, client Client, err error) mirrorEndpoint {
+		return mirrorEndpoint{name: name, newClient: func() (Client, error) {
+			created = append(created, name)
+			return client, err
+		}}
+	}
+
+	// The first mirror that works is used, and later ones are not
+	// even created
+	c := &mirroredClient{logger: log.NewNopLogger(), endpoints: []mirrorEndpoint{
+		endpoint("unreachable", nil, errors.New("no route to host")),
+		endpoint("failing", fakeClient{err: errors.New("500 Internal Server Error")}, nil),
+		endpoint("working", fakeClient{tags: []string{"v1"}}, nil),
+		endpoint("upstream", fakeClient{tags: []string{"v1", "v2"}}, nil),
+	}}
+	tags, err := c.Tags(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v1"}, tags)
+	assert.Equal(t, []string{"unreachable", "failing", "working"}, created)
+
+	// The last error is returned if all fail
+	c = &mirroredClient{logger: log.NewNopLogger(), endpoints: []mirrorEndpoint{
+		endpoint("mirror", fakeClient{err: errors.New("mirror error")}, nil),
+		endpoint("upstream", fakeClient{err: errors.New("upstream error")}, nil),
+	}}
+	_, err = c.Tags(context.Background())
+	assert.EqualError(t, err, "upstream error")
+}
+
+func TestRemoteClientFactory_Mirror(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/dockerhub-proxy/library/alpine/tags/list":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"name":"dockerhub-proxy/library/alpine","tags":["3.12","3.13"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	f := &RemoteClientFactory{
+		Logger:        log.NewNopLogger(),
+		InsecureHosts: []string{u.Host},
+		Mirrors: Mirrors{
+			"index.docker.io": {{Host: u.Host, PathPrefix: "dockerhub-proxy"}},
+		},
+	}
+	repo := image.Name{Image: "alpine"}.CanonicalName()
+	client, err := f.ClientFor(repo, NoCredentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := client.Tags(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3.12", "3.13"}, tags)
+	assert.Contains(t, paths, "/v2/dockerhub-proxy/library/alpine/tags/list")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/policy"
 )
 
 var (
@@ -21,6 +22,82 @@ type Registry interface {
 // which is supplied to us (probably by interrogating the cluster)
 type ImageCreds map[image.Name]Credentials
 
+// TagSelection says which tags of an image repository are in use:
+// those deployed, and those matching the tag patterns used to look
+// for updates.
+type TagSelection struct {
+	Patterns []policy.Pattern
+	Deployed []string
+}
+
+// Add includes the pattern and deployed tag given in the selection,
+// if they aren't already.
+func (s *TagSelection) Add(pattern policy.Pattern, deployed string) {
+	found := false
+	for _, p := range s.Patterns {
+		if p.String() == pattern.String() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		s.Patterns = append(s.Patterns, pattern)
+	}
+	if deployed == "" {
+		return
+	}
+	for _, t := range s.Deployed {
+		if t == deployed {
+			return
+		}
+	}
+	s.Deployed = append(s.Deployed, deployed)
+}
+
+// All returns true if every tag is in use, i.e., some consumer does
+// not filter tags.
+func (s TagSelection) All() bool {
+	for _, p := range s.Patterns {
+		if p.String() == policy.PatternAll.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Labels returns the names of the image labels by which the patterns
+// order images.
+func (s TagSelection) Labels() []string {
+	var labels []string
+	for _, p := range s.Patterns {
+		if label, ok := policy.OrderingLabel(p); ok {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Matches returns true if the tag given is in the selection.
+func (s TagSelection) Matches(tag string) bool {
+	for _, t := range s.Deployed {
+		if t == tag {
+			return true
+		}
+	}
+	for _, p := range s.Patterns {
+		if p.Matches(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageTags is a record of which tags are in use for each image
+// repository, which is supplied to us (probably by interrogating the
+// cluster). An image repository without an entry may have any of its
+// tags in use.
+type ImageTags map[image.CanonicalName]TagSelection
+
 // ImageScanDisabledRegistry is used when image scanning is disabled
 type ImageScanDisabledRegistry struct{}
 
@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+)
+
+// HostTLS supplies TLS configuration for particular registry hosts,
+// from files in a directory laid out as for docker (in
+// `/etc/docker/certs.d`): a directory per host (including the port,
+// if there is one), containing
+//
+//   - CA certificates to trust as well as the system roots, as `*.crt`;
+//   - client certificates as `*.cert`, each with its key in a `.key`
+//     file of the same name.
+//
+// The files are checked each time configuration is asked for, and
+// loaded again if they've changed.
+type HostTLS struct {
+	Dir    string
+	Logger log.Logger
+
+	mu    sync.Mutex
+	hosts map[string]*hostTLS
+}
+
+type hostTLS struct {
+	files  []fileVersion
+	config *tls.Config
+	err    error
+}
+
+type fileVersion struct {
+	name    string
+	modTime time.Time
+	size    int64
+}
+
+// ConfigFor returns the TLS configuration for the host, or nil if
+// there is nothing specific to the host.
+func (t *HostTLS) ConfigFor(host string) (*tls.Config, error) {
+	dir := filepath.Join(t.Dir, host)
+	files, err := tlsFiles(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading TLS configuration for %s", host)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.hosts == nil {
+		t.hosts = map[string]*hostTLS{}
+	}
+	if cached, ok := t.hosts[host]; ok && sameFiles(cached.files, files) {
+		return cached.config, cached.err
+	}
+
+	config, err := loadTLS(dir, files)
+	if err != nil {
+		err = errors.Wrapf(err, "loading TLS configuration for %s", host)
+	}
+	t.hosts[host] = &hostTLS{files: files, config: config, err: err}
+	if t.Logger != nil && len(files) > 0 {
+		if err != nil {
+			t.Logger.Log("host", host, "err", err)
+		} else {
+			t.Logger.Log("info", "loaded TLS configuration", "host", host, "files", fileNames(files))
+		}
+	}
+	return config, err
+}
+
+// tlsFiles lists the files relevant to TLS configuration in the
+// directory, with enough information to tell if they change. A
+// directory that doesn't exist has no files.
+func tlsFiles(dir string) ([]fileVersion, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []fileVersion
+	for _, info := range infos {
+		name := info.Name()
+		if !(strings.HasSuffix(name, ".crt") || strings.HasSuffix(name, ".cert") || strings.HasSuffix(name, ".key")) {
+			continue
+		}
+		// Kubernetes mounts secrets and config maps as symlinks to
+		// files that are replaced when updated, so look at the file
+		// linked to.
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+		files = append(files, fileVersion{name: name, modTime: info.ModTime(), size: info.Size()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+func sameFiles(a, b []fileVersion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].name != b[i].name || a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func fileNames(files []fileVersion) string {
+	var names []string
+	for _, f := range files {
+		names = append(names, f.name)
+	}
+	return strings.Join(names, ",")
+}
+
+func loadTLS(dir string, files []fileVersion) (*tls.Config, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		switch {
+		case strings.HasSuffix(f.name, ".crt"):
+			if config.RootCAs == nil {
+				pool, err := x509.SystemCertPool()
+				if err != nil || pool == nil {
+					pool = x509.NewCertPool()
+				}
+				config.RootCAs = pool
+			}
+			pem, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if !config.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", f.name)
+			}
+		case strings.HasSuffix(f.name, ".cert"):
+			keyName := strings.TrimSuffix(f.name, ".cert") + ".key"
+			cert, err := tls.LoadX509KeyPair(path, filepath.Join(dir, keyName))
+			if err != nil {
+				return nil, errors.Wrapf(err, "client certificate %s", f.name)
+			}
+			config.Certificates = append(config.Certificates, cert)
+		case strings.HasSuffix(f.name, ".key"):
+			certName := strings.TrimSuffix(f.name, ".key") + ".cert"
+			if _, err := os.Stat(filepath.Join(dir, certName)); err != nil {
+				return nil, fmt.Errorf("key %s has no client certificate %s", f.name, certName)
+			}
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,145 @@
+package registry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, template *x509.Certificate) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template.SerialNumber = serial
+	template.Subject = pkix.Name{CommonName: name}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestHostTLS(t *testing.T) {
+	ca := newTestCert(t, "ca", nil, &x509.Certificate{IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign})
+	serverCert := newTestCert(t, "server", ca, &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientCert := newTestCert(t, "client", ca, &x509.Certificate{
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	serverKeyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverKeyPair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	host := server.Listener.Addr().String()
+
+	dir, err := ioutil.TempDir("", "flux-registry-certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	hostTLS := &HostTLS{Dir: dir}
+
+	get := func() error {
+		config, err := hostTLS.ConfigFor(host)
+		if err != nil {
+			return err
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		resp, err := client.Get("https://" + host + "/v2/")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// Nothing configured for the host
+	config, err := hostTLS.ConfigFor(host)
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+	assert.Error(t, get())
+
+	hostDir := filepath.Join(dir, host)
+	if err := os.MkdirAll(hostDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, content []byte) {
+		path := filepath.Join(hostDir, name)
+		if err := ioutil.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+		// make sure the change is noticed, whatever the resolution
+		// of file times
+		later := time.Now().Add(time.Duration(len(content)) * time.Second)
+		os.Chtimes(path, later, later)
+	}
+
+	// The CA alone isn't enough, since the server wants a client
+	// certificate
+	write("ca.crt", ca.certPEM)
+	assert.Error(t, get())
+
+	// A certificate without its key is an error
+	write("client.cert", clientCert.certPEM)
+	_, err = hostTLS.ConfigFor(host)
+	assert.Error(t, err)
+
+	write("client.key", clientCert.keyPEM)
+	assert.NoError(t, get())
+
+	// Changed files are loaded again
+	write("ca.crt", []byte("not a certificate"))
+	_, err = hostTLS.ConfigFor(host)
+	assert.Error(t, err)
+}
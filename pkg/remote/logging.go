@@ -8,6 +8,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/job"
@@ -133,3 +135,30 @@ func (p *ErrorLoggingServer) NotifyChange(ctx context.Context, change v9.Change)
 	}()
 	return p.server.NotifyChange(ctx, change)
 }
+
+func (p *ErrorLoggingServer) PinRevision(ctx context.Context, revision string) (err error) {
+	defer func() {
+		if err != nil {
+			p.logger.Log("method", "PinRevision", "error", err)
+		}
+	}()
+	return p.server.PinRevision(ctx, revision)
+}
+
+func (p *ErrorLoggingServer) ListPendingUpdates(ctx context.Context) (_ []v13.PendingUpdate, err error) {
+	defer func() {
+		if err != nil {
+			p.logger.Log("method", "ListPendingUpdates", "error", err)
+		}
+	}()
+	return p.server.ListPendingUpdates(ctx)
+}
+
+func (p *ErrorLoggingServer) ListDeferredUpdates(ctx context.Context) (_ []v14.DeferredUpdate, err error) {
+	defer func() {
+		if err != nil {
+			p.logger.Log("method", "ListDeferredUpdates", "error", err)
+		}
+	}()
+	return p.server.ListDeferredUpdates(ctx)
+}
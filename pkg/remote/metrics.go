@@ -11,6 +11,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/job"
@@ -157,3 +159,33 @@ func (i *instrumentedServer) NotifyChange(ctx context.Context, change v9.Change)
 	}(time.Now())
 	return i.s.NotifyChange(ctx, change)
 }
+
+func (i *instrumentedServer) PinRevision(ctx context.Context, revision string) (err error) {
+	defer func(begin time.Time) {
+		requestDuration.With(
+			fluxmetrics.LabelMethod, "PinRevision",
+			fluxmetrics.LabelSuccess, fmt.Sprint(err == nil),
+		).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return i.s.PinRevision(ctx, revision)
+}
+
+func (i *instrumentedServer) ListPendingUpdates(ctx context.Context) (_ []v13.PendingUpdate, err error) {
+	defer func(begin time.Time) {
+		requestDuration.With(
+			fluxmetrics.LabelMethod, "ListPendingUpdates",
+			fluxmetrics.LabelSuccess, fmt.Sprint(err == nil),
+		).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return i.s.ListPendingUpdates(ctx)
+}
+
+func (i *instrumentedServer) ListDeferredUpdates(ctx context.Context) (_ []v14.DeferredUpdate, err error) {
+	defer func(begin time.Time) {
+		requestDuration.With(
+			fluxmetrics.LabelMethod, "ListDeferredUpdates",
+			fluxmetrics.LabelSuccess, fmt.Sprint(err == nil),
+		).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return i.s.ListDeferredUpdates(ctx)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/guid"
@@ -49,6 +51,14 @@ type MockServer struct {
 
 	GitRepoConfigAnswer v6.GitConfig
 	GitRepoConfigError  error
+
+	PinRevisionError error
+
+	ListPendingUpdatesAnswer []v13.PendingUpdate
+	ListPendingUpdatesError  error
+
+	ListDeferredUpdatesAnswer []v14.DeferredUpdate
+	ListDeferredUpdatesError  error
 }
 
 func (p *MockServer) Ping(ctx context.Context) error {
@@ -104,6 +114,18 @@ func (p *MockServer) GitRepoConfig(ctx context.Context, regenerate bool) (v6.Git
 	return p.GitRepoConfigAnswer, p.GitRepoConfigError
 }
 
+func (p *MockServer) PinRevision(ctx context.Context, revision string) error {
+	return p.PinRevisionError
+}
+
+func (p *MockServer) ListPendingUpdates(ctx context.Context) ([]v13.PendingUpdate, error) {
+	return p.ListPendingUpdatesAnswer, p.ListPendingUpdatesError
+}
+
+func (p *MockServer) ListDeferredUpdates(ctx context.Context) ([]v14.DeferredUpdate, error) {
+	return p.ListDeferredUpdatesAnswer, p.ListDeferredUpdatesError
+}
+
 var _ api.Server = &MockServer{}
 
 // -- Battery of tests for an api.Server implementation. Since these
@@ -152,6 +174,27 @@ func ServerTestBattery(t *testing.T, wrap func(mock api.Server) api.Server) {
 		},
 	}
 
+	pendingAnswer := []v13.PendingUpdate{
+		{
+			WorkloadID: resource.MustParseID("foobar/hello"),
+			Container:  "frobnicator",
+			Current:    imageID,
+			Target:     imageID.WithNewTag("v0.4.6"),
+			ProposedAt: now,
+		},
+	}
+
+	deferredAnswer := []v14.DeferredUpdate{
+		{
+			WorkloadID: resource.MustParseID("foobar/hello"),
+			Container:  "frobnicator",
+			Current:    imageID,
+			Target:     imageID.WithNewTag("v0.4.6"),
+			Reason:     "per-run limit",
+			DeferredAt: now,
+		},
+	}
+
 	syncStatusAnswer := []string{
 		"commit 1",
 		"commit 2",
@@ -180,6 +223,9 @@ func ServerTestBattery(t *testing.T, wrap func(mock api.Server) api.Server) {
 		UpdateManifestsArgTest: checkUpdateSpec,
 		UpdateManifestsAnswer:  job.ID(guid.New()),
 		SyncStatusAnswer:       syncStatusAnswer,
+
+		ListPendingUpdatesAnswer:  pendingAnswer,
+		ListDeferredUpdatesAnswer: deferredAnswer,
 	}
 
 	ctx := context.Background()
@@ -244,4 +290,36 @@ func ServerTestBattery(t *testing.T, wrap func(mock api.Server) api.Server) {
 	if !reflect.DeepEqual(mock.SyncStatusAnswer, syncSt) {
 		t.Errorf("expected: %#v\ngot: %#v", mock.SyncStatusAnswer, syncSt)
 	}
+
+	if err := client.PinRevision(ctx, "abcdef0"); err != nil {
+		t.Error(err)
+	}
+	mock.PinRevisionError = fmt.Errorf("pin revision error")
+	if err := client.PinRevision(ctx, ""); err == nil {
+		t.Error("expected error from PinRevision, got nil")
+	}
+
+	pending, err := client.ListPendingUpdates(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(pendingAnswer, pending) {
+		t.Errorf("expected: %#v\ngot: %#v", pendingAnswer, pending)
+	}
+	mock.ListPendingUpdatesError = fmt.Errorf("list pending updates error")
+	if _, err := client.ListPendingUpdates(ctx); err == nil {
+		t.Error("expected error from ListPendingUpdates, got nil")
+	}
+
+	deferred, err := client.ListDeferredUpdates(ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(deferredAnswer, deferred) {
+		t.Errorf("expected: %#v\ngot: %#v", deferredAnswer, deferred)
+	}
+	mock.ListDeferredUpdatesError = fmt.Errorf("list deferred updates error")
+	if _, err := client.ListDeferredUpdates(ctx); err == nil {
+		t.Error("expected error from ListDeferredUpdates, got nil")
+	}
 }
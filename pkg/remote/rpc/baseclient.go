@@ -8,6 +8,8 @@ import (
 	"github.com/fluxcd/flux/pkg/api"
 	"github.com/fluxcd/flux/pkg/api/v10"
 	"github.com/fluxcd/flux/pkg/api/v11"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 	"github.com/fluxcd/flux/pkg/api/v6"
 	"github.com/fluxcd/flux/pkg/api/v9"
 	"github.com/fluxcd/flux/pkg/job"
@@ -67,3 +69,15 @@ func (bc baseClient) SyncStatus(context.Context, string) ([]string, error) {
 func (bc baseClient) GitRepoConfig(context.Context, bool) (v6.GitConfig, error) {
 	return v6.GitConfig{}, remote.UpgradeNeededError(errors.New("GitRepoConfig method not implemented"))
 }
+
+func (bc baseClient) PinRevision(context.Context, string) error {
+	return remote.UpgradeNeededError(errors.New("PinRevision method not implemented"))
+}
+
+func (bc baseClient) ListPendingUpdates(context.Context) ([]v13.PendingUpdate, error) {
+	return nil, remote.UpgradeNeededError(errors.New("ListPendingUpdates method not implemented"))
+}
+
+func (bc baseClient) ListDeferredUpdates(context.Context) ([]v14.DeferredUpdate, error) {
+	return nil, remote.UpgradeNeededError(errors.New("ListDeferredUpdates method not implemented"))
+}
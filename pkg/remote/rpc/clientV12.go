@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+
+	"github.com/fluxcd/flux/pkg/api/v12"
+	"github.com/fluxcd/flux/pkg/remote"
+)
+
+// RPCClientV12 is the rpc-backed implementation of a server, for
+// talking to remote daemons. This version introduces PinRevision.
+type RPCClientV12 struct {
+	*RPCClientV11
+}
+
+type clientV12 interface {
+	v12.Server
+}
+
+var _ clientV12 = &RPCClientV12{}
+
+// NewClientV12 creates a new rpc-backed implementation of the server.
+func NewClientV12(conn io.ReadWriteCloser) *RPCClientV12 {
+	return &RPCClientV12{NewClientV11(conn)}
+}
+
+func (p *RPCClientV12) PinRevision(ctx context.Context, revision string) error {
+	var resp PinRevisionResponse
+	err := p.client.Call("RPCServer.PinRevision", revision, &resp)
+	if err != nil {
+		if _, ok := err.(rpc.ServerError); !ok && err != nil {
+			err = remote.FatalError{Err: err}
+		}
+	} else if resp.ApplicationError != nil {
+		err = resp.ApplicationError
+	}
+	return err
+}
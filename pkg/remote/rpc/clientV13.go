@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/remote"
+)
+
+// RPCClientV13 is the rpc-backed implementation of a server, for
+// talking to remote daemons. This version introduces
+// ListPendingUpdates.
+type RPCClientV13 struct {
+	*RPCClientV12
+}
+
+type clientV13 interface {
+	v13.Server
+}
+
+var _ clientV13 = &RPCClientV13{}
+
+// NewClientV13 creates a new rpc-backed implementation of the server.
+func NewClientV13(conn io.ReadWriteCloser) *RPCClientV13 {
+	return &RPCClientV13{NewClientV12(conn)}
+}
+
+func (p *RPCClientV13) ListPendingUpdates(ctx context.Context) ([]v13.PendingUpdate, error) {
+	var resp ListPendingUpdatesResponse
+	err := p.client.Call("RPCServer.ListPendingUpdates", struct{}{}, &resp)
+	if err != nil {
+		if _, ok := err.(rpc.ServerError); !ok && err != nil {
+			err = remote.FatalError{Err: err}
+		}
+	} else if resp.ApplicationError != nil {
+		err = resp.ApplicationError
+	}
+	return resp.Result, err
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/rpc"
+
+	"github.com/fluxcd/flux/pkg/api/v14"
+	"github.com/fluxcd/flux/pkg/remote"
+)
+
+// RPCClientV14 is the rpc-backed implementation of a server, for
+// talking to remote daemons. This version introduces
+// ListDeferredUpdates.
+type RPCClientV14 struct {
+	*RPCClientV13
+}
+
+type clientV14 interface {
+	v14.Server
+}
+
+var _ clientV14 = &RPCClientV14{}
+
+// NewClientV14 creates a new rpc-backed implementation of the server.
+func NewClientV14(conn io.ReadWriteCloser) *RPCClientV14 {
+	return &RPCClientV14{NewClientV13(conn)}
+}
+
+func (p *RPCClientV14) ListDeferredUpdates(ctx context.Context) ([]v14.DeferredUpdate, error) {
+	var resp ListDeferredUpdatesResponse
+	err := p.client.Call("RPCServer.ListDeferredUpdates", struct{}{}, &resp)
+	if err != nil {
+		if _, ok := err.(rpc.ServerError); !ok && err != nil {
+			err = remote.FatalError{Err: err}
+		}
+	} else if resp.ApplicationError != nil {
+		err = resp.ApplicationError
+	}
+	return resp.Result, err
+}
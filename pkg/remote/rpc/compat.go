@@ -187,8 +187,9 @@ func listImagesWithOptions(ctx context.Context, client listImagesWithoutOptionsC
 				}
 			}
 			tagPattern := policy.GetTagPattern(p, container.Name)
+			minAge, _ := policy.GetMinAge(p, container.Name)
 			// Create a new container using the same function used in v10
-			newContainer, err := v6.NewContainer(container.Name, alreadySorted(container.Available), container.Current, tagPattern, opts.OverrideContainerFields)
+			newContainer, err := v6.NewContainer(container.Name, alreadySorted(container.Available), container.Current, tagPattern, minAge, opts.OverrideContainerFields)
 			if err != nil {
 				return statuses, err
 			}
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/fluxcd/flux/pkg/api/v10"
+	"github.com/fluxcd/flux/pkg/api/v13"
+	"github.com/fluxcd/flux/pkg/api/v14"
 
 	"github.com/pkg/errors"
 
@@ -220,3 +222,58 @@ func (p *RPCServer) GitRepoConfig(regenerate bool, resp *GitRepoConfigResponse)
 	}
 	return err
 }
+
+type PinRevisionResponse struct {
+	ApplicationError *fluxerr.Error
+}
+
+type ListPendingUpdatesResponse struct {
+	Result           []v13.PendingUpdate
+	ApplicationError *fluxerr.Error
+}
+
+func (p *RPCServer) ListPendingUpdates(_ struct{}, resp *ListPendingUpdatesResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	v, err := p.s.ListPendingUpdates(ctx)
+	resp.Result = v
+	if err != nil {
+		if err, ok := errors.Cause(err).(*fluxerr.Error); ok {
+			resp.ApplicationError = err
+			return nil
+		}
+	}
+	return err
+}
+
+type ListDeferredUpdatesResponse struct {
+	Result           []v14.DeferredUpdate
+	ApplicationError *fluxerr.Error
+}
+
+func (p *RPCServer) ListDeferredUpdates(_ struct{}, resp *ListDeferredUpdatesResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	v, err := p.s.ListDeferredUpdates(ctx)
+	resp.Result = v
+	if err != nil {
+		if err, ok := errors.Cause(err).(*fluxerr.Error); ok {
+			resp.ApplicationError = err
+			return nil
+		}
+	}
+	return err
+}
+
+func (p *RPCServer) PinRevision(revision string, resp *PinRevisionResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	err := p.s.PinRevision(ctx, revision)
+	if err != nil {
+		if err, ok := errors.Cause(err).(*fluxerr.Error); ok {
+			resp.ApplicationError = err
+			return nil
+		}
+	}
+	return err
+}
@@ -35,8 +35,24 @@ func ChangesForPolicyUpdate(workload Workload, update PolicyUpdate) (map[string]
 
 	result := map[string]string{}
 	for pol, val := range add {
-		if policy.Tag(pol) && !policy.NewPattern(val).Valid() {
-			return nil, fmt.Errorf("invalid tag pattern: %q", val)
+		if policy.Tag(pol) {
+			pattern := policy.NewPattern(val)
+			if composite, ok := pattern.(policy.CompositePattern); ok && composite.Err() != nil {
+				return nil, fmt.Errorf("invalid tag pattern: %q: %s", val, composite.Err())
+			}
+			if !pattern.Valid() {
+				return nil, fmt.Errorf("invalid tag pattern: %q", val)
+			}
+		}
+		if policy.MinAge(pol) {
+			if _, err := policy.ParseMinAge(val); err != nil {
+				return nil, fmt.Errorf("invalid minimum age: %q", val)
+			}
+		}
+		if pol == policy.LockedUntil {
+			if _, err := policy.ParseLockedUntil(val); err != nil {
+				return nil, fmt.Errorf("invalid lock expiry: %q", val)
+			}
 		}
 		result[string(pol)] = val
 	}
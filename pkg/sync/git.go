@@ -80,6 +80,51 @@ func (p GitTagSyncProvider) DeleteMarker(ctx context.Context) error {
 	return p.repo.DeleteTag(ctx, p.syncTag)
 }
 
+// GetPin returns the revision of the git commit the pin tag (the
+// sync tag, suffixed with `-pin`) points at, if there is one.
+func (p GitTagSyncProvider) GetPin(ctx context.Context) (string, error) {
+	rev, err := p.repo.Revision(ctx, p.pinTag())
+	if isUnknownRevision(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if p.verifyTagMode != VerifySignaturesModeNone {
+		if _, err := p.repo.VerifyTag(ctx, p.pinTag()); err != nil {
+			return "", err
+		}
+	}
+	return rev, nil
+}
+
+// UpdatePin moves the pin tag in the upstream repo, or deletes it if
+// given an empty revision.
+func (p GitTagSyncProvider) UpdatePin(ctx context.Context, revision string) error {
+	if revision == "" {
+		if pinned, err := p.GetPin(ctx); err != nil || pinned == "" {
+			return err
+		}
+		return p.repo.DeleteTag(ctx, p.pinTag())
+	}
+	checkout, err := p.repo.Clone(ctx, p.config)
+	if err != nil {
+		return err
+	}
+	defer checkout.Clean()
+	return checkout.MoveTagAndPush(ctx, git.TagAction{
+		Tag:        p.pinTag(),
+		Revision:   revision,
+		Message:    "Pinned revision",
+		SigningKey: p.signingKey,
+	})
+}
+
+func (p GitTagSyncProvider) pinTag() string {
+	return p.syncTag + "-pin"
+}
+
 func isUnknownRevision(err error) bool {
 	return err != nil &&
 		(strings.Contains(err.Error(), "unknown revision or path not in the working tree.") ||
@@ -0,0 +1,74 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/flux/pkg/cluster/kubernetes/testfiles"
+	"github.com/fluxcd/flux/pkg/git"
+	"github.com/fluxcd/flux/pkg/git/gittest"
+)
+
+func TestGitTagSyncProvider_Pin(t *testing.T) {
+	repo, cleanup := gittest.Repo(t, testfiles.Files)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := repo.Ready(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config := git.Config{
+		Branch:    "master",
+		NotesRef:  "flux",
+		UserName:  "example",
+		UserEmail: "example@example.com",
+	}
+	provider, err := NewGitTagSyncProvider(repo, "flux-sync", "", VerifySignaturesModeNone, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinned, err := provider.GetPin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pinned != "" {
+		t.Fatalf("expected no pin, got %q", pinned)
+	}
+
+	head, err := repo.BranchHead(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.UpdatePin(ctx, head); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pinned, err = provider.GetPin(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pinned != head {
+		t.Errorf("expected pin at %s, got %q", head, pinned)
+	}
+
+	// Unpinning twice should be fine
+	for i := 0; i < 2; i++ {
+		if err := provider.UpdatePin(ctx, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pinned, err = provider.GetPin(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pinned != "" {
+		t.Errorf("expected no pin after unpinning, got %q", pinned)
+	}
+}
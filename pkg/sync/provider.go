@@ -55,6 +55,12 @@ type State interface {
 	UpdateMarker(ctx context.Context, revision string) error
 	// DeleteMarker removes the high water mark
 	DeleteMarker(ctx context.Context) error
+	// GetPin fetches the revision the cluster is pinned to, returning
+	// an empty string if it is not pinned.
+	GetPin(ctx context.Context) (string, error)
+	// UpdatePin records the revision the cluster is pinned to, or
+	// removes the pin if given an empty string.
+	UpdatePin(ctx context.Context, revision string) error
 	// String returns a string representation of where the state is
 	// recorded (e.g., for referring to it in logs)
 	String() string
@@ -11,7 +11,10 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-const syncMarkerKey = "flux.weave.works/sync-hwm"
+const (
+	syncMarkerKey = "flux.weave.works/sync-hwm"
+	syncPinKey    = "flux.weave.works/sync-pin"
+)
 
 // NativeSyncProvider keeps information related to the native state of a sync marker stored in a "native" kubernetes resource.
 type NativeSyncProvider struct {
@@ -67,8 +70,27 @@ func (p NativeSyncProvider) DeleteMarker(ctx context.Context) error {
 	return p.setRevision("")
 }
 
+// GetPin gets the revision the cluster is pinned to, if any.
+func (p NativeSyncProvider) GetPin(ctx context.Context) (string, error) {
+	resource, err := p.resourceAPI.Get(p.resourceName, meta_v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return resource.Annotations[syncPinKey], nil
+}
+
+// UpdatePin records the revision the cluster is pinned to; an empty
+// revision removes the pin.
+func (p NativeSyncProvider) UpdatePin(ctx context.Context, revision string) error {
+	return p.setAnnotation(syncPinKey, revision)
+}
+
 func (p NativeSyncProvider) setRevision(revision string) error {
-	jsonPatch, err := json.Marshal(patch(revision))
+	return p.setAnnotation(syncMarkerKey, revision)
+}
+
+func (p NativeSyncProvider) setAnnotation(key, value string) error {
+	jsonPatch, err := json.Marshal(patch(key, value))
 	if err != nil {
 		return err
 	}
@@ -81,11 +103,11 @@ func (p NativeSyncProvider) setRevision(revision string) error {
 	return err
 }
 
-func patch(revision string) map[string]map[string]map[string]string {
+func patch(key, value string) map[string]map[string]map[string]string {
 	return map[string]map[string]map[string]string{
 		"metadata": map[string]map[string]string{
 			"annotations": map[string]string{
-				syncMarkerKey: revision,
+				key: value,
 			},
 		},
 	}
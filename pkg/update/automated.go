@@ -19,10 +19,18 @@ type Change struct {
 	WorkloadID resource.ID
 	Container  resource.Container
 	ImageID    image.Ref
+	// Group is the image group the container is in, if any; the
+	// changes for a group are made all together, or not at all.
+	Group string `json:",omitempty"`
 }
 
 func (a *Automated) Add(service resource.ID, container resource.Container, image image.Ref) {
-	a.Changes = append(a.Changes, Change{service, container, image})
+	a.Changes = append(a.Changes, Change{WorkloadID: service, Container: container, ImageID: image})
+}
+
+// AddToGroup adds a change for a container in an image group.
+func (a *Automated) AddToGroup(group string, service resource.ID, container resource.Container, image image.Ref) {
+	a.Changes = append(a.Changes, Change{WorkloadID: service, Container: container, ImageID: image, Group: group})
 }
 
 func (a *Automated) CalculateRelease(ctx context.Context, rc ReleaseContext, logger log.Logger) ([]*WorkloadUpdate, Result, error) {
@@ -35,16 +43,17 @@ func (a *Automated) CalculateRelease(ctx context.Context, rc ReleaseContext, log
 	}
 
 	result := Result{}
-	updates, err := rc.SelectWorkloads(ctx, result, prefilters, postfilters)
+	candidates, err := rc.SelectWorkloads(ctx, result, prefilters, postfilters)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	a.markSkipped(result)
-	updates, err = a.calculateImageUpdates(rc, updates, result, logger)
+	updates, err := a.calculateImageUpdates(rc, candidates, result, logger)
 	if err != nil {
 		return nil, nil, err
 	}
+	updates = a.holdIncompleteGroups(candidates, updates, result)
 
 	return updates, result, err
 }
@@ -108,10 +117,11 @@ func (a *Automated) calculateImageUpdates(rc ReleaseContext, candidates []*Workl
 					continue
 				}
 
-				// We transplant the tag here, to make sure we keep
-				// the format of the image name as it is in the
-				// resource (e.g., to avoid canonicalising it)
-				newImageID := currentImageID.WithNewTag(change.ImageID.Tag)
+				// We transplant the tag (and digest, if pinned) here,
+				// to make sure we keep the format of the image name
+				// as it is in the resource (e.g., to avoid
+				// canonicalising it)
+				newImageID := currentImageID.WithNewTag(change.ImageID.Tag).WithDigest(change.ImageID.Digest)
 				containerUpdates = append(containerUpdates, ContainerUpdate{
 					Container: container.Name,
 					Current:   currentImageID,
@@ -138,6 +148,84 @@ func (a *Automated) calculateImageUpdates(rc ReleaseContext, candidates []*Workl
 	return updates, nil
 }
 
+// holdIncompleteGroups removes the updates for any image group that
+// would not be updated as a whole, e.g., because one of the workloads
+// is locked, so that the containers in the group stay on the same
+// tag.
+func (a *Automated) holdIncompleteGroups(candidates, updates []*WorkloadUpdate, result Result) []*WorkloadUpdate {
+	type containerKey struct {
+		workload  resource.ID
+		container string
+	}
+	done := map[containerKey]bool{}
+	for _, u := range updates {
+		for _, c := range u.Updates {
+			done[containerKey{u.ResourceID, c.Container}] = true
+		}
+	}
+	// a change that turned out to be no change at all is done too
+	current := map[containerKey]image.Ref{}
+	for _, u := range candidates {
+		for _, c := range u.Resource.Containers() {
+			current[containerKey{u.ResourceID, c.Name}] = c.Image
+		}
+	}
+
+	heldBy := map[string]containerKey{}
+	for _, change := range a.Changes {
+		key := containerKey{change.WorkloadID, change.Container.Name}
+		if change.Group == "" || done[key] {
+			continue
+		}
+		if im, ok := current[key]; ok && im.CanonicalRef() == change.ImageID.CanonicalRef() {
+			continue
+		}
+		if _, ok := heldBy[change.Group]; !ok {
+			heldBy[change.Group] = key
+		}
+	}
+	if len(heldBy) == 0 {
+		return updates
+	}
+	held := map[containerKey]string{}
+	for _, change := range a.Changes {
+		if _, ok := heldBy[change.Group]; ok {
+			held[containerKey{change.WorkloadID, change.Container.Name}] = change.Group
+		}
+	}
+
+	var remaining []*WorkloadUpdate
+	for _, u := range updates {
+		var kept []ContainerUpdate
+		var group string
+		for _, c := range u.Updates {
+			if g, ok := held[containerKey{u.ResourceID, c.Container}]; ok {
+				group = g
+				continue
+			}
+			kept = append(kept, c)
+		}
+		switch {
+		case len(kept) == len(u.Updates):
+			remaining = append(remaining, u)
+		case len(kept) > 0:
+			u.Updates = kept
+			remaining = append(remaining, u)
+			result[u.ResourceID] = WorkloadResult{
+				Status:       ReleaseStatusSuccess,
+				PerContainer: kept,
+			}
+		default:
+			by := heldBy[group]
+			result[u.ResourceID] = WorkloadResult{
+				Status: ReleaseStatusSkipped,
+				Error:  fmt.Sprintf(ImageGroupHeld, group, fmt.Sprintf("%s (container %s)", by.workload, by.container)),
+			}
+		}
+	}
+	return remaining
+}
+
 // workloadMap transposes the changes so they can be looked up by ID
 func (a *Automated) workloadMap() map[resource.ID][]Change {
 	set := map[resource.ID][]Change{}
@@ -3,6 +3,10 @@ package update
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fluxcd/flux/pkg/image"
+	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/resource"
 )
 
@@ -47,3 +51,63 @@ func TestCommitMessage(t *testing.T) {
 		t.Fatalf("Expected git commit message: '%s', was '%s'", expected, actual)
 	}
 }
+
+type testWorkload struct {
+	id         resource.ID
+	containers []resource.Container
+}
+
+func (w testWorkload) ResourceID() resource.ID                   { return w.id }
+func (w testWorkload) Policies() policy.Set                      { return nil }
+func (w testWorkload) Source() string                            { return "" }
+func (w testWorkload) Bytes() []byte                             { return nil }
+func (w testWorkload) Containers() []resource.Container          { return w.containers }
+func (w testWorkload) SetContainerImage(string, image.Ref) error { return nil }
+
+func TestHoldIncompleteGroups(t *testing.T) {
+	api := testWorkload{resource.MakeID("ns", "deployment", "api"), []resource.Container{
+		{Name: "api", Image: mustParseRef("billing/api:v1")},
+		{Name: "sidecar", Image: mustParseRef("envoy:v1")},
+	}}
+	worker := testWorkload{resource.MakeID("ns", "deployment", "worker"), []resource.Container{
+		{Name: "worker", Image: mustParseRef("billing/worker:v2")},
+	}}
+	migrate := resource.MakeID("ns", "cronjob", "migrate")
+
+	automated := Automated{}
+	automated.AddToGroup("billing", api.id, api.containers[0], mustParseRef("billing/api:v2"))
+	automated.Add(api.id, api.containers[1], mustParseRef("envoy:v2"))
+	// already on the tag, so no update needed
+	automated.AddToGroup("billing", worker.id, worker.containers[0], mustParseRef("billing/worker:v2"))
+
+	apiUpdate := func() *WorkloadUpdate {
+		return &WorkloadUpdate{ResourceID: api.id, Resource: api, Updates: []ContainerUpdate{
+			{Container: "api", Current: mustParseRef("billing/api:v1"), Target: mustParseRef("billing/api:v2")},
+			{Container: "sidecar", Current: mustParseRef("envoy:v1"), Target: mustParseRef("envoy:v2")},
+		}}
+	}
+	candidates := []*WorkloadUpdate{apiUpdate(), {ResourceID: worker.id, Resource: worker}}
+	updates := automated.holdIncompleteGroups(candidates, []*WorkloadUpdate{candidates[0]}, Result{})
+	assert.Len(t, updates, 1)
+	assert.Len(t, updates[0].Updates, 2)
+
+	// a member that can't be updated (e.g., because it's locked)
+	// holds back the rest of the group, but not other containers
+	automated.AddToGroup("billing", migrate, resource.Container{Name: "migrate"}, mustParseRef("billing/api:v2"))
+	candidates = []*WorkloadUpdate{apiUpdate(), {ResourceID: worker.id, Resource: worker}}
+	result := Result{}
+	updates = automated.holdIncompleteGroups(candidates, []*WorkloadUpdate{candidates[0]}, result)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, []ContainerUpdate{{Container: "sidecar", Current: mustParseRef("envoy:v1"), Target: mustParseRef("envoy:v2")}}, updates[0].Updates)
+	assert.Equal(t, ReleaseStatusSuccess, result[api.id].Status)
+
+	// and if that's all there was to update, the workload is skipped
+	onlyGroup := apiUpdate()
+	onlyGroup.Updates = onlyGroup.Updates[:1]
+	candidates = []*WorkloadUpdate{onlyGroup, {ResourceID: worker.id, Resource: worker}}
+	result = Result{}
+	updates = automated.holdIncompleteGroups(candidates, []*WorkloadUpdate{onlyGroup}, result)
+	assert.Empty(t, updates)
+	assert.Equal(t, ReleaseStatusSkipped, result[api.id].Status)
+	assert.Contains(t, result[api.id].Error, "image group billing held back")
+}
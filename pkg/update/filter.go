@@ -19,6 +19,7 @@ const (
 	DoesNotUseImage        = "does not use image(s)"
 	ContainerNotFound      = "container(s) not found: %s"
 	ContainerTagMismatch   = "container(s) tag mismatch: %s"
+	ImageGroupHeld         = "image group %s held back, since %s would not be updated"
 )
 
 type SpecificImageFilter struct {
@@ -72,6 +72,20 @@ func FilterAndSortRepositoryMetadata(rm image.RepositoryMetadata, pattern policy
 	return SortImages(filteredImages, pattern), nil
 }
 
+// fetchedOnly returns the metadata given with only the tags that have
+// image metadata. Every tag of an image repository is recorded, but
+// metadata is fetched only for the tags in use; a release, especially
+// a forced one, may look at tags nothing else knows about.
+func fetchedOnly(rm image.RepositoryMetadata) image.RepositoryMetadata {
+	var tags []string
+	for _, tag := range rm.Tags {
+		if _, ok := rm.Images[tag]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return image.RepositoryMetadata{Tags: tags, Images: rm.Images}
+}
+
 // Latest returns the latest image from SortedImageInfos. If no such image exists,
 // returns a zero value and `false`, and the caller can decide whether
 // that's an error or not.
@@ -165,35 +179,42 @@ func FetchImageRepos(reg registry.Registry, cs containers, logger log.Logger) (I
 	return ImageRepos{imageRepos}, nil
 }
 
-// Create a map of image repos to images. It will check that each image exists.
+// Create a map of image repos to images. It will check that each
+// image exists, and include what's known about it (e.g., its digest,
+// so it can be pinned). A digest given with the image is kept.
 func exactImageRepos(reg registry.Registry, images []image.Ref) (ImageRepos, error) {
 	m := imageReposMap{}
 	for _, id := range images {
 		// We must check that the exact images requested actually exist. Otherwise we risk pushing invalid images to git.
-		exist, err := imageExists(reg, id)
-		if err != nil {
-			return ImageRepos{}, errors.Wrap(image.ErrInvalidImageID, err.Error())
-		}
-		if !exist {
+		info, err := reg.GetImage(id.WithDigest(""))
+		if err != nil && !(fluxerr.IsMissing(err) && hasTag(reg, id)) {
 			return ImageRepos{}, errors.Wrap(image.ErrInvalidImageID, fmt.Sprintf("image %q does not exist", id))
 		}
+		info.ID = id.WithDigest("")
+		if id.Digest != "" {
+			info.Digest = id.Digest
+		}
 		m[id.CanonicalName()] = image.RepositoryMetadata{
 			Tags: []string{id.Tag},
 			Images: map[string]image.Info{
-				id.Tag: {ID: id},
+				id.Tag: info,
 			},
 		}
 	}
 	return ImageRepos{m}, nil
 }
 
-// Checks whether the given image exists in the repository.
-// Return true if exist, false otherwise.
-// FIXME(michael): never returns an error; should it?
-func imageExists(reg registry.Registry, imageID image.Ref) (bool, error) {
-	_, err := reg.GetImage(imageID)
+// hasTag reports whether the image's tag is known to exist, though
+// its metadata may not have been fetched, since it is not in use.
+func hasTag(reg registry.Registry, id image.Ref) bool {
+	metadata, err := reg.GetImageRepositoryMetadata(id.Name)
 	if err != nil {
-		return false, nil
+		return false
+	}
+	for _, tag := range metadata.Tags {
+		if tag == id.Tag {
+			return true
+		}
 	}
-	return true, nil
+	return false
 }
@@ -1,11 +1,13 @@
 package update
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	fluxerr "github.com/fluxcd/flux/pkg/errors"
 	"github.com/fluxcd/flux/pkg/image"
 	"github.com/fluxcd/flux/pkg/policy"
 	"github.com/fluxcd/flux/pkg/registry/mock"
@@ -131,3 +133,76 @@ func mustParseName(im string) image.Name {
 	}
 	return ref.Name
 }
+
+func TestExactImageRepos(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	explicit := "sha256:" + strings.Repeat("b", 64)
+	registry := &mock.Registry{Images: []image.Info{
+		{ID: name.ToRef("v2"), Digest: digest, CreatedAt: time.Now()},
+	}}
+
+	repos, err := exactImageRepos(registry, []image.Ref{name.ToRef("v2")})
+	assert.NoError(t, err)
+	latest, ok := getFilteredAndSortedImagesFromRepos(t, name.String(), repos).Latest()
+	assert.True(t, ok)
+	assert.Equal(t, digest, latest.Digest, "digest of the image should be known, so it can be pinned")
+
+	repos, err = exactImageRepos(registry, []image.Ref{name.ToRef("v2").WithDigest(explicit)})
+	assert.NoError(t, err)
+	latest, ok = getFilteredAndSortedImagesFromRepos(t, name.String(), repos).Latest()
+	assert.True(t, ok)
+	assert.Equal(t, name.ToRef("v2"), latest.ID)
+	assert.Equal(t, explicit, latest.Digest, "digest given explicitly should be kept")
+
+	_, err = exactImageRepos(registry, []image.Ref{name.ToRef("v3")})
+	assert.Error(t, err)
+}
+
+// unfetchedRegistry knows of tags whose metadata has not been
+// fetched, since they are not in use.
+type unfetchedRegistry struct {
+	mock.Registry
+	unfetched []string
+}
+
+func (r *unfetchedRegistry) GetImageRepositoryMetadata(id image.Name) (image.RepositoryMetadata, error) {
+	metadata, err := r.Registry.GetImageRepositoryMetadata(id)
+	metadata.Tags = append(metadata.Tags, r.unfetched...)
+	return metadata, err
+}
+
+func (r *unfetchedRegistry) GetImage(id image.Ref) (image.Info, error) {
+	info, err := r.Registry.GetImage(id)
+	if err != nil {
+		return info, &fluxerr.Error{Type: fluxerr.Missing, Err: err}
+	}
+	return info, nil
+}
+
+func TestReleaseUnfetchedTag(t *testing.T) {
+	registry := &unfetchedRegistry{Registry: mock.Registry{Images: infos}, unfetched: []string{"v3"}}
+
+	// A forced release considers every tag, including those that
+	// haven't been fetched
+	metadata, err := registry.GetImageRepositoryMetadata(name.Name)
+	assert.NoError(t, err)
+	_, err = FilterAndSortRepositoryMetadata(metadata, policy.PatternAll)
+	assert.Error(t, err)
+	images, err := FilterAndSortRepositoryMetadata(fetchedOnly(metadata), policy.PatternAll)
+	assert.NoError(t, err)
+	latest, ok := images.Latest()
+	assert.True(t, ok)
+	assert.Equal(t, name.ToRef("v2"), latest.ID)
+
+	// An image with a tag that hasn't been fetched can be released
+	// by name
+	repos, err := exactImageRepos(registry, []image.Ref{name.ToRef("v3")})
+	assert.NoError(t, err)
+	latest, ok = getFilteredAndSortedImagesFromRepos(t, name.String(), repos).Latest()
+	assert.True(t, ok)
+	assert.Equal(t, name.ToRef("v3"), latest.ID)
+	assert.Equal(t, "", latest.Digest)
+
+	_, err = exactImageRepos(registry, []image.Ref{name.ToRef("v4")})
+	assert.Error(t, err)
+}
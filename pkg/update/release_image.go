@@ -186,6 +186,8 @@ func (s ReleaseImageSpec) calculateImageUpdates(rc ReleaseContext, candidates []
 	// Compile an `ImageRepos` of all relevant images
 	var imageRepos ImageRepos
 	var singleRepo image.CanonicalName
+	// a digest given with the image to release, which it's pinned to
+	var explicitDigest string
 	var err error
 
 	switch s.ImageSpec {
@@ -196,6 +198,7 @@ func (s ReleaseImageSpec) calculateImageUpdates(rc ReleaseContext, candidates []
 		ref, err = s.ImageSpec.AsRef()
 		if err == nil {
 			singleRepo = ref.CanonicalName()
+			explicitDigest = ref.Digest
 			// FIXME(fons): we probably want to allow this operation even if image
 			//              scanning is disabled. We could either avoid the validation
 			//              or use an uncached registry.
@@ -238,7 +241,7 @@ func (s ReleaseImageSpec) calculateImageUpdates(rc ReleaseContext, candidates []
 				}
 			}
 
-			metadata := imageRepos.GetRepositoryMetadata(currentImageID.Name)
+			metadata := fetchedOnly(imageRepos.GetRepositoryMetadata(currentImageID.Name))
 			sortedImages, err := FilterAndSortRepositoryMetadata(metadata, tagPattern)
 			if err != nil {
 				// missing image repository metadata
@@ -255,7 +258,18 @@ func (s ReleaseImageSpec) calculateImageUpdates(rc ReleaseContext, candidates []
 				continue
 			}
 
-			if currentImageID == latestImage.ID {
+			// Images already given with a digest stay pinned, unless
+			// there's a policy saying otherwise; images released with
+			// a digest are pinned. A pinned image needs updating if its
+			// tag has moved to another digest, and an image no longer
+			// to be pinned needs its digest removed.
+			pin, ok := policy.GetPinDigest(u.Resource.Policies(), container.Name)
+			if !ok {
+				pin = currentImageID.Digest != ""
+			}
+			pin = pin || (explicitDigest != "" && currentImageID.CanonicalName() == singleRepo)
+			if currentImageID.WithDigest("") == latestImage.ID &&
+				((!pin && currentImageID.Digest == "") || (pin && (latestImage.Digest == "" || currentImageID.Digest == latestImage.Digest))) {
 				ignoredOrSkipped = ReleaseStatusSkipped
 				continue
 			}
@@ -264,6 +278,14 @@ func (s ReleaseImageSpec) calculateImageUpdates(rc ReleaseContext, candidates []
 			// appears in the manifest, whereas what we have is the
 			// canonical form.
 			newImageID := currentImageID.WithNewTag(latestImage.ID.Tag)
+			if pin {
+				if latestImage.Digest == "" {
+					// we can't say what to pin it to
+					ignoredOrSkipped = ReleaseStatusUnknown
+					continue
+				}
+				newImageID = newImageID.WithDigest(latestImage.Digest)
+			}
 			containerUpdates = append(containerUpdates, ContainerUpdate{
 				Container: container.Name,
 				Current:   currentImageID,